@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+	"github.com/seb07-cloud/pim-tui/internal/config"
+	"github.com/seb07-cloud/pim-tui/internal/export"
+	"github.com/seb07-cloud/pim-tui/internal/history"
+)
+
+// runExport implements `pim-tui export`, writing history or the eligibility
+// inventory without starting the TUI. Returns the process exit code.
+func runExport(args []string, cfg config.Config) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	what := fs.String("what", "history", "what to export: history or inventory")
+	formatName := fs.String("format", "", "output format: csv, json or md (default: from --output extension, else csv)")
+	output := fs.String("output", "", "output file path, or - for stdout (default: dated file in the current directory)")
+	from := fs.String("from", "", "only history on or after this date (YYYY-MM-DD)")
+	to := fs.String("to", "", "only history on or before this date (YYYY-MM-DD)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pim-tui export [flags]\n\nExport activation history or the eligibility inventory.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	format := export.FormatCSV
+	switch {
+	case *formatName != "":
+		f, err := export.ParseFormat(*formatName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		format = f
+	case *output != "" && *output != "-":
+		if ext := filepath.Ext(*output); ext != "" {
+			if f, err := export.ParseFormat(ext); err == nil {
+				format = f
+			}
+		}
+	}
+
+	var write func(io.Writer) error
+	var count int
+	switch *what {
+	case "history":
+		filter, err := export.DateRange(*from, *to)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		entries, err := loadHistory(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to load history: %v\n", err)
+			return 1
+		}
+		entries = filter.Apply(entries)
+		count = len(entries)
+		write = func(w io.Writer) error { return export.WriteHistory(w, format, entries) }
+	case "inventory":
+		items, err := loadInventory()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		count = len(items)
+		write = func(w io.Writer) error { return export.WriteInventory(w, format, items) }
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown --what %q (want history or inventory)\n", *what)
+		return 2
+	}
+
+	if *output == "-" {
+		if err := write(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	path := *output
+	if path == "" {
+		path = export.DefaultFileName(*what, format, time.Now())
+	}
+	written, err := export.WriteFile(path, write)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write %s: %v\n", path, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d %s entries to %s\n", count, *what, written)
+	return 0
+}
+
+func loadHistory(cfg config.Config) ([]history.Entry, error) {
+	path, err := history.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return history.NewStore(path).Load(history.Retention{
+		MaxAge:     time.Duration(cfg.HistoryRetentionDays) * 24 * time.Hour,
+		MaxEntries: cfg.HistoryMaxEntries,
+	})
+}
+
+// loadInventory fetches eligibilities using the Azure CLI session. A source that
+// fails is reported and skipped so the rest can still be exported.
+func loadInventory() ([]export.InventoryItem, error) {
+	client, err := azure.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	roles, err := client.GetRoles(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load roles: %v\n", err)
+	}
+	groups, err := client.GetGroups(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load groups: %v\n", err)
+	}
+	subs, err := client.GetLighthouseSubscriptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load subscriptions: %v\n", err)
+	}
+	return export.Inventory(roles, groups, subs), nil
+}
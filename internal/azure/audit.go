@@ -0,0 +1,244 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// scheduleInfo is the schedule of a Graph assignment schedule request
+type scheduleInfo struct {
+	StartDateTime *time.Time `json:"startDateTime"`
+	Expiration    struct {
+		EndDateTime *time.Time `json:"endDateTime"`
+	} `json:"expiration"`
+}
+
+// graphScheduleRequest is a roleAssignmentScheduleRequest or group assignmentScheduleRequest
+type graphScheduleRequest struct {
+	ID               string       `json:"id"`
+	Action           string       `json:"action"`
+	Status           string       `json:"status"`
+	CreatedDateTime  time.Time    `json:"createdDateTime"`
+	Justification    string       `json:"justification"`
+	DirectoryScopeID string       `json:"directoryScopeId"`
+	GroupID          string       `json:"groupId"`
+	ScheduleInfo     scheduleInfo `json:"scheduleInfo"`
+	RoleDefinition   *struct {
+		DisplayName string `json:"displayName"`
+	} `json:"roleDefinition"`
+	Group *struct {
+		DisplayName string `json:"displayName"`
+	} `json:"group"`
+}
+
+type graphScheduleRequestResponse struct {
+	Value    []graphScheduleRequest `json:"value"`
+	NextLink string                 `json:"@odata.nextLink"`
+}
+
+// armScheduleRequestResponse is the ARM roleAssignmentScheduleRequests list response
+type armScheduleRequestResponse struct {
+	Value []struct {
+		Name       string `json:"name"`
+		Properties struct {
+			RequestType      string    `json:"requestType"`
+			Status           string    `json:"status"`
+			CreatedOn        time.Time `json:"createdOn"`
+			Justification    string    `json:"justification"`
+			Scope            string    `json:"scope"`
+			RoleDefinitionID string    `json:"roleDefinitionId"`
+			ScheduleInfo     struct {
+				StartDateTime *time.Time `json:"startDateTime"`
+				Expiration    struct {
+					EndDateTime *time.Time `json:"endDateTime"`
+				} `json:"expiration"`
+			} `json:"scheduleInfo"`
+			ExpandedProperties *struct {
+				RoleDefinition *struct {
+					DisplayName string `json:"displayName"`
+				} `json:"roleDefinition"`
+				Scope *struct {
+					DisplayName string `json:"displayName"`
+				} `json:"scope"`
+			} `json:"expandedProperties"`
+		} `json:"properties"`
+	} `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// GetRoleRequestHistory returns the current user's Entra role requests created since since
+func (c *Client) GetRoleRequestHistory(ctx context.Context, since time.Time) ([]AuditRecord, error) {
+	reqURL := graphBaseURL + "/roleManagement/directory/roleAssignmentScheduleRequests/filterByCurrentUser(on='principal')?" +
+		graphHistoryQuery("roleDefinition", since)
+	requests, err := c.listGraphScheduleRequests(ctx, reqURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role request history: %w", err)
+	}
+
+	records := make([]AuditRecord, 0, len(requests))
+	for _, r := range requests {
+		record := r.record("role")
+		record.Scope = r.DirectoryScopeID
+		if r.RoleDefinition != nil {
+			record.Name = r.RoleDefinition.DisplayName
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// GetGroupRequestHistory returns the current user's PIM for Groups requests created since since
+func (c *Client) GetGroupRequestHistory(ctx context.Context, since time.Time) ([]AuditRecord, error) {
+	reqURL := graphBaseURL + "/identityGovernance/privilegedAccess/group/assignmentScheduleRequests/filterByCurrentUser(on='principal')?" +
+		graphHistoryQuery("group", since)
+	requests, err := c.listGraphScheduleRequests(ctx, reqURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group request history: %w", err)
+	}
+
+	records := make([]AuditRecord, 0, len(requests))
+	for _, r := range requests {
+		record := r.record("group")
+		record.Scope = r.GroupID
+		record.Name = r.GroupID
+		if r.Group != nil && r.Group.DisplayName != "" {
+			record.Name = r.Group.DisplayName
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// GetAzureRoleRequestHistory returns the current user's Azure RBAC role requests created since since.
+// ARM does not filter by date, so older requests are dropped client-side.
+func (c *Client) GetAzureRoleRequestHistory(ctx context.Context, since time.Time) ([]AuditRecord, error) {
+	params := url.Values{}
+	params.Set("api-version", "2020-10-01")
+	params.Set("$filter", "asRequestor()")
+	reqURL := "https://management.azure.com/providers/Microsoft.Authorization/roleAssignmentScheduleRequests?" + params.Encode()
+
+	var records []AuditRecord
+	for reqURL != "" {
+		data, err := c.armRequest(ctx, "GET", reqURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Azure role request history: %w", err)
+		}
+		var result armScheduleRequestResponse
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse Azure role request history: %w", err)
+		}
+
+		for _, r := range result.Value {
+			p := r.Properties
+			if p.CreatedOn.Before(since) {
+				continue
+			}
+			name := lastSegment(p.RoleDefinitionID)
+			scope := p.Scope
+			if p.ExpandedProperties != nil {
+				if p.ExpandedProperties.RoleDefinition != nil && p.ExpandedProperties.RoleDefinition.DisplayName != "" {
+					name = p.ExpandedProperties.RoleDefinition.DisplayName
+				}
+				if p.ExpandedProperties.Scope != nil && p.ExpandedProperties.Scope.DisplayName != "" {
+					name += " @ " + p.ExpandedProperties.Scope.DisplayName
+				}
+			}
+			records = append(records, AuditRecord{
+				RequestID:     r.Name,
+				Source:        "azure-role",
+				Action:        p.RequestType,
+				Time:          p.CreatedOn,
+				Name:          name,
+				Scope:         scope,
+				Status:        p.Status,
+				Justification: p.Justification,
+				StartsAt:      p.ScheduleInfo.StartDateTime,
+				ExpiresAt:     p.ScheduleInfo.Expiration.EndDateTime,
+			})
+		}
+		reqURL = result.NextLink
+	}
+	return records, nil
+}
+
+// GetRequestHistory fetches Entra role, group and Azure role request history in parallel.
+// Records from the sources that succeeded are returned oldest first, together with an
+// error describing any source that failed.
+func (c *Client) GetRequestHistory(ctx context.Context, since time.Time) ([]AuditRecord, error) {
+	fetchers := []func(context.Context, time.Time) ([]AuditRecord, error){
+		c.GetRoleRequestHistory,
+		c.GetGroupRequestHistory,
+		c.GetAzureRoleRequestHistory,
+	}
+
+	results := make([][]AuditRecord, len(fetchers))
+	errs := make([]error, len(fetchers))
+	var wg sync.WaitGroup
+	for i, fetch := range fetchers {
+		wg.Add(1)
+		go func(i int, fetch func(context.Context, time.Time) ([]AuditRecord, error)) {
+			defer wg.Done()
+			results[i], errs[i] = fetch(ctx, since)
+		}(i, fetch)
+	}
+	wg.Wait()
+
+	var records []AuditRecord
+	for _, r := range results {
+		records = append(records, r...)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, errors.Join(errs...)
+}
+
+func (c *Client) listGraphScheduleRequests(ctx context.Context, reqURL string) ([]graphScheduleRequest, error) {
+	var all []graphScheduleRequest
+	for reqURL != "" {
+		data, err := c.graphRequest(ctx, "GET", reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result graphScheduleRequestResponse
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		all = append(all, result.Value...)
+		reqURL = result.NextLink
+	}
+	return all, nil
+}
+
+// graphHistoryQuery builds the $expand/$filter query for schedule request history
+func graphHistoryQuery(expand string, since time.Time) string {
+	params := url.Values{}
+	params.Set("$expand", expand)
+	if !since.IsZero() {
+		params.Set("$filter", "createdDateTime ge "+since.UTC().Format(time.RFC3339))
+	}
+	return params.Encode()
+}
+
+func (r graphScheduleRequest) record(source string) AuditRecord {
+	return AuditRecord{
+		RequestID:     r.ID,
+		Source:        source,
+		Action:        r.Action,
+		Time:          r.CreatedDateTime,
+		Status:        r.Status,
+		Justification: r.Justification,
+		StartsAt:      r.ScheduleInfo.StartDateTime,
+		ExpiresAt:     r.ScheduleInfo.Expiration.EndDateTime,
+	}
+}
+
+// lastSegment returns the part of an ARM resource ID after the final slash
+func lastSegment(id string) string {
+	return id[strings.LastIndex(id, "/")+1:]
+}
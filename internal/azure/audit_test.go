@@ -0,0 +1,117 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGetRequestHistory tests reading and merging role, group and Azure role request history
+func TestGetRequestHistory(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var roleFilter string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "roleAssignmentScheduleRequests/filterByCurrentUser"):
+			if r.URL.Query().Get("page") == "2" {
+				w.Write([]byte(`{"value": [{"id": "r2", "action": "selfDeactivate", "status": "Revoked", "createdDateTime": "2026-01-03T10:00:00Z", "roleDefinition": {"displayName": "Global Reader"}}]}`))
+				return
+			}
+			roleFilter = r.URL.Query().Get("$filter")
+			w.Write([]byte(`{
+				"value": [{"id": "r1", "action": "selfActivate", "status": "Provisioned", "createdDateTime": "2026-01-02T10:00:00Z",
+					"justification": "ticket 1", "directoryScopeId": "/",
+					"roleDefinition": {"displayName": "Global Reader"},
+					"scheduleInfo": {"startDateTime": "2026-01-02T10:00:00Z", "expiration": {"endDateTime": "2026-01-02T18:00:00Z"}}}],
+				"@odata.nextLink": "https://graph.microsoft.com/v1.0/roleManagement/directory/roleAssignmentScheduleRequests/filterByCurrentUser(on='principal')?page=2"
+			}`))
+		case strings.Contains(r.URL.Path, "group/assignmentScheduleRequests"):
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": {"code": "Forbidden"}}`))
+		case strings.Contains(r.URL.Path, "Microsoft.Authorization/roleAssignmentScheduleRequests"):
+			if got := r.URL.Query().Get("$filter"); got != "asRequestor()" {
+				t.Errorf("ARM $filter = %q, want asRequestor()", got)
+			}
+			w.Write([]byte(`{"value": [
+				{"name": "a1", "properties": {"requestType": "SelfActivate", "status": "Provisioned", "createdOn": "2026-01-04T09:00:00Z",
+					"scope": "/subscriptions/s1", "roleDefinitionId": "/providers/Microsoft.Authorization/roleDefinitions/reader-id",
+					"expandedProperties": {"roleDefinition": {"displayName": "Reader"}, "scope": {"displayName": "Prod"}}}},
+				{"name": "a0", "properties": {"requestType": "SelfActivate", "status": "Provisioned", "createdOn": "2025-12-01T09:00:00Z",
+					"scope": "/subscriptions/s1", "roleDefinitionId": "/providers/Microsoft.Authorization/roleDefinitions/old"}}
+			]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	client.httpClient = &http.Client{
+		Transport: &testTransport{
+			baseURL:    server.URL,
+			realClient: http.DefaultTransport,
+		},
+		Timeout: 5 * time.Second,
+	}
+
+	records, err := client.GetRequestHistory(context.Background(), since)
+	if err == nil || !strings.Contains(err.Error(), "group request history") {
+		t.Errorf("GetRequestHistory() error = %v, want group history failure", err)
+	}
+	if !strings.Contains(roleFilter, "createdDateTime ge 2026-01-01T00:00:00Z") {
+		t.Errorf("role $filter = %q, want createdDateTime filter", roleFilter)
+	}
+
+	wantIDs := []string{"r1", "r2", "a1"}
+	if len(records) != len(wantIDs) {
+		t.Fatalf("records = %+v, want %d records", records, len(wantIDs))
+	}
+	for i, id := range wantIDs {
+		if records[i].RequestID != id {
+			t.Errorf("records[%d].RequestID = %q, want %q", i, records[i].RequestID, id)
+		}
+	}
+
+	if r := records[0]; r.Source != "role" || r.Name != "Global Reader" || r.ExpiresAt == nil || r.Failed() {
+		t.Errorf("role record = %+v", r)
+	}
+	if records[1].Failed() {
+		t.Errorf("records[1].Failed() = true for status %q", records[1].Status)
+	}
+	if r := records[2]; r.Source != "azure-role" || r.Name != "Reader @ Prod" || r.Scope != "/subscriptions/s1" {
+		t.Errorf("azure role record = %+v", r)
+	}
+}
+
+// TestAuditRecordFailed tests which request statuses count as failures
+func TestAuditRecordFailed(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{"Provisioned", false},
+		{"PendingApproval", false},
+		{"Granted", false},
+		{"Revoked", false},
+		{"Denied", true},
+		{"AdminDenied", true},
+		{"Failed", true},
+		{"FailedAsResourceIsLocked", true},
+		{"Canceled", true},
+		{"TimedOut", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if got := (AuditRecord{Status: tt.status}).Failed(); got != tt.want {
+				t.Errorf("Failed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/seb07-cloud/pim-tui/internal/fsutil"
+)
+
+// Metadata cache key prefixes
+const (
+	cacheGroupName    = "group:"     // group ID -> display name
+	cacheSubTenant    = "subtenant:" // subscription ID -> home tenant ID
+	cacheTenantName   = "tenant:"    // tenant ID -> display name
+	metadataCacheFile = "metadata-%s.json"
+)
+
+type cacheEntry struct {
+	Value   string    `json:"value"`
+	Fetched time.Time `json:"fetched"`
+}
+
+// MetadataCache persists display names that rarely change (groups, subscription
+// tenants, tenant names) so refreshes only resolve new IDs. One file is kept per
+// signed-in tenant under the user cache directory. A nil *MetadataCache is valid
+// and caches nothing.
+type MetadataCache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	entries map[string]cacheEntry
+	dirty   bool
+}
+
+// OpenMetadataCache loads the metadata cache for tenantID from the user cache
+// directory. A missing or unreadable cache file starts an empty cache.
+func OpenMetadataCache(tenantID string, ttl time.Duration) (*MetadataCache, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cache dir: %w", err)
+	}
+	path := filepath.Join(cacheDir, "pim-tui", fmt.Sprintf(metadataCacheFile, tenantID))
+	return newMetadataCache(path, ttl), nil
+}
+
+func newMetadataCache(path string, ttl time.Duration) *MetadataCache {
+	c := &MetadataCache{
+		path:    path,
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		// A corrupt file is treated as empty and overwritten on next save
+		_ = json.Unmarshal(data, &c.entries)
+	}
+	return c
+}
+
+// Get returns the cached value for key if present and younger than the TTL
+func (c *MetadataCache) Get(key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.Fetched) > c.ttl {
+		return "", false
+	}
+	return entry.Value, true
+}
+
+// Set stores value for key; empty values are not cached
+func (c *MetadataCache) Set(key, value string) {
+	if c == nil || value == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{Value: value, Fetched: time.Now()}
+	c.dirty = true
+}
+
+// Save writes the cache to disk if it changed, dropping expired entries
+func (c *MetadataCache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+
+	for key, entry := range c.entries {
+		if time.Since(entry.Fetched) > c.ttl {
+			delete(c.entries, key)
+		}
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFileAtomic(c.path, data); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// Clear drops all entries and removes the cache file
+func (c *MetadataCache) Clear() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cacheEntry)
+	c.dirty = false
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Len returns the number of cached entries
+func (c *MetadataCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// SetMetadataCache makes the client consult cache before resolving names.
+// Pass nil to disable caching.
+func (c *Client) SetMetadataCache(cache *MetadataCache) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache = cache
+}
+
+// ClearMetadataCache forgets all cached names so the next refresh re-resolves them
+func (c *Client) ClearMetadataCache() error {
+	return c.metadataCache().Clear()
+}
+
+func (c *Client) metadataCache() *MetadataCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache
+}
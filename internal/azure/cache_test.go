@@ -0,0 +1,126 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMetadataCacheGetSet tests TTL expiry and that empty values are not cached
+func TestMetadataCacheGetSet(t *testing.T) {
+	cache := newMetadataCache(filepath.Join(t.TempDir(), "metadata.json"), time.Hour)
+
+	cache.Set(cacheGroupName+"g1", "Admins")
+	cache.Set(cacheGroupName+"g2", "")
+	cache.entries[cacheTenantName+"old"] = cacheEntry{Value: "Old Tenant", Fetched: time.Now().Add(-2 * time.Hour)}
+
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantHit bool
+	}{
+		{"fresh entry", cacheGroupName + "g1", "Admins", true},
+		{"empty value not cached", cacheGroupName + "g2", "", false},
+		{"expired entry", cacheTenantName + "old", "", false},
+		{"unknown key", cacheGroupName + "missing", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hit := cache.Get(tt.key)
+			if got != tt.want || hit != tt.wantHit {
+				t.Errorf("Get(%q) = %q, %v, want %q, %v", tt.key, got, hit, tt.want, tt.wantHit)
+			}
+		})
+	}
+}
+
+// TestMetadataCachePersists tests that saved entries are reloaded and Clear removes the file
+func TestMetadataCachePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pim-tui", "metadata-tenant.json")
+	cache := newMetadataCache(path, time.Hour)
+	cache.Set(cacheSubTenant+"sub1", "tenant-1")
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded := newMetadataCache(path, time.Hour)
+	if got, ok := reloaded.Get(cacheSubTenant + "sub1"); !ok || got != "tenant-1" {
+		t.Errorf("reloaded Get() = %q, %v, want %q, true", got, ok, "tenant-1")
+	}
+
+	if err := reloaded.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if reloaded.Len() != 0 {
+		t.Errorf("Len() after Clear = %d, want 0", reloaded.Len())
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("cache file still exists after Clear, stat err = %v", err)
+	}
+}
+
+// TestNilMetadataCache tests that a nil cache is safe to use
+func TestNilMetadataCache(t *testing.T) {
+	var cache *MetadataCache
+	cache.Set("k", "v")
+	if _, ok := cache.Get("k"); ok {
+		t.Error("nil cache Get() hit, want miss")
+	}
+	if err := cache.Save(); err != nil {
+		t.Errorf("nil cache Save() error = %v", err)
+	}
+	if err := cache.Clear(); err != nil {
+		t.Errorf("nil cache Clear() error = %v", err)
+	}
+}
+
+// TestGetTenantNameByIDUsesCache tests that cached tenant names skip the request
+func TestGetTenantNameByIDUsesCache(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"displayName": "Contoso"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	client.httpClient = &http.Client{
+		Transport: &testTransport{
+			baseURL:    server.URL,
+			realClient: http.DefaultTransport,
+		},
+		Timeout: 5 * time.Second,
+	}
+	client.SetMetadataCache(newMetadataCache(filepath.Join(t.TempDir(), "metadata.json"), time.Hour))
+
+	for i := 0; i < 2; i++ {
+		name, err := client.getTenantNameByID(context.Background(), "t1")
+		if err != nil {
+			t.Fatalf("getTenantNameByID() error = %v", err)
+		}
+		if name != "Contoso" {
+			t.Errorf("getTenantNameByID() = %q, want %q", name, "Contoso")
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+
+	if err := client.ClearMetadataCache(); err != nil {
+		t.Fatalf("ClearMetadataCache() error = %v", err)
+	}
+	if _, err := client.getTenantNameByID(context.Background(), "t1"); err != nil {
+		t.Fatalf("getTenantNameByID() after clear error = %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("requests after clear = %d, want 2", got)
+	}
+}
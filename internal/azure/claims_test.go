@@ -0,0 +1,109 @@
+package azure
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseClaimsChallenge(t *testing.T) {
+	claimsJSON := `{"access_token":{"acrs":{"essential":true,"value":"c1"}}}`
+	encoded := base64.StdEncoding.EncodeToString([]byte(claimsJSON))
+
+	tests := []struct {
+		name       string
+		header     http.Header
+		body       string
+		wantClaims string
+		wantOK     bool
+	}{
+		{
+			name: "WWW-Authenticate header with base64 claims",
+			header: http.Header{"Www-Authenticate": []string{
+				`Bearer realm="", authorization_uri="https://login.microsoftonline.com/common/oauth2/authorize", error="insufficient_claims", claims="` + encoded + `"`,
+			}},
+			body:       `{"error":{"code":"InvalidAuthenticationToken"}}`,
+			wantClaims: claimsJSON,
+			wantOK:     true,
+		},
+		{
+			name:       "ARM error payload with URL-encoded claims",
+			header:     http.Header{},
+			body:       `{"error":{"code":"RoleAssignmentRequestAcrsValidationFailed","message":"The Role assignment request ACRS validation failed with 'https://login.microsoftonline.com/common/oauth2/authorize?client_id=x&claims=%7B%22access_token%22%3A%7B%22acrs%22%3A%7B%22essential%22%3Atrue%2C%22value%22%3A%22c1%22%7D%7D%7D'."}}`,
+			wantClaims: claimsJSON,
+			wantOK:     true,
+		},
+		{
+			name:   "plain API error has no challenge",
+			header: http.Header{},
+			body:   `{"error":{"code":"RoleAssignmentExists","message":"The Role assignment already exists."}}`,
+			wantOK: false,
+		},
+		{
+			name:   "non-JSON body has no challenge",
+			header: http.Header{},
+			body:   `Bad Gateway`,
+			wantOK: false,
+		},
+		{
+			name:   "WWW-Authenticate without claims is ignored",
+			header: http.Header{"Www-Authenticate": []string{`Bearer error="invalid_token"`}},
+			body:   `{}`,
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, ok := parseClaimsChallenge(tt.header, []byte(tt.body))
+			if ok != tt.wantOK {
+				t.Fatalf("parseClaimsChallenge() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if claims != tt.wantClaims {
+				t.Errorf("parseClaimsChallenge() claims = %q, want %q", claims, tt.wantClaims)
+			}
+		})
+	}
+}
+
+func TestArmRequestReturnsClaimsChallengeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		w.Write([]byte(`{"error":{"code":"RoleAssignmentRequestAcrsValidationFailed","message":"failed &claims=%7B%22access_token%22%3A%7B%22acrs%22%3A%7B%22essential%22%3Atrue%2C%22value%22%3A%22c1%22%7D%7D%7D"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	client.httpClient = &http.Client{
+		Transport: &testTransport{
+			baseURL:    server.URL,
+			realClient: http.DefaultTransport,
+		},
+		Timeout: 5 * time.Second,
+	}
+
+	_, err := client.armRequestWithBody(context.Background(), "PUT", "https://management.azure.com/subscriptions/x", map[string]string{})
+	challenge, ok := err.(*ClaimsChallengeError)
+	if !ok {
+		t.Fatalf("expected *ClaimsChallengeError, got %T: %v", err, err)
+	}
+	if challenge.StatusCode != 400 {
+		t.Errorf("StatusCode = %d, want 400", challenge.StatusCode)
+	}
+	if challenge.Claims != `{"access_token":{"acrs":{"essential":true,"value":"c1"}}}` {
+		t.Errorf("Claims = %q", challenge.Claims)
+	}
+}
+
+func TestWithClaims(t *testing.T) {
+	ctx := context.Background()
+	if HasClaims(ctx) {
+		t.Error("HasClaims(background) = true, want false")
+	}
+	if !HasClaims(WithClaims(ctx, `{"access_token":{}}`)) {
+		t.Error("HasClaims(WithClaims(...)) = false, want true")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -25,12 +26,25 @@ const (
 	pimBaseURL = "https://api.azrbac.mspim.azure.com/api/v2/privilegedAccess"
 )
 
+// Client is safe for concurrent use. The loaders for roles, groups and Lighthouse
+// run in parallel and share the cached user/tenant lookups.
 type Client struct {
 	cred       azcore.TokenCredential
 	pimCred    azcore.TokenCredential // Credential for PIM API
 	httpClient *http.Client
-	userID     string
-	tenant     *Tenant // Cached tenant info
+
+	mu          sync.Mutex  // Guards the fields below
+	flights     flightGroup // Coalesces concurrent identical lookups
+	userID      string
+	tenant      *Tenant        // Cached tenant info
+	concurrency int            // Max parallel requests for fan-out lookups
+	progress    ProgressFunc   // Optional progress callback for fan-out lookups
+	cache       *MetadataCache // Optional on-disk cache for display names
+	standing    bool           // Also return permanent active assignments
+
+	// Step-up authentication for Conditional Access claims challenges
+	stepUpCred   azcore.TokenCredential        // Interactive credential, created on first challenge
+	stepUpTokens map[string]azcore.AccessToken // scope|claims -> token
 }
 
 // NewClient creates a new Azure client using Azure CLI credentials.
@@ -72,10 +86,22 @@ func AuthenticateWithBrowser(ctx context.Context) (*Client, error) {
 	}, nil
 }
 
+// SetIncludeStanding controls whether GetRoles, GetGroups and GetLighthouseSubscriptions
+// also return standing (permanent) active assignments, marked with Standing.
+func (c *Client) SetIncludeStanding(include bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.standing = include
+}
+
+func (c *Client) standingEnabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.standing
+}
+
 func (c *Client) graphRequest(ctx context.Context, method, url string, body interface{}) ([]byte, error) {
-	token, err := c.cred.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: []string{"https://graph.microsoft.com/.default"},
-	})
+	token, err := c.getToken(ctx, c.cred, "https://graph.microsoft.com/.default")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token: %w", err)
 	}
@@ -127,6 +153,9 @@ func (c *Client) graphRequest(ctx context.Context, method, url string, body inte
 		}
 
 		if resp.StatusCode >= 400 {
+			if claims, ok := parseClaimsChallenge(resp.Header, respBody); ok {
+				return nil, &ClaimsChallengeError{Claims: claims, StatusCode: resp.StatusCode, Body: string(respBody)}
+			}
 			return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
 		}
 
@@ -139,9 +168,7 @@ func (c *Client) graphRequest(ctx context.Context, method, url string, body inte
 // pimRequest makes requests to the PIM Governance API (api.azrbac.mspim.azure.com)
 // This API uses the same token as ARM and works with Azure CLI credentials
 func (c *Client) pimRequest(ctx context.Context, method, url string, body interface{}) ([]byte, error) {
-	token, err := c.pimCred.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: []string{"https://api.azrbac.mspim.azure.com/.default"},
-	})
+	token, err := c.getToken(ctx, c.pimCred, "https://api.azrbac.mspim.azure.com/.default")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PIM token: %w", err)
 	}
@@ -193,6 +220,9 @@ func (c *Client) pimRequest(ctx context.Context, method, url string, body interf
 		}
 
 		if resp.StatusCode >= 400 {
+			if claims, ok := parseClaimsChallenge(resp.Header, respBody); ok {
+				return nil, &ClaimsChallengeError{Claims: claims, StatusCode: resp.StatusCode, Body: string(respBody)}
+			}
 			return nil, fmt.Errorf("PIM API error %d: %s", resp.StatusCode, string(respBody))
 		}
 
@@ -203,24 +233,36 @@ func (c *Client) pimRequest(ctx context.Context, method, url string, body interf
 }
 
 func (c *Client) GetCurrentUser(ctx context.Context) (string, error) {
-	if c.userID != "" {
-		return c.userID, nil
+	c.mu.Lock()
+	userID := c.userID
+	c.mu.Unlock()
+	if userID != "" {
+		return userID, nil
 	}
 
-	data, err := c.graphRequest(ctx, "GET", graphBaseURL+"/me?$select=id", nil)
-	if err != nil {
-		return "", err
-	}
+	// Parallel loaders all need the user ID - share a single /me request
+	val, err := c.flights.Do("me", func() (interface{}, error) {
+		data, err := c.graphRequest(ctx, "GET", graphBaseURL+"/me?$select=id", nil)
+		if err != nil {
+			return "", err
+		}
 
-	var result struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal(data, &result); err != nil {
+		var result struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return "", err
+		}
+
+		c.mu.Lock()
+		c.userID = result.ID
+		c.mu.Unlock()
+		return result.ID, nil
+	})
+	if err != nil {
 		return "", err
 	}
-
-	c.userID = result.ID
-	return c.userID, nil
+	return val.(string), nil
 }
 
 // GetCurrentUserInfo returns the user's display name and email
@@ -242,32 +284,44 @@ func (c *Client) GetCurrentUserInfo(ctx context.Context) (displayName, email str
 }
 
 func (c *Client) GetTenant(ctx context.Context) (*Tenant, error) {
-	if c.tenant != nil {
-		return c.tenant, nil
+	c.mu.Lock()
+	tenant := c.tenant
+	c.mu.Unlock()
+	if tenant != nil {
+		return tenant, nil
 	}
 
-	data, err := c.graphRequest(ctx, "GET", graphBaseURL+"/organization?$select=id,displayName", nil)
-	if err != nil {
-		return nil, err
-	}
+	val, err := c.flights.Do("organization", func() (interface{}, error) {
+		data, err := c.graphRequest(ctx, "GET", graphBaseURL+"/organization?$select=id,displayName", nil)
+		if err != nil {
+			return nil, err
+		}
 
-	var result struct {
-		Value []struct {
-			ID          string `json:"id"`
-			DisplayName string `json:"displayName"`
-		} `json:"value"`
-	}
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, err
-	}
+		var result struct {
+			Value []struct {
+				ID          string `json:"id"`
+				DisplayName string `json:"displayName"`
+			} `json:"value"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, err
+		}
 
-	if len(result.Value) == 0 {
-		return nil, fmt.Errorf("no organization found")
-	}
+		if len(result.Value) == 0 {
+			return nil, fmt.Errorf("no organization found")
+		}
 
-	c.tenant = &Tenant{
-		ID:          result.Value[0].ID,
-		DisplayName: result.Value[0].DisplayName,
+		tenant := &Tenant{
+			ID:          result.Value[0].ID,
+			DisplayName: result.Value[0].DisplayName,
+		}
+		c.mu.Lock()
+		c.tenant = tenant
+		c.mu.Unlock()
+		return tenant, nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return c.tenant, nil
+	return val.(*Tenant), nil
 }
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -445,6 +447,94 @@ func TestGetTenantCaching(t *testing.T) {
 	}
 }
 
+// TestConcurrentRefresh runs the roles, groups and Lighthouse loaders in parallel the
+// way the UI does on every refresh. Run with -race to detect unsynchronized access.
+func TestConcurrentRefresh(t *testing.T) {
+	var meCalls, groupNameCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/me"):
+			atomic.AddInt32(&meCalls, 1)
+			time.Sleep(20 * time.Millisecond) // Keep the lookup in flight so callers overlap
+			w.Write([]byte(`{"id": "user-1"}`))
+		case strings.Contains(path, "/organization"):
+			w.Write([]byte(`{"value": [{"id": "tenant-1", "displayName": "Contoso"}]}`))
+		case strings.Contains(path, "/aadroles/roleAssignments"):
+			w.Write([]byte(`{"value": [{"id": "a1", "roleDefinition": {"id": "r1", "displayName": "Reader"}, "endDateTime": "2030-01-01T00:00:00Z"}]}`))
+		case strings.Contains(path, "/aadGroups/roleAssignments"):
+			w.Write([]byte(`{"value": [{"id": "a2", "resourceId": "g1", "roleDefinition": {"id": "member", "displayName": "Member"}}]}`))
+		case strings.Contains(path, "/aadGroups/resources/"):
+			atomic.AddInt32(&groupNameCalls, 1)
+			w.Write([]byte(`{"id": "g1", "displayName": "Admins"}`))
+		case strings.Contains(path, "roleEligibilityScheduleInstances"):
+			w.Write([]byte(`{"value": [{"properties": {"roleDefinitionId": "/providers/Microsoft.Authorization/roleDefinitions/c1", "scope": "/subscriptions/s1"}}]}`))
+		case strings.Contains(path, "roleAssignmentScheduleInstances"):
+			w.Write([]byte(`{"value": []}`))
+		case strings.HasPrefix(path, "/subscriptions/"):
+			w.Write([]byte(`{"subscriptionId": "s1", "tenantId": "tenant-2"}`))
+		case strings.Contains(path, "findTenantInformationByTenantId"):
+			w.Write([]byte(`{"displayName": "Fabrikam"}`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	client.pimCred = &mockCredential{}
+	client.httpClient = &http.Client{
+		Transport: &testTransport{
+			baseURL:    server.URL,
+			realClient: http.DefaultTransport,
+		},
+		Timeout: 5 * time.Second,
+	}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 3; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetRoles(ctx); err != nil {
+				errs <- err
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetGroups(ctx); err != nil {
+				errs <- err
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetLighthouseSubscriptions(ctx); err != nil {
+				errs <- err
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetTenant(ctx); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&meCalls); got != 1 {
+		t.Errorf("expected 1 /me request for concurrent loaders, got %d", got)
+	}
+	if got := atomic.LoadInt32(&groupNameCalls); got < 1 || got > 3 {
+		t.Errorf("expected 1-3 group name requests, got %d", got)
+	}
+}
+
 // mockResponse represents a single HTTP response for retry testing
 type mockResponse struct {
 	code int
@@ -478,3 +568,234 @@ func (t *testTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	return t.realClient.RoundTrip(newReq)
 }
+
+// TestParseRequestResults tests extraction of request IDs, status and expiry from API responses
+func TestParseRequestResults(t *testing.T) {
+	fallback := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 1, 16, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		got        RequestResult
+		wantID     string
+		wantStatus string
+		wantExpiry *time.Time
+	}{
+		{
+			name:       "PIM response with schedule end",
+			got:        parsePIMRequestResult([]byte(`{"id":"req-1","status":{"status":"Closed","subStatus":"Provisioned"},"schedule":{"endDateTime":"2026-01-01T16:00:00Z"}}`), &fallback),
+			wantID:     "req-1",
+			wantStatus: "Provisioned",
+			wantExpiry: &end,
+		},
+		{
+			name:       "PIM response without schedule uses fallback",
+			got:        parsePIMRequestResult([]byte(`{"id":"req-2","status":{"status":"PendingApproval"}}`), &fallback),
+			wantID:     "req-2",
+			wantStatus: "PendingApproval",
+			wantExpiry: &fallback,
+		},
+		{
+			name:       "ARM response keeps generated request ID",
+			got:        parseARMRequestResult([]byte(`{"name":"ignored","properties":{"status":"Provisioned","scheduleInfo":{"expiration":{"endDateTime":"2026-01-01T16:00:00Z"}}}}`), "arm-req", &fallback),
+			wantID:     "arm-req",
+			wantStatus: "Provisioned",
+			wantExpiry: &end,
+		},
+		{
+			name:       "invalid body keeps defaults",
+			got:        parseARMRequestResult([]byte(`not json`), "arm-req", nil),
+			wantID:     "arm-req",
+			wantStatus: "",
+			wantExpiry: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.RequestID != tt.wantID {
+				t.Errorf("RequestID = %q, want %q", tt.got.RequestID, tt.wantID)
+			}
+			if tt.got.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", tt.got.Status, tt.wantStatus)
+			}
+			if (tt.got.ExpiresAt == nil) != (tt.wantExpiry == nil) ||
+				(tt.got.ExpiresAt != nil && !tt.got.ExpiresAt.Equal(*tt.wantExpiry)) {
+				t.Errorf("ExpiresAt = %v, want %v", tt.got.ExpiresAt, tt.wantExpiry)
+			}
+		})
+	}
+}
+
+// TestStandingAssignments tests that active assignments without an end date are reported as standing
+func TestStandingAssignments(t *testing.T) {
+	var roleAssignments []pimRoleAssignment
+	if err := json.Unmarshal([]byte(`[
+		{"id": "a1", "roleDefinition": {"id": "reader", "displayName": "Global Reader"}, "assignmentState": "Active", "endDateTime": "2026-01-01T16:00:00Z"},
+		{"id": "a2", "roleDefinition": {"id": "admin", "displayName": "Global Administrator"}, "assignmentState": "Active"}
+	]`), &roleAssignments); err != nil {
+		t.Fatal(err)
+	}
+
+	if active := activeRoleExpiries(roleAssignments); len(active) != 1 || active["reader"] == nil {
+		t.Errorf("activeRoleExpiries() = %v, want only the time-bound reader activation", active)
+	}
+	roles := standingRoles(roleAssignments)
+	if len(roles) != 1 || roles[0].RoleDefinitionID != "admin" || !roles[0].Standing || roles[0].Status != StatusActive {
+		t.Errorf("standingRoles() = %+v, want standing Global Administrator", roles)
+	}
+
+	var armAssignments roleAssignmentResponse
+	if err := json.Unmarshal([]byte(`{"value": [
+		{"properties": {"scope": "/subscriptions/s1", "roleDefinitionId": "/x/roleDefinitions/owner", "assignmentType": "Assigned",
+			"expandedProperties": {"roleDefinition": {"displayName": "Owner"}, "scope": {"displayName": "Prod"}}}},
+		{"properties": {"scope": "/subscriptions/s2", "roleDefinitionId": "/x/roleDefinitions/reader", "assignmentType": "Activated", "endDateTime": "2026-01-01T16:00:00Z"}},
+		{"properties": {"scope": "/providers/Microsoft.Management/managementGroups/mg", "roleDefinitionId": "/x/roleDefinitions/reader", "assignmentType": "Assigned"}}
+	]}`), &armAssignments); err != nil {
+		t.Fatal(err)
+	}
+
+	subMap := map[string]*LighthouseSubscription{}
+	addStandingAzureRoles(subMap, armAssignments)
+	if len(subMap) != 1 {
+		t.Fatalf("subscriptions = %d, want 1 (activations and management groups skipped)", len(subMap))
+	}
+	sub := subMap["s1"]
+	if sub == nil || sub.DisplayName != "Prod" || len(sub.EligibleRoles) != 1 {
+		t.Fatalf("subscription s1 = %+v, want Prod with one role", sub)
+	}
+	if role := sub.EligibleRoles[0]; !role.Standing || role.RoleDefinitionName != "Owner" || role.RoleEligibilityID != "" {
+		t.Errorf("standing role = %+v", role)
+	}
+}
+
+// TestRenewRequests tests the request bodies sent for eligibility renewals
+func TestRenewRequests(t *testing.T) {
+	bodies := make(map[string]map[string]interface{})
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/me"):
+			w.Write([]byte(`{"id": "user-1"}`))
+			return
+		case strings.HasSuffix(r.URL.Path, "/organization"):
+			w.Write([]byte(`{"value": [{"id": "tenant-1", "displayName": "Contoso"}]}`))
+			return
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		bodies[r.URL.Path] = body
+		mu.Unlock()
+		w.Write([]byte(`{"id": "req-1", "status": {"status": "PendingApproval"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	client.pimCred = &mockCredential{}
+	client.httpClient = &http.Client{
+		Transport: &testTransport{
+			baseURL:    server.URL,
+			realClient: http.DefaultTransport,
+		},
+		Timeout: 5 * time.Second,
+	}
+	ctx := context.Background()
+	year := 365 * 24 * time.Hour
+
+	if _, err := client.RenewRole(ctx, "role-def", "keep access", year); err != nil {
+		t.Fatalf("RenewRole() error = %v", err)
+	}
+	if _, err := client.RenewGroup(ctx, "group-1", "member", "keep access", year); err != nil {
+		t.Fatalf("RenewGroup() error = %v", err)
+	}
+	result, err := client.RenewAzureRole(ctx, "/subscriptions/s1", "/x/roleDefinitions/reader", "keep access", year)
+	if err != nil {
+		t.Fatalf("RenewAzureRole() error = %v", err)
+	}
+
+	for _, path := range []string{"/api/v2/privilegedAccess/aadroles/roleAssignmentRequests", "/api/v2/privilegedAccess/aadGroups/roleAssignmentRequests"} {
+		body := bodies[path]
+		if body["type"] != "UserRenew" || body["assignmentState"] != "Eligible" || body["reason"] != "keep access" {
+			t.Errorf("%s body = %v, want eligible UserRenew with reason", path, body)
+		}
+	}
+
+	armPath := "/subscriptions/s1/providers/Microsoft.Authorization/roleEligibilityScheduleRequests/" + result.RequestID
+	props, _ := bodies[armPath]["properties"].(map[string]interface{})
+	if props["requestType"] != "SelfRenew" || props["principalId"] != "user-1" {
+		t.Errorf("ARM body properties = %v, want SelfRenew for user-1", props)
+	}
+	expiration := props["scheduleInfo"].(map[string]interface{})["expiration"].(map[string]interface{})
+	if expiration["duration"] != "P365D" {
+		t.Errorf("ARM expiration duration = %v, want P365D", expiration["duration"])
+	}
+}
+
+func TestExtendRequests(t *testing.T) {
+	bodies := make(map[string]map[string]interface{})
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/me"):
+			w.Write([]byte(`{"id": "user-1"}`))
+			return
+		case strings.HasSuffix(r.URL.Path, "/organization"):
+			w.Write([]byte(`{"value": [{"id": "tenant-1", "displayName": "Contoso"}]}`))
+			return
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		bodies[r.URL.Path] = body
+		mu.Unlock()
+		w.Write([]byte(`{"id": "req-1", "status": {"status": "Provisioned"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	client.pimCred = &mockCredential{}
+	client.httpClient = &http.Client{
+		Transport: &testTransport{
+			baseURL:    server.URL,
+			realClient: http.DefaultTransport,
+		},
+		Timeout: 5 * time.Second,
+	}
+	ctx := context.Background()
+	duration := 90 * time.Minute
+
+	if _, err := client.ExtendRole(ctx, "role-def", "migration", duration); err != nil {
+		t.Fatalf("ExtendRole() error = %v", err)
+	}
+	if _, err := client.ExtendGroup(ctx, "group-1", "member", "migration", duration); err != nil {
+		t.Fatalf("ExtendGroup() error = %v", err)
+	}
+	result, err := client.ExtendAzureRole(ctx, "/subscriptions/s1", "/x/roleDefinitions/reader", "elig-1", "migration", duration)
+	if err != nil {
+		t.Fatalf("ExtendAzureRole() error = %v", err)
+	}
+
+	for _, path := range []string{"/api/v2/privilegedAccess/aadroles/roleAssignmentRequests", "/api/v2/privilegedAccess/aadGroups/roleAssignmentRequests"} {
+		body := bodies[path]
+		if body["type"] != "UserExtend" || body["assignmentState"] != "Active" || body["reason"] != "migration" {
+			t.Errorf("%s body = %v, want active UserExtend with reason", path, body)
+		}
+		schedule, _ := body["schedule"].(map[string]interface{})
+		if schedule["duration"] != "PT90M" {
+			t.Errorf("%s schedule duration = %v, want PT90M", path, schedule["duration"])
+		}
+	}
+
+	armPath := "/subscriptions/s1/providers/Microsoft.Authorization/roleAssignmentScheduleRequests/" + result.RequestID
+	props, _ := bodies[armPath]["properties"].(map[string]interface{})
+	if props["requestType"] != "SelfExtend" || props["linkedRoleEligibilityScheduleId"] != "elig-1" {
+		t.Errorf("ARM body properties = %v, want SelfExtend linked to elig-1", props)
+	}
+	expiration := props["scheduleInfo"].(map[string]interface{})["expiration"].(map[string]interface{})
+	if expiration["duration"] != "PT90M" {
+		t.Errorf("ARM expiration duration = %v, want PT90M", expiration["duration"])
+	}
+}
@@ -0,0 +1,63 @@
+package azure
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFlightGroupCoalescesConcurrentCalls(t *testing.T) {
+	var g flightGroup
+	var calls int32
+	release := make(chan struct{})
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]interface{}, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = g.Do("key", func() (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return "value", nil
+			})
+		}(i)
+	}
+
+	// Give all callers time to join the in-flight call before releasing it
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("fn called %d times, want 1", got)
+	}
+	for i, r := range results {
+		if r != "value" {
+			t.Errorf("results[%d] = %v, want value", i, r)
+		}
+	}
+}
+
+func TestFlightGroupForgetsCompletedCalls(t *testing.T) {
+	var g flightGroup
+	calls := 0
+	fn := func() (interface{}, error) {
+		calls++
+		return nil, errors.New("lookup failed")
+	}
+
+	if _, err := g.Do("key", fn); err == nil {
+		t.Error("expected error from first call")
+	}
+	if _, err := g.Do("key", fn); err == nil {
+		t.Error("expected error from second call")
+	}
+	if calls != 2 {
+		t.Errorf("fn called %d times, want 2 (failed lookups are not cached)", calls)
+	}
+}
@@ -68,33 +68,17 @@ func (c *Client) GetEligibleGroups(ctx context.Context) ([]Group, error) {
 		reqURL = result.NextLink // Follow pagination until no more pages
 	}
 
-	// Get group details for each unique group (in parallel)
-	groupIDs := make(map[string]bool)
+	// Resolve names for each unique group
+	seen := make(map[string]bool)
+	var groupIDs []string
 	for _, g := range allAssignments {
-		groupIDs[g.ResourceID] = true
+		if !seen[g.ResourceID] {
+			seen[g.ResourceID] = true
+			groupIDs = append(groupIDs, g.ResourceID)
+		}
 	}
-
-	// Fetch group names in parallel
-	groupNames := make(map[string]string)
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	for groupID := range groupIDs {
-		wg.Add(1)
-		go func(id string) {
-			defer wg.Done()
-			name, err := c.getGroupName(ctx, id)
-			if err != nil {
-				// Silently skip - group name is optional for display
-				return
-			}
-			if name != "" {
-				mu.Lock()
-				groupNames[id] = name
-				mu.Unlock()
-			}
-		}(groupID)
-	}
-	wg.Wait()
+	groupNames := c.getGroupNames(ctx, groupIDs)
+	_ = c.metadataCache().Save() // Best effort - a failed save only costs lookups next time
 
 	groups := make([]Group, 0, len(allAssignments))
 	for _, g := range allAssignments {
@@ -110,32 +94,151 @@ func (c *Client) GetEligibleGroups(ctx context.Context) ([]Group, error) {
 			RoleDefinitionID: g.RoleDefinition.ID,          // "member" or "owner" from API
 			Status:           StatusInactive,
 			MaxDuration:      8 * time.Hour,
+			EligibleUntil:    parseEndDateTime(g.EndDateTime),
 		})
 	}
 
 	return groups, nil
 }
 
-func (c *Client) getGroupName(ctx context.Context, groupID string) (string, error) {
-	reqURL := fmt.Sprintf("%s/aadGroups/resources/%s", pimBaseURL, groupID)
+// getByIDsBatchSize is the maximum number of IDs accepted by directoryObjects/getByIds
+const getByIDsBatchSize = 1000
+
+// getGroupNames resolves display names for the given group IDs. Cached names are
+// used first; the rest are fetched with one Graph directoryObjects/getByIds call
+// per 1000 groups, and groups the batch call could not resolve fall back to
+// per-group PIM lookups on the worker pool.
+// Groups whose name cannot be resolved are omitted from the result.
+func (c *Client) getGroupNames(ctx context.Context, groupIDs []string) map[string]string {
+	groupNames := make(map[string]string)
+	cache := c.metadataCache()
+
+	var uncached []string
+	for _, id := range groupIDs {
+		if name, ok := cache.Get(cacheGroupName + id); ok {
+			groupNames[id] = name
+		} else {
+			uncached = append(uncached, id)
+		}
+	}
+	if len(uncached) == 0 {
+		return groupNames
+	}
 
-	data, err := c.pimRequest(ctx, "GET", reqURL, nil)
+	// Progress counts every uncached group, whether the batch call or the
+	// per-group fallback resolved it
+	total := len(uncached)
+	resolved := 0
+	c.reportProgress("group names", 0, total)
+	for start := 0; start < total; start += getByIDsBatchSize {
+		end := min(start+getByIDsBatchSize, total)
+		names, err := c.getDirectoryObjectNames(ctx, uncached[start:end], "group")
+		if err != nil {
+			// Fall back to per-group lookups below
+			continue
+		}
+		for id, name := range names {
+			groupNames[id] = name
+			cache.Set(cacheGroupName+id, name)
+		}
+		resolved += len(names)
+		c.reportProgress("group names", min(resolved, total), total)
+	}
+
+	var missing []string
+	for _, id := range uncached {
+		if groupNames[id] == "" {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		c.reportProgress("group names", total, total)
+		return groupNames
+	}
+
+	var mu sync.Mutex
+	c.forEachLimitFrom(ctx, "group names", missing, total-len(missing), total, func(id string) {
+		name, err := c.getGroupName(ctx, id)
+		if err != nil || name == "" {
+			// Silently skip - group name is optional for display
+			return
+		}
+		mu.Lock()
+		groupNames[id] = name
+		mu.Unlock()
+		cache.Set(cacheGroupName+id, name)
+	})
+
+	return groupNames
+}
+
+// getDirectoryObjectNames looks up display names for up to 1000 directory objects
+// of the given type in a single Graph request
+func (c *Client) getDirectoryObjectNames(ctx context.Context, ids []string, objectType string) (map[string]string, error) {
+	body := map[string]interface{}{
+		"ids":   ids,
+		"types": []string{objectType},
+	}
+	data, err := c.graphRequest(ctx, "POST", graphBaseURL+"/directoryObjects/getByIds?$select=id,displayName", body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var result struct {
-		ID          string `json:"id"`
-		DisplayName string `json:"displayName"`
+		Value []struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"displayName"`
+		} `json:"value"`
 	}
 	if err := json.Unmarshal(data, &result); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return result.DisplayName, nil
+	names := make(map[string]string, len(result.Value))
+	for _, obj := range result.Value {
+		if obj.DisplayName != "" {
+			names[obj.ID] = obj.DisplayName
+		}
+	}
+	return names, nil
+}
+
+func (c *Client) getGroupName(ctx context.Context, groupID string) (string, error) {
+	val, err := c.flights.Do("group:"+groupID, func() (interface{}, error) {
+		reqURL := fmt.Sprintf("%s/aadGroups/resources/%s", pimBaseURL, groupID)
+
+		data, err := c.pimRequest(ctx, "GET", reqURL, nil)
+		if err != nil {
+			return "", err
+		}
+
+		var result struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"displayName"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return "", err
+		}
+
+		return result.DisplayName, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return val.(string), nil
 }
 
 func (c *Client) GetActiveGroups(ctx context.Context) (map[string]*time.Time, error) {
+	assignments, err := c.getActiveGroupAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return activeGroupExpiries(assignments), nil
+}
+
+// getActiveGroupAssignments returns all active group memberships and ownerships of
+// the current user, both PIM activations and standing assignments
+func (c *Client) getActiveGroupAssignments(ctx context.Context) ([]pimGroupAssignment, error) {
 	userID, err := c.GetCurrentUser(ctx)
 	if err != nil {
 		return nil, err
@@ -162,8 +265,13 @@ func (c *Client) GetActiveGroups(ctx context.Context) (map[string]*time.Time, er
 		reqURL = result.NextLink // Follow pagination until no more pages
 	}
 
+	return allAssignments, nil
+}
+
+// activeGroupExpiries maps group IDs to the end of their time-bound activation
+func activeGroupExpiries(assignments []pimGroupAssignment) map[string]*time.Time {
 	active := make(map[string]*time.Time)
-	for _, g := range allAssignments {
+	for _, g := range assignments {
 		if g.EndDateTime != "" {
 			t, err := time.Parse(time.RFC3339, g.EndDateTime)
 			if err == nil {
@@ -171,14 +279,47 @@ func (c *Client) GetActiveGroups(ctx context.Context) (map[string]*time.Time, er
 			}
 		}
 	}
+	return active
+}
+
+// standingGroups returns the active group assignments without an end date,
+// resolving group names like GetEligibleGroups
+func (c *Client) standingGroups(ctx context.Context, assignments []pimGroupAssignment) []Group {
+	var standing []pimGroupAssignment
+	var groupIDs []string
+	for _, g := range assignments {
+		if g.EndDateTime == "" {
+			standing = append(standing, g)
+			groupIDs = append(groupIDs, g.ResourceID)
+		}
+	}
+	if len(standing) == 0 {
+		return nil
+	}
+	groupNames := c.getGroupNames(ctx, groupIDs)
 
-	return active, nil
+	groups := make([]Group, 0, len(standing))
+	for _, g := range standing {
+		displayName := groupNames[g.ResourceID]
+		if displayName == "" {
+			displayName = g.ResourceID
+		}
+		groups = append(groups, Group{
+			ID:               g.ResourceID,
+			DisplayName:      displayName,
+			Description:      g.RoleDefinition.DisplayName,
+			RoleDefinitionID: g.RoleDefinition.ID,
+			Status:           StatusActive,
+			Standing:         true,
+		})
+	}
+	return groups
 }
 
 func (c *Client) GetGroups(ctx context.Context) ([]Group, error) {
 	// Fetch eligible and active groups in parallel
 	var eligible []Group
-	var active map[string]*time.Time
+	var assignments []pimGroupAssignment
 	var eligibleErr, activeErr error
 
 	var wg sync.WaitGroup
@@ -189,7 +330,7 @@ func (c *Client) GetGroups(ctx context.Context) ([]Group, error) {
 	}()
 	go func() {
 		defer wg.Done()
-		active, activeErr = c.GetActiveGroups(ctx)
+		assignments, activeErr = c.getActiveGroupAssignments(ctx)
 	}()
 	wg.Wait()
 
@@ -200,6 +341,7 @@ func (c *Client) GetGroups(ctx context.Context) ([]Group, error) {
 		return nil, activeErr
 	}
 
+	active := activeGroupExpiries(assignments)
 	for i := range eligible {
 		if expiry, ok := active[eligible[i].ID]; ok {
 			eligible[i].ExpiresAt = expiry
@@ -207,13 +349,16 @@ func (c *Client) GetGroups(ctx context.Context) ([]Group, error) {
 		}
 	}
 
+	if c.standingEnabled() {
+		eligible = append(eligible, c.standingGroups(ctx, assignments)...)
+	}
 	return eligible, nil
 }
 
-func (c *Client) ActivateGroup(ctx context.Context, groupID, roleDefinitionID, justification string, duration time.Duration) error {
+func (c *Client) ActivateGroup(ctx context.Context, groupID, roleDefinitionID, justification string, duration time.Duration) (RequestResult, error) {
 	userID, err := c.GetCurrentUser(ctx)
 	if err != nil {
-		return err
+		return RequestResult{}, err
 	}
 
 	minutes := int(duration.Minutes())
@@ -232,14 +377,17 @@ func (c *Client) ActivateGroup(ctx context.Context, groupID, roleDefinitionID, j
 		},
 	}
 
-	_, err = c.pimRequest(ctx, "POST", pimBaseURL+"/aadGroups/roleAssignmentRequests", body)
-	return err
+	data, err := c.pimRequest(ctx, "POST", pimBaseURL+"/aadGroups/roleAssignmentRequests", body)
+	if err != nil {
+		return RequestResult{}, err
+	}
+	return parsePIMRequestResult(data, scheduledExpiry(duration)), nil
 }
 
-func (c *Client) DeactivateGroup(ctx context.Context, groupID, roleDefinitionID string) error {
+func (c *Client) DeactivateGroup(ctx context.Context, groupID, roleDefinitionID string) (RequestResult, error) {
 	userID, err := c.GetCurrentUser(ctx)
 	if err != nil {
-		return err
+		return RequestResult{}, err
 	}
 
 	// For deactivation, we use UserRemove type with a minimal schedule
@@ -257,6 +405,68 @@ func (c *Client) DeactivateGroup(ctx context.Context, groupID, roleDefinitionID
 		},
 	}
 
-	_, err = c.pimRequest(ctx, "POST", pimBaseURL+"/aadGroups/roleAssignmentRequests", body)
-	return err
+	data, err := c.pimRequest(ctx, "POST", pimBaseURL+"/aadGroups/roleAssignmentRequests", body)
+	if err != nil {
+		return RequestResult{}, err
+	}
+	return parsePIMRequestResult(data, nil), nil
+}
+
+// ExtendGroup requests an extension of the current user's active group membership
+// or ownership, keeping it active for duration from now
+func (c *Client) ExtendGroup(ctx context.Context, groupID, roleDefinitionID, justification string, duration time.Duration) (RequestResult, error) {
+	userID, err := c.GetCurrentUser(ctx)
+	if err != nil {
+		return RequestResult{}, err
+	}
+
+	body := map[string]interface{}{
+		"resourceId":       groupID,
+		"roleDefinitionId": roleDefinitionID,
+		"subjectId":        userID,
+		"assignmentState":  "Active",
+		"type":             "UserExtend",
+		"reason":           justification,
+		"schedule": map[string]interface{}{
+			"type":          "Once",
+			"startDateTime": time.Now().UTC().Format(time.RFC3339),
+			"duration":      fmt.Sprintf("PT%dM", int(duration.Minutes())),
+		},
+	}
+
+	data, err := c.pimRequest(ctx, "POST", pimBaseURL+"/aadGroups/roleAssignmentRequests", body)
+	if err != nil {
+		return RequestResult{}, err
+	}
+	return parsePIMRequestResult(data, scheduledExpiry(duration)), nil
+}
+
+// RenewGroup requests renewal of the current user's eligible group membership or
+// ownership, extending it by duration from now
+func (c *Client) RenewGroup(ctx context.Context, groupID, roleDefinitionID, justification string, duration time.Duration) (RequestResult, error) {
+	userID, err := c.GetCurrentUser(ctx)
+	if err != nil {
+		return RequestResult{}, err
+	}
+
+	start := time.Now().UTC()
+	body := map[string]interface{}{
+		"resourceId":       groupID,
+		"roleDefinitionId": roleDefinitionID,
+		"subjectId":        userID,
+		"assignmentState":  "Eligible",
+		"type":             "UserRenew",
+		"reason":           justification,
+		"schedule": map[string]interface{}{
+			"type":          "Once",
+			"startDateTime": start.Format(time.RFC3339),
+			"endDateTime":   start.Add(duration).Format(time.RFC3339),
+		},
+	}
+
+	data, err := c.pimRequest(ctx, "POST", pimBaseURL+"/aadGroups/roleAssignmentRequests", body)
+	if err != nil {
+		return RequestResult{}, err
+	}
+	return parsePIMRequestResult(data, scheduledExpiry(duration)), nil
 }
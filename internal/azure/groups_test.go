@@ -0,0 +1,131 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetGroupNames(t *testing.T) {
+	tests := []struct {
+		name            string
+		batchStatus     int
+		batchNames      map[string]string
+		wantNames       map[string]string
+		wantPIMLookups  int32
+		wantBatchCalled bool
+	}{
+		{
+			name:            "batch call resolves all groups",
+			batchStatus:     200,
+			batchNames:      map[string]string{"g1": "Admins", "g2": "Operators"},
+			wantNames:       map[string]string{"g1": "Admins", "g2": "Operators"},
+			wantPIMLookups:  0,
+			wantBatchCalled: true,
+		},
+		{
+			name:            "missing groups fall back to PIM lookups",
+			batchStatus:     200,
+			batchNames:      map[string]string{"g1": "Admins"},
+			wantNames:       map[string]string{"g1": "Admins", "g2": "pim-g2"},
+			wantPIMLookups:  1,
+			wantBatchCalled: true,
+		},
+		{
+			name:            "failed batch call falls back for every group",
+			batchStatus:     403,
+			wantNames:       map[string]string{"g1": "pim-g1", "g2": "pim-g2"},
+			wantPIMLookups:  2,
+			wantBatchCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pimLookups int32
+			var batchCalled atomic.Bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/directoryObjects/getByIds"):
+					batchCalled.Store(true)
+					if r.Method != "POST" {
+						t.Errorf("expected POST for getByIds, got %s", r.Method)
+					}
+					var req struct {
+						IDs   []string `json:"ids"`
+						Types []string `json:"types"`
+					}
+					json.NewDecoder(r.Body).Decode(&req)
+					if len(req.Types) != 1 || req.Types[0] != "group" {
+						t.Errorf("expected types [group], got %v", req.Types)
+					}
+					w.WriteHeader(tt.batchStatus)
+					var value []map[string]string
+					for _, id := range req.IDs {
+						if name, ok := tt.batchNames[id]; ok {
+							value = append(value, map[string]string{"id": id, "displayName": name})
+						}
+					}
+					json.NewEncoder(w).Encode(map[string]interface{}{"value": value})
+				case strings.Contains(r.URL.Path, "/aadGroups/resources/"):
+					atomic.AddInt32(&pimLookups, 1)
+					id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+					json.NewEncoder(w).Encode(map[string]string{"id": id, "displayName": "pim-" + id})
+				default:
+					w.WriteHeader(404)
+				}
+			}))
+			defer server.Close()
+
+			client := newTestClient(server.URL)
+			client.pimCred = &mockCredential{}
+			client.httpClient = &http.Client{
+				Transport: &testTransport{
+					baseURL:    server.URL,
+					realClient: http.DefaultTransport,
+				},
+				Timeout: 5 * time.Second,
+			}
+
+			var mu sync.Mutex
+			var progress []int
+			client.SetProgressFunc(func(stage string, done, total int) {
+				if total != 2 {
+					t.Errorf("progress total = %d, want 2 for every update", total)
+				}
+				mu.Lock()
+				progress = append(progress, done)
+				mu.Unlock()
+			})
+
+			names := client.getGroupNames(context.Background(), []string{"g1", "g2"})
+
+			if batchCalled.Load() != tt.wantBatchCalled {
+				t.Errorf("batch called = %v, want %v", batchCalled.Load(), tt.wantBatchCalled)
+			}
+			if got := atomic.LoadInt32(&pimLookups); got != tt.wantPIMLookups {
+				t.Errorf("PIM lookups = %d, want %d", got, tt.wantPIMLookups)
+			}
+			for id, want := range tt.wantNames {
+				if names[id] != want {
+					t.Errorf("names[%s] = %q, want %q", id, names[id], want)
+				}
+			}
+			for i := 1; i < len(progress); i++ {
+				if progress[i] < progress[i-1] {
+					t.Errorf("progress went backwards: %v", progress)
+					break
+				}
+			}
+			if len(progress) == 0 || progress[len(progress)-1] != 2 {
+				t.Errorf("progress = %v, want it to finish at 2", progress)
+			}
+		})
+	}
+}
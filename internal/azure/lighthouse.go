@@ -12,8 +12,6 @@ import (
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 )
 
 // newUUID generates a random UUID v4
@@ -82,31 +80,56 @@ type roleAssignmentResponse struct {
 		ID         string `json:"id"`
 		Name       string `json:"name"`
 		Properties struct {
-			RoleDefinitionID string `json:"roleDefinitionId"`
-			PrincipalID      string `json:"principalId"`
-			Scope            string `json:"scope"`
-			Status           string `json:"status"`
-			StartDateTime    string `json:"startDateTime"`
-			EndDateTime      string `json:"endDateTime"`
-			AssignmentType   string `json:"assignmentType"`
-			MemberType       string `json:"memberType"`
+			RoleDefinitionID   string `json:"roleDefinitionId"`
+			PrincipalID        string `json:"principalId"`
+			Scope              string `json:"scope"`
+			Status             string `json:"status"`
+			StartDateTime      string `json:"startDateTime"`
+			EndDateTime        string `json:"endDateTime"`
+			AssignmentType     string `json:"assignmentType"`
+			MemberType         string `json:"memberType"`
+			ExpandedProperties *struct {
+				RoleDefinition *struct {
+					DisplayName string `json:"displayName"`
+				} `json:"roleDefinition"`
+				Scope *struct {
+					DisplayName string `json:"displayName"`
+				} `json:"scope"`
+			} `json:"expandedProperties"`
 		} `json:"properties"`
 	} `json:"value"`
 }
 
 // getSubscriptionDetails fetches subscription details including tenant ID
+// Only the home tenant is read from the result, so a cached tenant ID short-circuits the request.
 func (c *Client) getSubscriptionDetails(ctx context.Context, subscriptionID string) (*subscriptionResponse, error) {
-	reqURL := fmt.Sprintf("https://management.azure.com/subscriptions/%s?api-version=2022-12-01", subscriptionID)
-	data, err := c.armRequest(ctx, "GET", reqURL)
-	if err != nil {
-		return nil, err
+	cache := c.metadataCache()
+	if tenantID, ok := cache.Get(cacheSubTenant + subscriptionID); ok {
+		return &subscriptionResponse{SubscriptionID: subscriptionID, TenantID: tenantID}, nil
 	}
 
-	var result subscriptionResponse
-	if err := json.Unmarshal(data, &result); err != nil {
+	val, err := c.flights.Do("subscription:"+subscriptionID, func() (interface{}, error) {
+		reqURL := fmt.Sprintf("https://management.azure.com/subscriptions/%s?api-version=2022-12-01", subscriptionID)
+		data, err := c.armRequest(ctx, "GET", reqURL)
+		if err != nil {
+			return nil, err
+		}
+
+		var result subscriptionResponse
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, err
+		}
+		tenantID := result.HomeTenantID
+		if tenantID == "" {
+			tenantID = result.TenantID
+		}
+		cache.Set(cacheSubTenant+subscriptionID, tenantID)
+		return &result, nil
+	})
+	if err != nil {
 		return nil, err
 	}
-	return &result, nil
+	return val.(*subscriptionResponse), nil
 }
 
 // getTenantDisplayName tries to get a tenant's display name from cache or returns the ID
@@ -124,36 +147,44 @@ func getTenantDisplayName(tenantID string, tenantCache map[string]string) string
 // getTenantNameByID fetches a tenant's display name using the Graph API
 // This works for any tenant, including Lighthouse customer tenants
 func (c *Client) getTenantNameByID(ctx context.Context, tenantID string) (string, error) {
-	// Use Graph API to lookup tenant info by ID
-	reqURL := fmt.Sprintf("https://graph.microsoft.com/v1.0/tenantRelationships/findTenantInformationByTenantId(tenantId='%s')", tenantID)
-
-	data, err := c.graphRequest(ctx, "GET", reqURL, nil)
-	if err != nil {
-		return "", err
+	cache := c.metadataCache()
+	if name, ok := cache.Get(cacheTenantName + tenantID); ok {
+		return name, nil
 	}
 
-	var result struct {
-		TenantID          string `json:"tenantId"`
-		DisplayName       string `json:"displayName"`
-		DefaultDomainName string `json:"defaultDomainName"`
-	}
-	if err := json.Unmarshal(data, &result); err != nil {
-		return "", err
-	}
+	val, err := c.flights.Do("tenant:"+tenantID, func() (interface{}, error) {
+		// Use Graph API to lookup tenant info by ID
+		reqURL := fmt.Sprintf("https://graph.microsoft.com/v1.0/tenantRelationships/findTenantInformationByTenantId(tenantId='%s')", tenantID)
 
-	if result.DisplayName != "" {
-		return result.DisplayName, nil
-	}
-	if result.DefaultDomainName != "" {
-		return result.DefaultDomainName, nil
+		data, err := c.graphRequest(ctx, "GET", reqURL, nil)
+		if err != nil {
+			return "", err
+		}
+
+		var result struct {
+			TenantID          string `json:"tenantId"`
+			DisplayName       string `json:"displayName"`
+			DefaultDomainName string `json:"defaultDomainName"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return "", err
+		}
+
+		name := result.DisplayName
+		if name == "" {
+			name = result.DefaultDomainName
+		}
+		cache.Set(cacheTenantName+tenantID, name)
+		return name, nil
+	})
+	if err != nil {
+		return "", err
 	}
-	return "", nil
+	return val.(string), nil
 }
 
 func (c *Client) armRequest(ctx context.Context, method, reqURL string) ([]byte, error) {
-	token, err := c.cred.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: []string{"https://management.azure.com/.default"},
-	})
+	token, err := c.getToken(ctx, c.cred, "https://management.azure.com/.default")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ARM token: %w", err)
 	}
@@ -191,6 +222,9 @@ func (c *Client) armRequest(ctx context.Context, method, reqURL string) ([]byte,
 		}
 
 		if resp.StatusCode >= 400 {
+			if claims, ok := parseClaimsChallenge(resp.Header, body); ok {
+				return nil, &ClaimsChallengeError{Claims: claims, StatusCode: resp.StatusCode, Body: string(body)}
+			}
 			return nil, fmt.Errorf("ARM API error %d: %s", resp.StatusCode, string(body))
 		}
 
@@ -201,7 +235,7 @@ func (c *Client) armRequest(ctx context.Context, method, reqURL string) ([]byte,
 
 // GetLighthouseSubscriptions fetches subscriptions where the current user has eligible PIM roles
 // Uses the ARM API with $filter=asTarget() to get only the current user's eligible assignments
-func (c *Client) GetLighthouseSubscriptions(ctx context.Context, groups []Group) ([]LighthouseSubscription, error) {
+func (c *Client) GetLighthouseSubscriptions(ctx context.Context) ([]LighthouseSubscription, error) {
 	// Query all eligible role assignments for the current user using asTarget() filter
 	// This returns ONLY the current user's eligible assignments across all subscriptions
 	// Build URL with proper query parameter encoding
@@ -272,76 +306,15 @@ func (c *Client) GetLighthouseSubscriptions(ctx context.Context, groups []Group)
 			Scope:              e.Properties.Scope,
 			Status:             StatusInactive,
 			ExpiresAt:          nil,
+			EligibleUntil:      parseEndDateTime(e.Properties.EndDateTime),
 		}
 
 		sub.EligibleRoles = append(sub.EligibleRoles, role)
 	}
 
-	// Phase 1: Fetch subscription details to get tenant IDs (in parallel)
-	subTenantMap := make(map[string]string) // subID -> tenantID
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	for subID := range subMap {
-		wg.Add(1)
-		go func(id string) {
-			defer wg.Done()
-			details, err := c.getSubscriptionDetails(ctx, id)
-			if err != nil {
-				// Silently skip - subscription details are optional for display
-				return
-			}
-			tenantID := details.HomeTenantID
-			if tenantID == "" {
-				tenantID = details.TenantID
-			}
-			if tenantID != "" {
-				mu.Lock()
-				subTenantMap[id] = tenantID
-				mu.Unlock()
-			}
-		}(subID)
-	}
-	wg.Wait()
-
-	// Phase 2: Collect unique tenant IDs
-	uniqueTenants := make(map[string]bool)
-	for _, tenantID := range subTenantMap {
-		uniqueTenants[tenantID] = true
-	}
-
-	// Phase 3: Fetch tenant names for unique tenant IDs only (in parallel)
-	// This is the optimization: N subscriptions in M tenants = M calls instead of N calls
-	tenantCache := make(map[string]string) // tenantID -> tenantName
-	for tenantID := range uniqueTenants {
-		wg.Add(1)
-		go func(tid string) {
-			defer wg.Done()
-			name, err := c.getTenantNameByID(ctx, tid)
-			if err != nil {
-				// Silently skip - tenant name is optional for display
-				return
-			}
-			if name != "" {
-				mu.Lock()
-				tenantCache[tid] = name
-				mu.Unlock()
-			}
-		}(tenantID)
-	}
-	wg.Wait()
-
-	// Tenant cache populated - names applied in phase 4
-
-	// Phase 4: Apply cached tenant info to subscriptions
-	for subID, tenantID := range subTenantMap {
-		if sub, ok := subMap[subID]; ok {
-			sub.TenantID = tenantID
-			sub.TenantName = getTenantDisplayName(tenantID, tenantCache)
-		}
-	}
-
-	// Query active role assignments to update status
-	// This is optional - if it fails, we just don't show which roles are active
+	// Query active role assignments to update status, and to add standing assignments
+	// when requested. This is optional - if it fails, we just don't show which roles are active.
+	// It runs before the tenant lookups so subscriptions with only standing access get names too.
 	activeBaseURL := "https://management.azure.com/providers/Microsoft.Authorization/roleAssignmentScheduleInstances"
 	activeParams := url.Values{}
 	activeParams.Set("api-version", "2020-10-01")
@@ -377,6 +350,71 @@ func (c *Client) GetLighthouseSubscriptions(ctx context.Context, groups []Group)
 					}
 				}
 			}
+
+			if c.standingEnabled() {
+				addStandingAzureRoles(subMap, activeResult)
+			}
+		}
+	}
+
+	// Phase 1: Fetch subscription details to get tenant IDs (bounded worker pool)
+	subIDs := make([]string, 0, len(subMap))
+	for subID := range subMap {
+		subIDs = append(subIDs, subID)
+	}
+	subTenantMap := make(map[string]string) // subID -> tenantID
+	var mu sync.Mutex
+	c.forEachLimit(ctx, "subscriptions", subIDs, func(id string) {
+		details, err := c.getSubscriptionDetails(ctx, id)
+		if err != nil {
+			// Silently skip - subscription details are optional for display
+			return
+		}
+		tenantID := details.HomeTenantID
+		if tenantID == "" {
+			tenantID = details.TenantID
+		}
+		if tenantID != "" {
+			mu.Lock()
+			subTenantMap[id] = tenantID
+			mu.Unlock()
+		}
+	})
+
+	// Phase 2: Collect unique tenant IDs
+	uniqueTenants := make(map[string]bool)
+	var tenantIDs []string
+	for _, tenantID := range subTenantMap {
+		if !uniqueTenants[tenantID] {
+			uniqueTenants[tenantID] = true
+			tenantIDs = append(tenantIDs, tenantID)
+		}
+	}
+
+	// Phase 3: Fetch tenant names for unique tenant IDs only (bounded worker pool)
+	// This is the optimization: N subscriptions in M tenants = M calls instead of N calls
+	tenantCache := make(map[string]string) // tenantID -> tenantName
+	c.forEachLimit(ctx, "tenant names", tenantIDs, func(tid string) {
+		name, err := c.getTenantNameByID(ctx, tid)
+		if err != nil {
+			// Silently skip - tenant name is optional for display
+			return
+		}
+		if name != "" {
+			mu.Lock()
+			tenantCache[tid] = name
+			mu.Unlock()
+		}
+	})
+
+	// Tenant cache populated - names applied in phase 4
+	_ = c.metadataCache().Save() // Best effort - a failed save only costs lookups next time
+
+	// Phase 4: Apply cached tenant info to subscriptions
+	for subID, tenantID := range subTenantMap {
+		if sub, ok := subMap[subID]; ok {
+			sub.TenantID = tenantID
+			sub.TenantName = getTenantDisplayName(tenantID, tenantCache)
 		}
 	}
 
@@ -397,16 +435,60 @@ func (c *Client) GetLighthouseSubscriptions(ctx context.Context, groups []Group)
 	return subscriptions, nil
 }
 
+// addStandingAzureRoles adds permanent ("Assigned") role assignments without an end
+// date to their subscriptions, creating subscriptions that have no eligible roles
+func addStandingAzureRoles(subMap map[string]*LighthouseSubscription, active roleAssignmentResponse) {
+	for _, a := range active.Value {
+		p := a.Properties
+		if p.AssignmentType == "Activated" || p.EndDateTime != "" {
+			continue
+		}
+		parts := strings.Split(p.Scope, "/")
+		if len(parts) < 3 || parts[1] != "subscriptions" {
+			continue // Skip management group and tenant scopes
+		}
+		subID := parts[2]
+
+		roleName := lastSegment(p.RoleDefinitionID)
+		if p.ExpandedProperties != nil && p.ExpandedProperties.RoleDefinition != nil && p.ExpandedProperties.RoleDefinition.DisplayName != "" {
+			roleName = p.ExpandedProperties.RoleDefinition.DisplayName
+		}
+
+		sub, exists := subMap[subID]
+		if !exists {
+			displayName := subID
+			if p.ExpandedProperties != nil && p.ExpandedProperties.Scope != nil && len(parts) == 3 {
+				displayName = p.ExpandedProperties.Scope.DisplayName
+			}
+			sub = &LighthouseSubscription{
+				ID:            subID,
+				DisplayName:   displayName,
+				Status:        StatusInactive,
+				EligibleRoles: make([]EligibleAzureRole, 0),
+			}
+			subMap[subID] = sub
+		}
+		sub.EligibleRoles = append(sub.EligibleRoles, EligibleAzureRole{
+			RoleDefinitionID:   p.RoleDefinitionID,
+			RoleDefinitionName: roleName,
+			Scope:              p.Scope,
+			Status:             StatusActive,
+			Standing:           true,
+		})
+	}
+}
+
 // ActivateAzureRole activates an eligible Azure RBAC role
 // scope should be the full scope path (e.g., /subscriptions/{id} or /subscriptions/{id}/resourceGroups/{name})
-func (c *Client) ActivateAzureRole(ctx context.Context, scope, roleDefinitionID, roleEligibilityID, justification string, duration time.Duration) error {
+// The returned RequestResult carries the generated request ID even when the request fails.
+func (c *Client) ActivateAzureRole(ctx context.Context, scope, roleDefinitionID, roleEligibilityID, justification string, duration time.Duration) (RequestResult, error) {
 	requestID := newUUID()
 	activationURL := fmt.Sprintf("https://management.azure.com%s/providers/Microsoft.Authorization/roleAssignmentScheduleRequests/%s?api-version=2020-10-01", scope, requestID)
 
 	// Get current user ID for principalId
 	userID, err := c.GetCurrentUser(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get current user: %w", err)
+		return RequestResult{RequestID: requestID}, fmt.Errorf("failed to get current user: %w", err)
 	}
 
 	body := map[string]interface{}{
@@ -426,19 +508,22 @@ func (c *Client) ActivateAzureRole(ctx context.Context, scope, roleDefinitionID,
 		},
 	}
 
-	_, err = c.armRequestWithBody(ctx, "PUT", activationURL, body)
-	return err
+	data, err := c.armRequestWithBody(ctx, "PUT", activationURL, body)
+	if err != nil {
+		return RequestResult{RequestID: requestID}, err
+	}
+	return parseARMRequestResult(data, requestID, scheduledExpiry(duration)), nil
 }
 
 // DeactivateAzureRole deactivates an active Azure RBAC role
 // scope should be the full scope path (e.g., /subscriptions/{id} or /subscriptions/{id}/resourceGroups/{name})
-func (c *Client) DeactivateAzureRole(ctx context.Context, scope, roleDefinitionID string) error {
+func (c *Client) DeactivateAzureRole(ctx context.Context, scope, roleDefinitionID string) (RequestResult, error) {
 	requestID := newUUID()
 	deactivationURL := fmt.Sprintf("https://management.azure.com%s/providers/Microsoft.Authorization/roleAssignmentScheduleRequests/%s?api-version=2020-10-01", scope, requestID)
 
 	userID, err := c.GetCurrentUser(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get current user: %w", err)
+		return RequestResult{RequestID: requestID}, fmt.Errorf("failed to get current user: %w", err)
 	}
 
 	body := map[string]interface{}{
@@ -449,15 +534,111 @@ func (c *Client) DeactivateAzureRole(ctx context.Context, scope, roleDefinitionI
 		},
 	}
 
-	_, err = c.armRequestWithBody(ctx, "PUT", deactivationURL, body)
-	return err
+	data, err := c.armRequestWithBody(ctx, "PUT", deactivationURL, body)
+	if err != nil {
+		return RequestResult{RequestID: requestID}, err
+	}
+	return parseARMRequestResult(data, requestID, nil), nil
+}
+
+// ExtendAzureRole requests an extension of an active Azure RBAC role with a SelfExtend
+// roleAssignmentScheduleRequest, keeping it active for duration from now.
+// The returned RequestResult carries the generated request ID even when the request fails.
+func (c *Client) ExtendAzureRole(ctx context.Context, scope, roleDefinitionID, roleEligibilityID, justification string, duration time.Duration) (RequestResult, error) {
+	requestID := newUUID()
+	extendURL := fmt.Sprintf("https://management.azure.com%s/providers/Microsoft.Authorization/roleAssignmentScheduleRequests/%s?api-version=2020-10-01", scope, requestID)
+
+	userID, err := c.GetCurrentUser(ctx)
+	if err != nil {
+		return RequestResult{RequestID: requestID}, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"principalId":                     userID,
+			"roleDefinitionId":                roleDefinitionID,
+			"requestType":                     "SelfExtend",
+			"linkedRoleEligibilityScheduleId": roleEligibilityID,
+			"justification":                   justification,
+			"scheduleInfo": map[string]interface{}{
+				"startDateTime": time.Now().UTC().Format(time.RFC3339),
+				"expiration": map[string]interface{}{
+					"type":     "AfterDuration",
+					"duration": fmt.Sprintf("PT%dM", int(duration.Minutes())),
+				},
+			},
+		},
+	}
+
+	data, err := c.armRequestWithBody(ctx, "PUT", extendURL, body)
+	if err != nil {
+		return RequestResult{RequestID: requestID}, err
+	}
+	return parseARMRequestResult(data, requestID, scheduledExpiry(duration)), nil
+}
+
+// RenewAzureRole requests renewal of an Azure RBAC eligibility with a SelfRenew
+// roleEligibilityScheduleRequest, extending it by duration from now.
+// The returned RequestResult carries the generated request ID even when the request fails.
+func (c *Client) RenewAzureRole(ctx context.Context, scope, roleDefinitionID, justification string, duration time.Duration) (RequestResult, error) {
+	requestID := newUUID()
+	renewURL := fmt.Sprintf("https://management.azure.com%s/providers/Microsoft.Authorization/roleEligibilityScheduleRequests/%s?api-version=2020-10-01", scope, requestID)
+
+	userID, err := c.GetCurrentUser(ctx)
+	if err != nil {
+		return RequestResult{RequestID: requestID}, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"principalId":      userID,
+			"roleDefinitionId": roleDefinitionID,
+			"requestType":      "SelfRenew",
+			"justification":    justification,
+			"scheduleInfo": map[string]interface{}{
+				"startDateTime": time.Now().UTC().Format(time.RFC3339),
+				"expiration": map[string]interface{}{
+					"type":     "AfterDuration",
+					"duration": fmt.Sprintf("P%dD", int(duration.Hours()/24)),
+				},
+			},
+		},
+	}
+
+	data, err := c.armRequestWithBody(ctx, "PUT", renewURL, body)
+	if err != nil {
+		return RequestResult{RequestID: requestID}, err
+	}
+	return parseARMRequestResult(data, requestID, scheduledExpiry(duration)), nil
+}
+
+// parseARMRequestResult extracts the status and end time from a roleAssignmentScheduleRequests
+// response. The request name is the ID we generated; expiresAt is used when no end time is returned.
+func parseARMRequestResult(data []byte, requestID string, expiresAt *time.Time) RequestResult {
+	result := RequestResult{RequestID: requestID, ExpiresAt: expiresAt}
+	var resp struct {
+		Properties struct {
+			Status       string `json:"status"`
+			ScheduleInfo struct {
+				Expiration struct {
+					EndDateTime *time.Time `json:"endDateTime"`
+				} `json:"expiration"`
+			} `json:"scheduleInfo"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return result
+	}
+	result.Status = resp.Properties.Status
+	if resp.Properties.ScheduleInfo.Expiration.EndDateTime != nil {
+		result.ExpiresAt = resp.Properties.ScheduleInfo.Expiration.EndDateTime
+	}
+	return result
 }
 
 // armRequestWithBody makes an ARM API request with a JSON body
 func (c *Client) armRequestWithBody(ctx context.Context, method, reqURL string, body interface{}) ([]byte, error) {
-	token, err := c.cred.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: []string{"https://management.azure.com/.default"},
-	})
+	token, err := c.getToken(ctx, c.cred, "https://management.azure.com/.default")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ARM token: %w", err)
 	}
@@ -509,6 +690,9 @@ func (c *Client) armRequestWithBody(ctx context.Context, method, reqURL string,
 		}
 
 		if resp.StatusCode >= 400 {
+			if claims, ok := parseClaimsChallenge(resp.Header, respBody); ok {
+				return nil, &ClaimsChallengeError{Claims: claims, StatusCode: resp.StatusCode, Body: string(respBody)}
+			}
 			return nil, fmt.Errorf("ARM API error %d: %s", resp.StatusCode, string(respBody))
 		}
 
@@ -74,6 +74,7 @@ func (c *Client) GetEligibleRoles(ctx context.Context) ([]Role, error) {
 			DirectoryScopeID: "/", // Entra roles are tenant-scoped
 			Status:           StatusInactive,
 			MaxDuration:      8 * time.Hour,
+			EligibleUntil:    parseEndDateTime(r.EndDateTime),
 		})
 	}
 
@@ -81,6 +82,16 @@ func (c *Client) GetEligibleRoles(ctx context.Context) ([]Role, error) {
 }
 
 func (c *Client) GetActiveRoles(ctx context.Context) (map[string]*time.Time, error) {
+	assignments, err := c.getActiveRoleAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return activeRoleExpiries(assignments), nil
+}
+
+// getActiveRoleAssignments returns all active Entra role assignments of the current
+// user, both PIM activations and standing assignments
+func (c *Client) getActiveRoleAssignments(ctx context.Context) ([]pimRoleAssignment, error) {
 	userID, err := c.GetCurrentUser(ctx)
 	if err != nil {
 		return nil, err
@@ -108,8 +119,13 @@ func (c *Client) GetActiveRoles(ctx context.Context) (map[string]*time.Time, err
 		reqURL = result.NextLink // Follow pagination until no more pages
 	}
 
+	return allAssignments, nil
+}
+
+// activeRoleExpiries maps role definition IDs to the end of their time-bound activation
+func activeRoleExpiries(assignments []pimRoleAssignment) map[string]*time.Time {
 	active := make(map[string]*time.Time)
-	for _, r := range allAssignments {
+	for _, r := range assignments {
 		if r.EndDateTime != "" {
 			t, err := time.Parse(time.RFC3339, r.EndDateTime)
 			if err == nil {
@@ -117,14 +133,33 @@ func (c *Client) GetActiveRoles(ctx context.Context) (map[string]*time.Time, err
 			}
 		}
 	}
+	return active
+}
 
-	return active, nil
+// standingRoles returns the active assignments without an end date. These were
+// assigned permanently rather than activated through PIM.
+func standingRoles(assignments []pimRoleAssignment) []Role {
+	var roles []Role
+	for _, r := range assignments {
+		if r.EndDateTime != "" {
+			continue
+		}
+		roles = append(roles, Role{
+			ID:               r.ID,
+			DisplayName:      r.RoleDefinition.DisplayName,
+			RoleDefinitionID: r.RoleDefinition.ID,
+			DirectoryScopeID: "/",
+			Status:           StatusActive,
+			Standing:         true,
+		})
+	}
+	return roles
 }
 
 func (c *Client) GetRoles(ctx context.Context) ([]Role, error) {
 	// Fetch eligible and active roles in parallel
 	var eligible []Role
-	var active map[string]*time.Time
+	var assignments []pimRoleAssignment
 	var eligibleErr, activeErr error
 
 	var wg sync.WaitGroup
@@ -135,7 +170,7 @@ func (c *Client) GetRoles(ctx context.Context) ([]Role, error) {
 	}()
 	go func() {
 		defer wg.Done()
-		active, activeErr = c.GetActiveRoles(ctx)
+		assignments, activeErr = c.getActiveRoleAssignments(ctx)
 	}()
 	wg.Wait()
 
@@ -146,6 +181,7 @@ func (c *Client) GetRoles(ctx context.Context) ([]Role, error) {
 		return nil, activeErr
 	}
 
+	active := activeRoleExpiries(assignments)
 	for i := range eligible {
 		if expiry, ok := active[eligible[i].RoleDefinitionID]; ok {
 			eligible[i].ExpiresAt = expiry
@@ -153,19 +189,59 @@ func (c *Client) GetRoles(ctx context.Context) ([]Role, error) {
 		}
 	}
 
+	if c.standingEnabled() {
+		eligible = append(eligible, standingRoles(assignments)...)
+	}
 	return eligible, nil
 }
 
-func (c *Client) ActivateRole(ctx context.Context, roleDefinitionID, directoryScopeID, justification string, duration time.Duration) error {
+// pimRequestResponse is the part of a roleAssignmentRequests response kept for history
+type pimRequestResponse struct {
+	ID     string `json:"id"`
+	Status struct {
+		Status    string `json:"status"`
+		SubStatus string `json:"subStatus"`
+	} `json:"status"`
+	Schedule struct {
+		EndDateTime *time.Time `json:"endDateTime"`
+	} `json:"schedule"`
+}
+
+// parsePIMRequestResult extracts the request ID, status and end time from a PIM API
+// response. expiresAt is used when the response carries no end time.
+func parsePIMRequestResult(data []byte, expiresAt *time.Time) RequestResult {
+	result := RequestResult{ExpiresAt: expiresAt}
+	var resp pimRequestResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return result
+	}
+	result.RequestID = resp.ID
+	result.Status = resp.Status.SubStatus
+	if result.Status == "" {
+		result.Status = resp.Status.Status
+	}
+	if resp.Schedule.EndDateTime != nil {
+		result.ExpiresAt = resp.Schedule.EndDateTime
+	}
+	return result
+}
+
+// scheduledExpiry returns when an activation starting now for duration ends
+func scheduledExpiry(duration time.Duration) *time.Time {
+	expiry := time.Now().Add(duration)
+	return &expiry
+}
+
+func (c *Client) ActivateRole(ctx context.Context, roleDefinitionID, directoryScopeID, justification string, duration time.Duration) (RequestResult, error) {
 	userID, err := c.GetCurrentUser(ctx)
 	if err != nil {
-		return err
+		return RequestResult{}, err
 	}
 
 	// First get the resource ID (tenant ID) for Entra roles
 	tenant, err := c.GetTenant(ctx)
 	if err != nil {
-		return err
+		return RequestResult{}, err
 	}
 
 	minutes := int(duration.Minutes())
@@ -184,20 +260,23 @@ func (c *Client) ActivateRole(ctx context.Context, roleDefinitionID, directorySc
 		},
 	}
 
-	_, err = c.pimRequest(ctx, "POST", pimBaseURL+"/aadroles/roleAssignmentRequests", body)
-	return err
+	data, err := c.pimRequest(ctx, "POST", pimBaseURL+"/aadroles/roleAssignmentRequests", body)
+	if err != nil {
+		return RequestResult{}, err
+	}
+	return parsePIMRequestResult(data, scheduledExpiry(duration)), nil
 }
 
-func (c *Client) DeactivateRole(ctx context.Context, roleDefinitionID, directoryScopeID string) error {
+func (c *Client) DeactivateRole(ctx context.Context, roleDefinitionID, directoryScopeID string) (RequestResult, error) {
 	userID, err := c.GetCurrentUser(ctx)
 	if err != nil {
-		return err
+		return RequestResult{}, err
 	}
 
 	// Get the resource ID (tenant ID) for Entra roles
 	tenant, err := c.GetTenant(ctx)
 	if err != nil {
-		return err
+		return RequestResult{}, err
 	}
 
 	// For deactivation, we use UserRemove type with a minimal schedule
@@ -216,6 +295,78 @@ func (c *Client) DeactivateRole(ctx context.Context, roleDefinitionID, directory
 		},
 	}
 
-	_, err = c.pimRequest(ctx, "POST", pimBaseURL+"/aadroles/roleAssignmentRequests", body)
-	return err
+	data, err := c.pimRequest(ctx, "POST", pimBaseURL+"/aadroles/roleAssignmentRequests", body)
+	if err != nil {
+		return RequestResult{}, err
+	}
+	return parsePIMRequestResult(data, nil), nil
+}
+
+// ExtendRole requests an extension of the current user's active Entra role assignment,
+// keeping it active for duration from now
+func (c *Client) ExtendRole(ctx context.Context, roleDefinitionID, justification string, duration time.Duration) (RequestResult, error) {
+	userID, err := c.GetCurrentUser(ctx)
+	if err != nil {
+		return RequestResult{}, err
+	}
+
+	tenant, err := c.GetTenant(ctx)
+	if err != nil {
+		return RequestResult{}, err
+	}
+
+	body := map[string]interface{}{
+		"roleDefinitionId": roleDefinitionID,
+		"resourceId":       tenant.ID,
+		"subjectId":        userID,
+		"assignmentState":  "Active",
+		"type":             "UserExtend",
+		"reason":           justification,
+		"schedule": map[string]interface{}{
+			"type":          "Once",
+			"startDateTime": time.Now().UTC().Format(time.RFC3339),
+			"duration":      fmt.Sprintf("PT%dM", int(duration.Minutes())),
+		},
+	}
+
+	data, err := c.pimRequest(ctx, "POST", pimBaseURL+"/aadroles/roleAssignmentRequests", body)
+	if err != nil {
+		return RequestResult{}, err
+	}
+	return parsePIMRequestResult(data, scheduledExpiry(duration)), nil
+}
+
+// RenewRole requests renewal of the current user's eligibility for an Entra role,
+// extending it by duration from now. Renewals usually need administrator approval.
+func (c *Client) RenewRole(ctx context.Context, roleDefinitionID, justification string, duration time.Duration) (RequestResult, error) {
+	userID, err := c.GetCurrentUser(ctx)
+	if err != nil {
+		return RequestResult{}, err
+	}
+
+	tenant, err := c.GetTenant(ctx)
+	if err != nil {
+		return RequestResult{}, err
+	}
+
+	start := time.Now().UTC()
+	body := map[string]interface{}{
+		"roleDefinitionId": roleDefinitionID,
+		"resourceId":       tenant.ID,
+		"subjectId":        userID,
+		"assignmentState":  "Eligible",
+		"type":             "UserRenew",
+		"reason":           justification,
+		"schedule": map[string]interface{}{
+			"type":          "Once",
+			"startDateTime": start.Format(time.RFC3339),
+			"endDateTime":   start.Add(duration).Format(time.RFC3339),
+		},
+	}
+
+	data, err := c.pimRequest(ctx, "POST", pimBaseURL+"/aadroles/roleAssignmentRequests", body)
+	if err != nil {
+		return RequestResult{}, err
+	}
+	return parsePIMRequestResult(data, scheduledExpiry(duration)), nil
 }
@@ -0,0 +1,137 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ActivationRequirements is what the PIM policy of a role or group asks for
+// before an activation is granted, beyond a justification
+type ActivationRequirements struct {
+	Approval    bool // An approver must accept the request
+	MFA         bool // The sign-in must have used multi-factor authentication
+	AuthContext bool // A Conditional Access authentication context must be satisfied
+}
+
+// Unattended reports whether an activation can be granted without the user
+// approving a sign-in or waiting for an approver
+func (r ActivationRequirements) Unattended() bool {
+	return !r.Approval && !r.MFA && !r.AuthContext
+}
+
+// String lists the requirements, e.g. "approval, MFA"
+func (r ActivationRequirements) String() string {
+	var parts []string
+	if r.Approval {
+		parts = append(parts, "approval")
+	}
+	if r.MFA {
+		parts = append(parts, "MFA")
+	}
+	if r.AuthContext {
+		parts = append(parts, "authentication context")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// policyRule is one rule of a role management policy. Graph and ARM share the
+// rule IDs and settings this reads.
+type policyRule struct {
+	ID           string   `json:"id"`
+	EnabledRules []string `json:"enabledRules"`
+	IsEnabled    bool     `json:"isEnabled"`
+	Setting      struct {
+		IsApprovalRequired bool `json:"isApprovalRequired"`
+	} `json:"setting"`
+}
+
+// activationRequirements reads the end user activation rules of a policy
+func activationRequirements(rules []policyRule) ActivationRequirements {
+	var req ActivationRequirements
+	for _, rule := range rules {
+		switch rule.ID {
+		case "Approval_EndUser_Assignment":
+			req.Approval = rule.Setting.IsApprovalRequired
+		case "Enablement_EndUser_Assignment":
+			for _, r := range rule.EnabledRules {
+				if r == "MultiFactorAuthentication" {
+					req.MFA = true
+				}
+			}
+		case "AuthenticationContext_EndUser_Assignment":
+			req.AuthContext = rule.IsEnabled
+		}
+	}
+	return req
+}
+
+// graphPolicyAssignments is the Graph response for role management policy
+// assignments expanded with their policy rules
+type graphPolicyAssignments struct {
+	Value []struct {
+		Policy struct {
+			Rules []policyRule `json:"rules"`
+		} `json:"policy"`
+	} `json:"value"`
+}
+
+// GetRoleActivationRequirements returns what activating an Entra role asks for
+func (c *Client) GetRoleActivationRequirements(ctx context.Context, roleDefinitionID, directoryScopeID string) (ActivationRequirements, error) {
+	filter := fmt.Sprintf("scopeId eq '%s' and scopeType eq 'DirectoryRole' and roleDefinitionId eq '%s'", directoryScopeID, roleDefinitionID)
+	return c.graphActivationRequirements(ctx, filter)
+}
+
+// GetGroupActivationRequirements returns what activating a group membership or
+// ownership asks for
+func (c *Client) GetGroupActivationRequirements(ctx context.Context, groupID, roleDefinitionID string) (ActivationRequirements, error) {
+	filter := fmt.Sprintf("scopeId eq '%s' and scopeType eq 'Group' and roleDefinitionId eq '%s'", groupID, roleDefinitionID)
+	return c.graphActivationRequirements(ctx, filter)
+}
+
+func (c *Client) graphActivationRequirements(ctx context.Context, filter string) (ActivationRequirements, error) {
+	reqURL := fmt.Sprintf("%s/policies/roleManagementPolicyAssignments?$filter=%s&$expand=%s",
+		graphBaseURL, url.QueryEscape(filter), url.QueryEscape("policy($expand=rules)"))
+	data, err := c.graphRequest(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return ActivationRequirements{}, err
+	}
+	var resp graphPolicyAssignments
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return ActivationRequirements{}, err
+	}
+	if len(resp.Value) == 0 {
+		return ActivationRequirements{}, fmt.Errorf("no activation policy found")
+	}
+	return activationRequirements(resp.Value[0].Policy.Rules), nil
+}
+
+// GetAzureRoleActivationRequirements returns what activating an Azure RBAC role
+// at scope asks for
+func (c *Client) GetAzureRoleActivationRequirements(ctx context.Context, scope, roleDefinitionID string) (ActivationRequirements, error) {
+	params := url.Values{}
+	params.Set("api-version", "2020-10-01")
+	params.Set("$filter", fmt.Sprintf("roleDefinitionId eq '%s'", roleDefinitionID))
+	reqURL := fmt.Sprintf("https://management.azure.com%s/providers/Microsoft.Authorization/roleManagementPolicyAssignments?%s", scope, params.Encode())
+
+	data, err := c.armRequest(ctx, "GET", reqURL)
+	if err != nil {
+		return ActivationRequirements{}, err
+	}
+	var resp struct {
+		Value []struct {
+			Properties struct {
+				EffectiveRules []policyRule `json:"effectiveRules"`
+			} `json:"properties"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return ActivationRequirements{}, err
+	}
+	if len(resp.Value) == 0 {
+		return ActivationRequirements{}, fmt.Errorf("no activation policy found")
+	}
+	return activationRequirements(resp.Value[0].Properties.EffectiveRules), nil
+}
@@ -0,0 +1,75 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestActivationRequirements tests reading approval, MFA and authentication
+// context rules from Graph and ARM role management policies
+func TestActivationRequirements(t *testing.T) {
+	var graphFilter string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "policies/roleManagementPolicyAssignments"):
+			graphFilter = r.URL.Query().Get("$filter")
+			if strings.Contains(graphFilter, "'Group'") {
+				w.Write([]byte(`{"value": []}`))
+				return
+			}
+			w.Write([]byte(`{"value": [{"policy": {"rules": [
+				{"id": "Approval_EndUser_Assignment", "setting": {"isApprovalRequired": false}},
+				{"id": "Enablement_EndUser_Assignment", "enabledRules": ["Justification", "MultiFactorAuthentication"]},
+				{"id": "AuthenticationContext_EndUser_Assignment", "isEnabled": false}
+			]}}]}`))
+		case strings.Contains(r.URL.Path, "Microsoft.Authorization/roleManagementPolicyAssignments"):
+			w.Write([]byte(`{"value": [{"properties": {"effectiveRules": [
+				{"id": "Approval_EndUser_Assignment", "setting": {"isApprovalRequired": true}},
+				{"id": "Enablement_EndUser_Assignment", "enabledRules": ["Justification"]}
+			]}}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	client.httpClient = &http.Client{
+		Transport: &testTransport{
+			baseURL:    server.URL,
+			realClient: http.DefaultTransport,
+		},
+		Timeout: 5 * time.Second,
+	}
+	ctx := context.Background()
+
+	req, err := client.GetRoleActivationRequirements(ctx, "role-id", "/")
+	if err != nil {
+		t.Fatalf("GetRoleActivationRequirements() error = %v", err)
+	}
+	if want := (ActivationRequirements{MFA: true}); req != want || req.Unattended() {
+		t.Errorf("role requirements = %+v, want %+v", req, want)
+	}
+	if !strings.Contains(graphFilter, "scopeType eq 'DirectoryRole' and roleDefinitionId eq 'role-id'") {
+		t.Errorf("Graph $filter = %q", graphFilter)
+	}
+
+	req, err = client.GetAzureRoleActivationRequirements(ctx, "/subscriptions/s1", "/providers/Microsoft.Authorization/roleDefinitions/reader")
+	if err != nil {
+		t.Fatalf("GetAzureRoleActivationRequirements() error = %v", err)
+	}
+	if req.String() != "approval" || req.Unattended() {
+		t.Errorf("Azure role requirements = %+v, want approval only", req)
+	}
+
+	// A missing policy is an error rather than "nothing required"
+	if _, err := client.GetGroupActivationRequirements(ctx, "group-id", "member"); err == nil {
+		t.Error("GetGroupActivationRequirements() without a policy should fail")
+	}
+}
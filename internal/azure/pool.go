@@ -0,0 +1,73 @@
+package azure
+
+import (
+	"context"
+
+	"github.com/seb07-cloud/pim-tui/internal/pool"
+)
+
+// DefaultConcurrency bounds parallel lookups when no limit is configured
+const DefaultConcurrency = 8
+
+// ProgressFunc receives progress updates for fan-out lookups.
+// stage names the lookup (e.g. "group names"), done counts completed items of total.
+type ProgressFunc func(stage string, done, total int)
+
+// SetConcurrency sets the maximum number of parallel requests used by fan-out
+// lookups such as group, subscription and tenant name resolution.
+// Values below 1 restore DefaultConcurrency.
+func (c *Client) SetConcurrency(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.concurrency = n
+}
+
+// SetProgressFunc registers a callback for fan-out progress. The callback is invoked
+// from worker goroutines and must not block.
+func (c *Client) SetProgressFunc(fn ProgressFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress = fn
+}
+
+func (c *Client) concurrencyLimit() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.concurrency < 1 {
+		return DefaultConcurrency
+	}
+	return c.concurrency
+}
+
+func (c *Client) reportProgress(stage string, done, total int) {
+	c.mu.Lock()
+	fn := c.progress
+	c.mu.Unlock()
+	if fn != nil {
+		fn(stage, done, total)
+	}
+}
+
+// forEachLimit calls fn for every item with at most concurrencyLimit() calls in
+// flight, reporting progress under stage as items complete. It stops handing out
+// work once ctx is cancelled and returns after all started calls have finished.
+func (c *Client) forEachLimit(ctx context.Context, stage string, items []string, fn func(item string)) {
+	c.forEachLimitFrom(ctx, stage, items, 0, len(items), fn)
+}
+
+// forEachLimitFrom is forEachLimit for the last items of a larger stage whose
+// first done of total items were handled some other way, so progress keeps
+// counting against the same total
+func (c *Client) forEachLimitFrom(ctx context.Context, stage string, items []string, done, total int, fn func(item string)) {
+	if len(items) == 0 {
+		return
+	}
+
+	c.reportProgress(stage, done, total)
+	pool.Map(ctx, items, c.concurrencyLimit(), func(_ context.Context, item string) struct{} {
+		fn(item)
+		return struct{}{}
+	}, func(n int) {
+		c.reportProgress(stage, done+n, total)
+	})
+}
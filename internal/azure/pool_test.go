@@ -0,0 +1,98 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEachLimitBoundsConcurrency(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		wantLimit int32
+	}{
+		{name: "configured limit", limit: 3, wantLimit: 3},
+		{name: "zero uses default", limit: 0, wantLimit: DefaultConcurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient("")
+			client.SetConcurrency(tt.limit)
+
+			items := make([]string, 40)
+			for i := range items {
+				items[i] = fmt.Sprintf("item-%d", i)
+			}
+
+			var inFlight, peak, calls int32
+			client.forEachLimit(context.Background(), "test", items, func(string) {
+				n := atomic.AddInt32(&inFlight, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(2 * time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				atomic.AddInt32(&calls, 1)
+			})
+
+			if calls != int32(len(items)) {
+				t.Errorf("fn called %d times, want %d", calls, len(items))
+			}
+			if peak > tt.wantLimit {
+				t.Errorf("peak concurrency = %d, want <= %d", peak, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestForEachLimitReportsProgress(t *testing.T) {
+	client := newTestClient("")
+
+	var mu sync.Mutex
+	var updates []int
+	client.SetProgressFunc(func(stage string, done, total int) {
+		if stage != "names" || total != 5 {
+			t.Errorf("unexpected progress stage=%q total=%d", stage, total)
+		}
+		mu.Lock()
+		updates = append(updates, done)
+		mu.Unlock()
+	})
+
+	client.forEachLimit(context.Background(), "names", []string{"a", "b", "c", "d", "e"}, func(string) {})
+
+	if len(updates) != 6 {
+		t.Fatalf("got %d progress updates, want 6 (initial + one per item)", len(updates))
+	}
+	maxDone := 0
+	for _, d := range updates {
+		maxDone = max(maxDone, d)
+	}
+	if updates[0] != 0 || maxDone != 5 {
+		t.Errorf("progress updates = %v, want start at 0 and reach 5", updates)
+	}
+}
+
+func TestForEachLimitStopsOnCancel(t *testing.T) {
+	client := newTestClient("")
+	client.SetConcurrency(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+	client.forEachLimit(ctx, "test", []string{"a", "b", "c", "d"}, func(string) {
+		atomic.AddInt32(&calls, 1)
+		cancel()
+	})
+
+	if calls >= 4 {
+		t.Errorf("fn called %d times after cancel, want fewer than 4", calls)
+	}
+}
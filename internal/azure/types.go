@@ -1,6 +1,9 @@
 package azure
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type ActivationStatus int
 
@@ -40,6 +43,59 @@ func StatusFromExpiry(expiry *time.Time) ActivationStatus {
 	return StatusActive
 }
 
+// parseEndDateTime parses an RFC 3339 end date, returning nil for empty or
+// malformed values (no end date means the assignment is permanent)
+func parseEndDateTime(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// EligibilityExpiringWithin reports whether an eligibility ending at endsAt runs out within window.
+// Permanent eligibilities (nil endsAt) never expire.
+func EligibilityExpiringWithin(endsAt *time.Time, window time.Duration) bool {
+	return endsAt != nil && time.Until(*endsAt) < window
+}
+
+// RequestResult describes a submitted activation or deactivation request
+type RequestResult struct {
+	RequestID string     // ID of the request as assigned by the API
+	Status    string     // Request status, e.g. "Provisioned" or "PendingApproval"
+	ExpiresAt *time.Time // Scheduled end of the assignment, nil for deactivations
+}
+
+// AuditRecord is one PIM request made for the current user, as recorded by Azure.
+// It covers requests made from any machine or from the portal.
+type AuditRecord struct {
+	RequestID     string
+	Source        string // "role", "group" or "azure-role"
+	Action        string // Request action as reported by the API, e.g. "selfActivate"
+	Time          time.Time
+	Name          string // Role or group display name
+	Scope         string // Directory scope, group ID or Azure resource scope
+	Status        string // e.g. "Provisioned", "Denied" or "Failed"
+	Justification string
+	StartsAt      *time.Time
+	ExpiresAt     *time.Time
+}
+
+// Failed reports whether the request was denied, canceled or failed.
+// "Revoked" is the normal outcome of a deactivation and is not a failure.
+func (r AuditRecord) Failed() bool {
+	status := strings.ToLower(r.Status)
+	for _, s := range []string{"fail", "denied", "cancel", "timedout"} {
+		if strings.Contains(status, s) {
+			return true
+		}
+	}
+	return false
+}
+
 type Tenant struct {
 	ID          string
 	DisplayName string
@@ -54,7 +110,9 @@ type Role struct {
 	Status           ActivationStatus
 	ExpiresAt        *time.Time
 	MaxDuration      time.Duration
-	Permissions      []string // Permission actions for this role
+	Permissions      []string   // Permission actions for this role
+	Standing         bool       // Permanent active assignment, not activated through PIM
+	EligibleUntil    *time.Time // End of the eligibility itself, nil if permanent
 }
 
 type Group struct {
@@ -67,6 +125,8 @@ type Group struct {
 	MaxDuration      time.Duration
 	LinkedRoles      []LinkedRole      // Entra ID roles tied to this group
 	LinkedAzureRBac  []LinkedAzureRole // Azure RBAC roles tied to this group
+	Standing         bool              // Permanent membership/ownership, not activated through PIM
+	EligibleUntil    *time.Time        // End of the eligibility itself, nil if permanent
 }
 
 // LinkedRole represents an Entra ID role assignment linked to a group
@@ -103,4 +163,6 @@ type EligibleAzureRole struct {
 	Scope              string // Subscription or resource group scope
 	Status             ActivationStatus
 	ExpiresAt          *time.Time
+	Standing           bool       // Permanent assignment, not activated through PIM
+	EligibleUntil      *time.Time // End of the eligibility itself, nil if permanent
 }
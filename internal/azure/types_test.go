@@ -145,3 +145,35 @@ func TestActivationStatus_IsActive(t *testing.T) {
 		})
 	}
 }
+
+// TestEligibilityExpiringWithin tests the eligibility end date warning window
+func TestEligibilityExpiringWithin(t *testing.T) {
+	now := time.Now()
+	window := 14 * 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		endsAt *time.Time
+		want   bool
+	}{
+		{"permanent eligibility never expires", nil, false},
+		{"ends after the window", timePtr(now.Add(30 * 24 * time.Hour)), false},
+		{"ends inside the window", timePtr(now.Add(3 * 24 * time.Hour)), true},
+		{"already ended", timePtr(now.Add(-time.Hour)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EligibilityExpiringWithin(tt.endsAt, window); got != tt.want {
+				t.Errorf("EligibilityExpiringWithin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := parseEndDateTime(""); got != nil {
+		t.Errorf("parseEndDateTime(\"\") = %v, want nil", got)
+	}
+	if got := parseEndDateTime("2026-03-01T00:00:00Z"); got == nil || got.Year() != 2026 {
+		t.Errorf("parseEndDateTime() = %v, want 2026-03-01", got)
+	}
+}
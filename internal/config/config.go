@@ -17,13 +17,37 @@ type ThemeConfig struct {
 	ColorBorder    string `yaml:"color_border"`
 }
 
+// NotificationConfig controls alerts before elevations expire and when requests
+// are approved or fail. Sinks are any of "bell", "osc9", "osc777", "desktop" and
+// "command"; the command runs through the shell with the event in its environment.
+type NotificationConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	Sinks      []string `yaml:"sinks"`
+	Thresholds []int    `yaml:"thresholds"` // Minutes before expiry to notify at
+	OnApproval bool     `yaml:"on_approval"`
+	OnFailure  bool     `yaml:"on_failure"`
+	Command    string   `yaml:"command"`
+}
+
 type Config struct {
-	DefaultDuration     int         `yaml:"default_duration"`
-	DurationPresets     []int       `yaml:"duration_presets"`
-	LogLevel            string      `yaml:"log_level"`
-	AutoRefreshInterval int         `yaml:"auto_refresh_interval"`
-	AutoRefreshEnabled  bool        `yaml:"auto_refresh_enabled"`
-	Theme               ThemeConfig `yaml:"theme"`
+	DefaultDuration       int                            `yaml:"default_duration"`
+	DurationPresets       []int                          `yaml:"duration_presets"`
+	LogLevel              string                         `yaml:"log_level"`
+	AutoRefreshInterval   int                            `yaml:"auto_refresh_interval"`
+	AutoRefreshEnabled    bool                           `yaml:"auto_refresh_enabled"`
+	MaxConcurrency        int                            `yaml:"max_concurrency"`          // Parallel requests for name lookups
+	MetadataCacheTTL      int                            `yaml:"metadata_cache_ttl"`       // Hours to cache group/tenant names; 0 disables
+	ActivationConcurrency int                            `yaml:"activation_concurrency"`   // Parallel activation/deactivation requests
+	HistoryRetentionDays  int                            `yaml:"history_retention_days"`   // Days of history kept on disk; 0 keeps all
+	HistoryMaxEntries     int                            `yaml:"history_max_entries"`      // Max history entries kept on disk; 0 keeps all
+	ShowStanding          bool                           `yaml:"show_standing"`            // List permanent active assignments too
+	EligibilityWarnDays   int                            `yaml:"eligibility_warn_days"`    // Highlight eligibilities ending within this many days
+	RenewalDays           int                            `yaml:"renewal_days"`             // Eligibility length requested by renewals
+	AutoExtendLeadMinutes int                            `yaml:"auto_extend_lead_minutes"` // Extend auto-extended elevations this long before expiry
+	AutoExtendMaxHours    int                            `yaml:"auto_extend_max_hours"`    // Longest an auto-extend rule may keep an elevation alive
+	Notifications         NotificationConfig             `yaml:"notifications"`
+	KeyBindings           map[string]map[string][]string `yaml:"keybindings"` // Keymap -> action -> keys; overrides the defaults
+	Theme                 ThemeConfig                    `yaml:"theme"`
 }
 
 func DefaultTheme() ThemeConfig {
@@ -38,14 +62,34 @@ func DefaultTheme() ThemeConfig {
 	}
 }
 
+func DefaultNotifications() NotificationConfig {
+	return NotificationConfig{
+		Enabled:    true,
+		Sinks:      []string{"bell"},
+		Thresholds: []int{15, 5},
+		OnApproval: true,
+		OnFailure:  true,
+	}
+}
+
 func Default() Config {
 	return Config{
-		DefaultDuration:     4,
-		DurationPresets:     []int{1, 2, 4, 8},
-		LogLevel:            "info",
-		AutoRefreshInterval: 60,
-		AutoRefreshEnabled:  true,
-		Theme:               DefaultTheme(),
+		DefaultDuration:       4,
+		DurationPresets:       []int{1, 2, 4, 8},
+		LogLevel:              "info",
+		AutoRefreshInterval:   60,
+		AutoRefreshEnabled:    true,
+		MaxConcurrency:        8,
+		MetadataCacheTTL:      24,
+		ActivationConcurrency: 4,
+		HistoryRetentionDays:  365,
+		HistoryMaxEntries:     10000,
+		EligibilityWarnDays:   14,
+		RenewalDays:           365,
+		AutoExtendLeadMinutes: 10,
+		AutoExtendMaxHours:    12,
+		Notifications:         DefaultNotifications(),
+		Theme:                 DefaultTheme(),
 	}
 }
 
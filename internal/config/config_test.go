@@ -40,6 +40,66 @@ func TestDefault(t *testing.T) {
 			got:      cfg.AutoRefreshEnabled,
 			expected: true,
 		},
+		{
+			name:     "MaxConcurrency is 8",
+			got:      cfg.MaxConcurrency,
+			expected: 8,
+		},
+		{
+			name:     "MetadataCacheTTL is 24",
+			got:      cfg.MetadataCacheTTL,
+			expected: 24,
+		},
+		{
+			name:     "ActivationConcurrency is 4",
+			got:      cfg.ActivationConcurrency,
+			expected: 4,
+		},
+		{
+			name:     "HistoryRetentionDays is 365",
+			got:      cfg.HistoryRetentionDays,
+			expected: 365,
+		},
+		{
+			name:     "HistoryMaxEntries is 10000",
+			got:      cfg.HistoryMaxEntries,
+			expected: 10000,
+		},
+		{
+			name:     "ShowStanding is false",
+			got:      cfg.ShowStanding,
+			expected: false,
+		},
+		{
+			name:     "EligibilityWarnDays is 14",
+			got:      cfg.EligibilityWarnDays,
+			expected: 14,
+		},
+		{
+			name:     "RenewalDays is 365",
+			got:      cfg.RenewalDays,
+			expected: 365,
+		},
+		{
+			name:     "AutoExtendLeadMinutes is 10",
+			got:      cfg.AutoExtendLeadMinutes,
+			expected: 10,
+		},
+		{
+			name:     "AutoExtendMaxHours is 12",
+			got:      cfg.AutoExtendMaxHours,
+			expected: 12,
+		},
+		{
+			name:     "Notifications ring the bell at 15 and 5 minutes",
+			got:      cfg.Notifications,
+			expected: NotificationConfig{Enabled: true, Sinks: []string{"bell"}, Thresholds: []int{15, 5}, OnApproval: true, OnFailure: true},
+		},
+		{
+			name:     "KeyBindings has no overrides",
+			got:      cfg.KeyBindings,
+			expected: map[string]map[string][]string(nil),
+		},
 	}
 
 	for _, tt := range tests {
@@ -261,3 +321,75 @@ theme:
 	// This is because the defaults are set in Default() but unmarshal overwrites the struct
 	// The behavior is that the config file's theme replaces the entire Theme struct if present
 }
+
+func TestLoad_Notifications(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tempDir)
+
+	configDir := filepath.Join(tempDir, "pim-tui")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+
+	configContent := `
+notifications:
+  sinks: [osc777, command]
+  thresholds: [30]
+  command: "logger pim-tui"
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+
+	want := NotificationConfig{
+		Enabled:    true, // Not set in the file, kept from defaults
+		Sinks:      []string{"osc777", "command"},
+		Thresholds: []int{30},
+		OnApproval: true,
+		OnFailure:  true,
+		Command:    "logger pim-tui",
+	}
+	if !reflect.DeepEqual(cfg.Notifications, want) {
+		t.Errorf("Load() Notifications = %+v, want %+v", cfg.Notifications, want)
+	}
+}
+
+func TestLoad_KeyBindings(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tempDir)
+
+	configDir := filepath.Join(tempDir, "pim-tui")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+
+	configContent := `
+keybindings:
+  normal:
+    deactivate: [x, backspace]
+    renew: []
+  history:
+    close: [esc]
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+
+	want := map[string]map[string][]string{
+		"normal":  {"deactivate": {"x", "backspace"}, "renew": {}},
+		"history": {"close": {"esc"}},
+	}
+	if !reflect.DeepEqual(cfg.KeyBindings, want) {
+		t.Errorf("Load() KeyBindings = %v, want %v", cfg.KeyBindings, want)
+	}
+}
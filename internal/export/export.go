@@ -0,0 +1,259 @@
+// Package export writes activation history and the eligibility inventory to
+// CSV, JSON or Markdown.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+	"github.com/seb07-cloud/pim-tui/internal/history"
+)
+
+// Format is an export file format
+type Format string
+
+const (
+	FormatCSV      Format = "csv"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "md"
+)
+
+// Formats lists the supported formats in display order
+var Formats = []Format{FormatCSV, FormatJSON, FormatMarkdown}
+
+// ParseFormat parses a format name such as "csv", "json", "md" or "markdown"
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(s, ".")) {
+	case "csv":
+		return FormatCSV, nil
+	case "json":
+		return FormatJSON, nil
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (want csv, json or md)", s)
+}
+
+// InventoryItem is one eligible role, group or Azure role assignment
+type InventoryItem struct {
+	Type          string     `json:"type"` // "role", "group" or "azure-role"
+	Name          string     `json:"name"`
+	Scope         string     `json:"scope,omitempty"`
+	Subscription  string     `json:"subscription,omitempty"`
+	Tenant        string     `json:"tenant,omitempty"`
+	Status        string     `json:"status"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	EligibleUntil *time.Time `json:"eligible_until,omitempty"` // End of the eligibility, empty if permanent
+}
+
+// Inventory flattens loaded eligibilities into export rows
+func Inventory(roles []azure.Role, groups []azure.Group, subs []azure.LighthouseSubscription) []InventoryItem {
+	var items []InventoryItem
+	for _, r := range roles {
+		items = append(items, InventoryItem{
+			Type:          "role",
+			Name:          r.DisplayName,
+			Scope:         r.DirectoryScopeID,
+			Status:        r.Status.String(),
+			ExpiresAt:     r.ExpiresAt,
+			EligibleUntil: r.EligibleUntil,
+		})
+	}
+	for _, g := range groups {
+		items = append(items, InventoryItem{
+			Type:          "group",
+			Name:          g.DisplayName,
+			Scope:         g.ID,
+			Status:        g.Status.String(),
+			ExpiresAt:     g.ExpiresAt,
+			EligibleUntil: g.EligibleUntil,
+		})
+	}
+	for _, s := range subs {
+		for _, r := range s.EligibleRoles {
+			items = append(items, InventoryItem{
+				Type:          "azure-role",
+				Name:          r.RoleDefinitionName,
+				Scope:         r.Scope,
+				Subscription:  s.DisplayName,
+				Tenant:        s.TenantName,
+				Status:        r.Status.String(),
+				ExpiresAt:     r.ExpiresAt,
+				EligibleUntil: r.EligibleUntil,
+			})
+		}
+	}
+	return items
+}
+
+var historyHeader = []string{"Time", "Kind", "Type", "Name", "Scope", "Duration", "Justification", "Success", "Error", "Request ID", "Status", "Expires"}
+
+func historyRow(e history.Entry) []string {
+	duration := ""
+	if e.Duration > 0 {
+		duration = e.Duration.String()
+	}
+	return []string{
+		e.Time.Format(time.RFC3339), e.Kind, e.Type, e.Name, e.Scope, duration,
+		e.Justification, fmt.Sprintf("%v", e.Success), e.Error, e.RequestID, e.Status, formatTime(e.ExpiresAt),
+	}
+}
+
+var inventoryHeader = []string{"Type", "Name", "Scope", "Subscription", "Tenant", "Status", "Expires", "Eligible Until"}
+
+func inventoryRow(i InventoryItem) []string {
+	return []string{i.Type, i.Name, i.Scope, i.Subscription, i.Tenant, i.Status, formatTime(i.ExpiresAt), formatTime(i.EligibleUntil)}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// WriteHistory writes history entries to w in the given format
+func WriteHistory(w io.Writer, format Format, entries []history.Entry) error {
+	if format == FormatJSON {
+		if entries == nil {
+			entries = []history.Entry{}
+		}
+		return writeJSON(w, entries)
+	}
+	rows := make([][]string, len(entries))
+	for i, e := range entries {
+		rows[i] = historyRow(e)
+	}
+	return writeTable(w, format, "Activation History", historyHeader, rows)
+}
+
+// WriteInventory writes inventory items to w in the given format
+func WriteInventory(w io.Writer, format Format, items []InventoryItem) error {
+	if format == FormatJSON {
+		if items == nil {
+			items = []InventoryItem{}
+		}
+		return writeJSON(w, items)
+	}
+	rows := make([][]string, len(items))
+	for i, item := range items {
+		rows[i] = inventoryRow(item)
+	}
+	return writeTable(w, format, "Eligibility Inventory", inventoryHeader, rows)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeTable(w io.Writer, format Format, title string, header []string, rows [][]string) error {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	case FormatMarkdown:
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %s\n\nGenerated: %s\n\n", title, time.Now().Format(time.RFC3339))
+		b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+		b.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+		for _, row := range rows {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = markdownEscape(cell)
+			}
+			b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+// markdownEscape keeps cell content from breaking the table layout
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", " ")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// WriteFile creates path (and its directory) and writes it with write.
+// A leading "~/" is expanded to the home directory.
+func WriteFile(path string, write func(io.Writer) error) (string, error) {
+	path, err := ExpandPath(path)
+	if err != nil {
+		return "", err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return "", err
+		}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// ExpandPath expands a leading "~/" to the user's home directory
+func ExpandPath(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("export path is empty")
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find home dir: %w", err)
+		}
+		return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+	}
+	return path, nil
+}
+
+// DefaultFileName returns a dated file name such as "pim-tui-history-20260102.csv"
+func DefaultFileName(what string, format Format, now time.Time) string {
+	return fmt.Sprintf("pim-tui-%s-%s.%s", what, now.Format("20060102"), format)
+}
+
+// DateRange builds a history filter from inclusive YYYY-MM-DD dates in the local
+// time zone. Empty strings leave that end open.
+func DateRange(from, to string) (history.Filter, error) {
+	var f history.Filter
+	if from != "" {
+		since, err := time.ParseInLocation("2006-01-02", from, time.Local)
+		if err != nil {
+			return f, fmt.Errorf("invalid from date %q: want YYYY-MM-DD", from)
+		}
+		f.Since = since
+	}
+	if to != "" {
+		until, err := time.ParseInLocation("2006-01-02", to, time.Local)
+		if err != nil {
+			return f, fmt.Errorf("invalid to date %q: want YYYY-MM-DD", to)
+		}
+		f.Until = until.AddDate(0, 0, 1)
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && !f.Since.Before(f.Until) {
+		return f, fmt.Errorf("from date %s is after to date %s", from, to)
+	}
+	return f, nil
+}
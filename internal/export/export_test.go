@@ -0,0 +1,164 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seb07-cloud/pim-tui/internal/history"
+)
+
+// TestParseFormat tests format names and aliases
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{"csv", FormatCSV, false},
+		{"JSON", FormatJSON, false},
+		{"md", FormatMarkdown, false},
+		{"markdown", FormatMarkdown, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWriteHistory tests each output format for history entries
+func TestWriteHistory(t *testing.T) {
+	entries := []history.Entry{
+		{Time: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC), Kind: history.KindActivate, Name: "Reader | Team", Success: true},
+		{Time: time.Date(2026, 1, 3, 10, 0, 0, 0, time.UTC), Kind: history.KindDeactivate, Name: "Owner", Error: "denied"},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteHistory(&buf, FormatCSV, entries); err != nil {
+			t.Fatalf("WriteHistory() error = %v", err)
+		}
+		records, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid CSV: %v", err)
+		}
+		if len(records) != 3 {
+			t.Errorf("records = %d, want 3 (header + 2)", len(records))
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteHistory(&buf, FormatJSON, entries); err != nil {
+			t.Fatalf("WriteHistory() error = %v", err)
+		}
+		var got []history.Entry
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if len(got) != 2 || got[1].Error != "denied" {
+			t.Errorf("decoded = %+v, want both entries", got)
+		}
+	})
+
+	t.Run("json empty is array", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteHistory(&buf, FormatJSON, nil); err != nil {
+			t.Fatalf("WriteHistory() error = %v", err)
+		}
+		if got := strings.TrimSpace(buf.String()); got != "[]" {
+			t.Errorf("output = %q, want []", got)
+		}
+	})
+
+	t.Run("markdown escapes pipes", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteHistory(&buf, FormatMarkdown, entries); err != nil {
+			t.Fatalf("WriteHistory() error = %v", err)
+		}
+		if !strings.Contains(buf.String(), `Reader \| Team`) {
+			t.Errorf("output does not escape pipe:\n%s", buf.String())
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		if err := WriteHistory(io.Discard, Format("xml"), entries); err == nil {
+			t.Error("WriteHistory() expected error for unknown format")
+		}
+	})
+}
+
+// TestWriteFile tests directory creation, permissions and ~ expansion
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "out.csv")
+
+	written, err := WriteFile(path, func(w io.Writer) error {
+		_, err := io.WriteString(w, "hello")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if written != path {
+		t.Errorf("written = %q, want %q", written, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "hello" {
+		t.Errorf("file = %q, %v; want hello", data, err)
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	if _, err := WriteFile("", func(io.Writer) error { return nil }); err == nil {
+		t.Error("WriteFile(\"\") expected error")
+	}
+
+	t.Setenv("HOME", dir)
+	got, err := ExpandPath("~/x.md")
+	if err != nil || got != filepath.Join(dir, "x.md") {
+		t.Errorf("ExpandPath(~/x.md) = %q, %v; want %q", got, err, filepath.Join(dir, "x.md"))
+	}
+}
+
+// TestDateRange tests inclusive date parsing for export filters
+func TestDateRange(t *testing.T) {
+	f, err := DateRange("2026-01-02", "2026-01-03")
+	if err != nil {
+		t.Fatalf("DateRange() error = %v", err)
+	}
+	inside := history.Entry{Time: time.Date(2026, 1, 3, 23, 0, 0, 0, time.Local)}
+	outside := history.Entry{Time: time.Date(2026, 1, 4, 0, 0, 0, 0, time.Local)}
+	if !f.Match(inside) {
+		t.Error("entry on the to date should match")
+	}
+	if f.Match(outside) {
+		t.Error("entry after the to date should not match")
+	}
+
+	if f, err := DateRange("", ""); err != nil || !f.IsZero() {
+		t.Errorf("DateRange(\"\", \"\") = %+v, %v; want zero filter", f, err)
+	}
+	if _, err := DateRange("01/02/2026", ""); err == nil {
+		t.Error("DateRange() expected error for bad date")
+	}
+	if _, err := DateRange("2026-02-01", "2026-01-01"); err == nil {
+		t.Error("DateRange() expected error for reversed range")
+	}
+}
@@ -0,0 +1,61 @@
+package history
+
+import (
+	"strings"
+	"time"
+)
+
+// Outcome filter values
+const (
+	OutcomeAny     = ""
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Filter selects history entries. Zero values match everything.
+type Filter struct {
+	Since   time.Time // Entries at or after this time
+	Until   time.Time // Entries before this time
+	Kind    string    // KindActivate, KindDeactivate, KindExtend or KindRenew
+	Name    string    // Case-insensitive substring of the entry name
+	Outcome string    // OutcomeSuccess or OutcomeFailure
+}
+
+// Match reports whether e passes the filter
+func (f Filter) Match(e Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	if f.Kind != "" && e.Kind != f.Kind {
+		return false
+	}
+	if f.Name != "" && !strings.Contains(strings.ToLower(e.Name), strings.ToLower(f.Name)) {
+		return false
+	}
+	switch f.Outcome {
+	case OutcomeSuccess:
+		return e.Success
+	case OutcomeFailure:
+		return !e.Success
+	}
+	return true
+}
+
+// Apply returns the entries that pass the filter, keeping their order
+func (f Filter) Apply(entries []Entry) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if f.Match(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// IsZero reports whether the filter matches everything
+func (f Filter) IsZero() bool {
+	return f == Filter{}
+}
@@ -0,0 +1,54 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFilterMatch tests filtering by date, kind, name and outcome
+func TestFilterMatch(t *testing.T) {
+	now := time.Now()
+	entry := Entry{Time: now, Kind: KindActivate, Name: "Global Administrator", Success: true}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"zero filter matches", Filter{}, true},
+		{"since before entry", Filter{Since: now.Add(-time.Hour)}, true},
+		{"since after entry", Filter{Since: now.Add(time.Hour)}, false},
+		{"until after entry", Filter{Until: now.Add(time.Hour)}, true},
+		{"until at entry excludes it", Filter{Until: now}, false},
+		{"matching kind", Filter{Kind: KindActivate}, true},
+		{"other kind", Filter{Kind: KindDeactivate}, false},
+		{"name substring ignores case", Filter{Name: "global admin"}, true},
+		{"name mismatch", Filter{Name: "reader"}, false},
+		{"success outcome", Filter{Outcome: OutcomeSuccess}, true},
+		{"failure outcome", Filter{Outcome: OutcomeFailure}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(entry); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFilterApply tests that Apply keeps matching entries in order
+func TestFilterApply(t *testing.T) {
+	entries := []Entry{
+		{Name: "a", Success: true},
+		{Name: "b", Success: false},
+		{Name: "c", Success: true},
+	}
+	got := Filter{Outcome: OutcomeSuccess}.Apply(entries)
+	if len(got) != 2 || got[0].Name != "a" || got[1].Name != "c" {
+		t.Errorf("Apply() = %+v, want [a c]", got)
+	}
+	if !(Filter{}).IsZero() || (Filter{Name: "x"}).IsZero() {
+		t.Error("IsZero() mismatch")
+	}
+}
@@ -0,0 +1,194 @@
+// Package history persists the activation audit trail as JSON Lines.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seb07-cloud/pim-tui/internal/fsutil"
+)
+
+// Entry kinds
+const (
+	KindActivate   = "activate"
+	KindDeactivate = "deactivate"
+	KindExtend     = "extend"
+	KindRenew      = "renew" // Eligibility renewal request
+)
+
+// Entry records the outcome of one activation, deactivation or extension request
+type Entry struct {
+	Time          time.Time     `json:"time"`
+	Kind          string        `json:"kind"` // KindActivate, KindDeactivate, KindExtend or KindRenew
+	Type          string        `json:"type"` // "role", "group" or "azure-role"
+	Name          string        `json:"name"`
+	Scope         string        `json:"scope,omitempty"` // Directory scope, group ID or Azure resource scope
+	Duration      time.Duration `json:"duration,omitempty"`
+	Justification string        `json:"justification,omitempty"`
+	Success       bool          `json:"success"`
+	Error         string        `json:"error,omitempty"`      // Failure reason when Success is false
+	RequestID     string        `json:"request_id,omitempty"` // Request ID returned by the API
+	Status        string        `json:"status,omitempty"`     // Request status reported by the API
+	ExpiresAt     *time.Time    `json:"expires_at,omitempty"` // Scheduled end of the assignment
+	Remote        bool          `json:"remote,omitempty"`     // Read from the Azure audit trail, not recorded locally
+}
+
+// Retention limits how much history is kept on disk. Zero values keep everything.
+type Retention struct {
+	MaxAge     time.Duration
+	MaxEntries int
+}
+
+// Store appends entries to a JSON Lines file
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// StateDir returns the pim-tui directory in the XDG state directory
+// ($XDG_STATE_HOME/pim-tui, defaulting to ~/.local/state).
+func StateDir() (string, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find home dir: %w", err)
+		}
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateDir, "pim-tui"), nil
+}
+
+// DefaultPath returns the history file in StateDir
+func DefaultPath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// NewStore returns a store writing to path
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the file the store writes to
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append writes entries to the end of the history file
+func (s *Store) Append(entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads all entries oldest first, applying retention. When retention drops
+// entries the file is rewritten without them. Malformed lines are skipped but
+// kept in the file, since they may still hold audit records a later version
+// can read. A missing file yields no entries.
+func (s *Store) Load(retention Retention) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []Entry
+	var malformed []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			malformed = append(malformed, line)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	kept := applyRetention(entries, retention, time.Now())
+	if len(kept) < len(entries) {
+		if err := s.rewrite(kept, malformed); err != nil {
+			return kept, err
+		}
+	}
+	return kept, nil
+}
+
+// rewrite replaces the file contents with the malformed lines, unchanged,
+// followed by entries; caller holds s.mu
+func (s *Store) rewrite(entries []Entry, malformed []string) error {
+	var buf bytes.Buffer
+	for _, line := range malformed {
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return fsutil.WriteFileAtomic(s.path, buf.Bytes())
+}
+
+func applyRetention(entries []Entry, retention Retention, now time.Time) []Entry {
+	if retention.MaxAge > 0 {
+		cutoff := now.Add(-retention.MaxAge)
+		kept := entries[:0]
+		for _, e := range entries {
+			if !e.Time.Before(cutoff) {
+				kept = append(kept, e)
+			}
+		}
+		entries = kept
+	}
+	if retention.MaxEntries > 0 && len(entries) > retention.MaxEntries {
+		entries = entries[len(entries)-retention.MaxEntries:]
+	}
+	return entries
+}
@@ -0,0 +1,113 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestStoreAppendLoad tests that appended entries round-trip through the file
+func TestStoreAppendLoad(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "pim-tui", "history.jsonl"))
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	if err := store.Append(
+		Entry{Time: time.Now(), Kind: KindActivate, Name: "Reader", Success: true, RequestID: "req-1", ExpiresAt: &expiry},
+		Entry{Time: time.Now(), Kind: KindDeactivate, Name: "Reader", Success: false, Error: "too short"},
+	); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := store.Append(Entry{Time: time.Now(), Kind: KindActivate, Name: "Owner", Success: true}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	entries, err := store.Load(Retention{})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Load() returned %d entries, want 3", len(entries))
+	}
+	if entries[0].RequestID != "req-1" || entries[0].ExpiresAt == nil || !entries[0].ExpiresAt.Equal(expiry) {
+		t.Errorf("entries[0] = %+v, want request ID and expiry preserved", entries[0])
+	}
+	if entries[1].Error != "too short" || entries[1].Success {
+		t.Errorf("entries[1] = %+v, want failure preserved", entries[1])
+	}
+	if entries[2].Name != "Owner" {
+		t.Errorf("entries[2].Name = %q, want %q", entries[2].Name, "Owner")
+	}
+}
+
+// TestStoreLoadMissingFile tests that a missing history file is not an error
+func TestStoreLoadMissingFile(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "missing.jsonl"))
+	entries, err := store.Load(Retention{})
+	if err != nil || entries != nil {
+		t.Errorf("Load() = %v, %v, want nil, nil", entries, err)
+	}
+}
+
+// TestStoreLoadRetention tests that retention drops old entries and compacts the file
+func TestStoreLoadRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	store := NewStore(path)
+	now := time.Now()
+	if err := store.Append(
+		Entry{Time: now.AddDate(0, 0, -100), Name: "ancient"},
+		Entry{Time: now.AddDate(0, 0, -2), Name: "old"},
+		Entry{Time: now.AddDate(0, 0, -1), Name: "recent"},
+		Entry{Time: now, Name: "newest"},
+	); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	// Malformed lines are skipped but survive compaction
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	f.WriteString("not json\n")
+	f.Close()
+
+	entries, err := store.Load(Retention{MaxAge: 30 * 24 * time.Hour, MaxEntries: 2})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name != "recent" || entries[1].Name != "newest" {
+		t.Errorf("Load() = %+v, want [recent newest]", entries)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 3 || !strings.HasPrefix(string(data), "not json\n") {
+		t.Errorf("file after compaction = %q, want the malformed line and 2 entries", data)
+	}
+
+	// Without anything to drop the file is left as it is
+	f, _ = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	f.WriteString("{truncated\n")
+	f.Close()
+	before, _ := os.ReadFile(path)
+	if entries, err := store.Load(Retention{}); err != nil || len(entries) != 2 {
+		t.Fatalf("Load() = %d entries, %v; want 2", len(entries), err)
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Errorf("file rewritten without retention: %q, want %q", after, before)
+	}
+}
+
+// TestDefaultPath tests that the history file lives in the XDG state directory
+func TestDefaultPath(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateDir)
+
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath() error = %v", err)
+	}
+	want := filepath.Join(stateDir, "pim-tui", "history.jsonl")
+	if path != want {
+		t.Errorf("DefaultPath() = %q, want %q", path, want)
+	}
+}
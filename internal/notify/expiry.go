@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"sort"
+	"time"
+)
+
+// ExpiryTracker decides when an active elevation crosses a notification
+// threshold, firing each threshold at most once per elevation. An extension
+// changes the expiry time and so starts a fresh set of thresholds.
+type ExpiryTracker struct {
+	thresholds []time.Duration // Descending
+	fired      map[expiryKey]time.Duration
+}
+
+type expiryKey struct {
+	id        string
+	expiresAt time.Time
+}
+
+// NewExpiryTracker returns a tracker for the given thresholds in minutes;
+// non-positive values are ignored
+func NewExpiryTracker(minutes []int) *ExpiryTracker {
+	t := &ExpiryTracker{fired: make(map[expiryKey]time.Duration)}
+	for _, m := range minutes {
+		if m > 0 {
+			t.thresholds = append(t.thresholds, time.Duration(m)*time.Minute)
+		}
+	}
+	sort.Slice(t.thresholds, func(i, j int) bool { return t.thresholds[i] > t.thresholds[j] })
+	return t
+}
+
+// Due reports whether the elevation id expiring at expiresAt has crossed a
+// threshold it has not been notified for yet, and returns the time left.
+// Thresholds already passed when an elevation is first seen are collapsed
+// into a single notification.
+func (t *ExpiryTracker) Due(id string, expiresAt, now time.Time) (time.Duration, bool) {
+	left := expiresAt.Sub(now)
+	if len(t.thresholds) == 0 || left <= 0 {
+		return left, false
+	}
+	key := expiryKey{id, expiresAt}
+	lowest, seen := t.fired[key]
+	due := false
+	for _, th := range t.thresholds {
+		if left > th {
+			break
+		}
+		if !seen || th < lowest {
+			lowest, seen, due = th, true, true
+		}
+	}
+	if due {
+		t.fired[key] = lowest
+	}
+	return left, due
+}
+
+// Prune forgets elevations that have already expired
+func (t *ExpiryTracker) Prune(now time.Time) {
+	for key := range t.fired {
+		if !key.expiresAt.After(now) {
+			delete(t.fired, key)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiryTrackerDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(20 * time.Minute)
+
+	tracker := NewExpiryTracker([]int{5, 15, 0})
+
+	steps := []struct {
+		name    string
+		at      time.Duration // Time since now
+		wantDue bool
+	}{
+		{"before first threshold", 0, false},
+		{"crosses 15 minutes", 6 * time.Minute, true},
+		{"still inside 15 minutes", 8 * time.Minute, false},
+		{"crosses 5 minutes", 16 * time.Minute, true},
+		{"inside 5 minutes again", 17 * time.Minute, false},
+		{"expired", 21 * time.Minute, false},
+	}
+	for _, step := range steps {
+		if _, due := tracker.Due("role-a", expires, now.Add(step.at)); due != step.wantDue {
+			t.Errorf("%s: Due() = %v, want %v", step.name, due, step.wantDue)
+		}
+	}
+}
+
+func TestExpiryTrackerCollapsesPassedThresholds(t *testing.T) {
+	now := time.Now()
+	tracker := NewExpiryTracker([]int{15, 5})
+
+	// First seen with 3 minutes left: one notification, not one per threshold
+	left, due := tracker.Due("role-a", now.Add(3*time.Minute), now)
+	if !due {
+		t.Fatal("Due() = false for an elevation inside every threshold")
+	}
+	if left != 3*time.Minute {
+		t.Errorf("Due() left = %v, want 3m", left)
+	}
+	if _, due := tracker.Due("role-a", now.Add(3*time.Minute), now.Add(time.Second)); due {
+		t.Error("Due() fired twice for the same elevation")
+	}
+}
+
+func TestExpiryTrackerExtensionResets(t *testing.T) {
+	now := time.Now()
+	tracker := NewExpiryTracker([]int{10})
+
+	if _, due := tracker.Due("role-a", now.Add(5*time.Minute), now); !due {
+		t.Fatal("Due() = false inside threshold")
+	}
+	// Extended: new expiry time, thresholds apply again once crossed
+	extended := now.Add(65 * time.Minute)
+	if _, due := tracker.Due("role-a", extended, now); due {
+		t.Error("Due() = true right after extension")
+	}
+	if _, due := tracker.Due("role-a", extended, now.Add(56*time.Minute)); !due {
+		t.Error("Due() = false after extended elevation crossed the threshold")
+	}
+	// Other elevations are tracked independently
+	if _, due := tracker.Due("role-b", now.Add(5*time.Minute), now); !due {
+		t.Error("Due() = false for a second elevation")
+	}
+}
+
+func TestExpiryTrackerPrune(t *testing.T) {
+	now := time.Now()
+	tracker := NewExpiryTracker([]int{10})
+	tracker.Due("role-a", now.Add(time.Minute), now)
+	tracker.Due("role-b", now.Add(5*time.Minute), now)
+
+	tracker.Prune(now.Add(2 * time.Minute))
+	if len(tracker.fired) != 1 {
+		t.Errorf("Prune() kept %d elevations, want 1", len(tracker.fired))
+	}
+}
+
+func TestExpiryTrackerNoThresholds(t *testing.T) {
+	now := time.Now()
+	tracker := NewExpiryTracker(nil)
+	if _, due := tracker.Due("role-a", now.Add(time.Minute), now); due {
+		t.Error("Due() = true without thresholds")
+	}
+}
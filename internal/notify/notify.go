@@ -0,0 +1,241 @@
+// Package notify delivers short alerts about elevations through pluggable sinks:
+// the terminal bell, OSC 9/777 desktop notification escapes, notify-send and a
+// user-supplied command hook.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seb07-cloud/pim-tui/internal/config"
+)
+
+// Kind identifies why a notification was raised
+type Kind string
+
+const (
+	KindExpiring Kind = "expiring" // An active elevation is about to expire
+	KindApproved Kind = "approved" // A request waiting for approval became active
+	KindFailed   Kind = "failed"   // An activation request failed
+)
+
+// Event is a single notification
+type Event struct {
+	Kind    Kind
+	Title   string
+	Message string
+}
+
+// Sink delivers events to one destination
+type Sink interface {
+	Notify(ctx context.Context, e Event) error
+}
+
+// Sink names accepted in the notifications config
+const (
+	SinkBell    = "bell"
+	SinkOSC9    = "osc9"
+	SinkOSC777  = "osc777"
+	SinkDesktop = "desktop"
+	SinkCommand = "command"
+)
+
+// commandTimeout bounds how long notify-send or a command hook may run
+const commandTimeout = 10 * time.Second
+
+// Notifier fans events out to every configured sink
+type Notifier struct {
+	sinks []Sink
+}
+
+// New builds a notifier from config, writing terminal sinks to w. It returns a
+// notifier without sinks when notifications are disabled, along with an error
+// listing any sink names it did not recognise.
+func New(cfg config.NotificationConfig, w io.Writer) (*Notifier, error) {
+	n := &Notifier{}
+	if !cfg.Enabled {
+		return n, nil
+	}
+	term := &terminal{w: w, tmux: os.Getenv("TMUX") != ""}
+	var errs []error
+	for _, name := range cfg.Sinks {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case SinkBell:
+			n.sinks = append(n.sinks, Bell{term})
+		case SinkOSC9:
+			n.sinks = append(n.sinks, OSC{term: term, Code: 9})
+		case SinkOSC777:
+			n.sinks = append(n.sinks, OSC{term: term, Code: 777})
+		case SinkDesktop:
+			n.sinks = append(n.sinks, Desktop{})
+		case SinkCommand:
+			if cfg.Command == "" {
+				errs = append(errs, errors.New("notification sink \"command\" needs notifications.command"))
+				continue
+			}
+			n.sinks = append(n.sinks, Command{Command: cfg.Command})
+		default:
+			errs = append(errs, fmt.Errorf("unknown notification sink %q", name))
+		}
+	}
+	return n, errors.Join(errs...)
+}
+
+// NewWithSinks returns a notifier delivering to the given sinks
+func NewWithSinks(sinks ...Sink) *Notifier {
+	return &Notifier{sinks: sinks}
+}
+
+// Enabled reports whether any sink is configured
+func (n *Notifier) Enabled() bool {
+	return n != nil && len(n.sinks) > 0
+}
+
+// Notify delivers e to every sink and returns the joined sink errors
+func (n *Notifier) Notify(ctx context.Context, e Event) error {
+	if !n.Enabled() {
+		return nil
+	}
+	var errs []error
+	for _, s := range n.sinks {
+		if err := s.Notify(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Output is the program's terminal, shared by the Bubble Tea renderer and the
+// terminal sinks. Writes are serialised so an escape sequence can never land in
+// the middle of a rendered frame.
+type Output struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewOutput wraps f, usually os.Stdout. Pass the result to tea.WithOutput and
+// to New so both write through it.
+func NewOutput(f *os.File) *Output {
+	return &Output{f: f}
+}
+
+func (o *Output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.f.Write(p)
+}
+
+// Read, Close and Fd let Bubble Tea detect that the output is a terminal
+func (o *Output) Read(p []byte) (int, error) { return o.f.Read(p) }
+func (o *Output) Close() error               { return o.f.Close() }
+func (o *Output) Fd() uintptr                { return o.f.Fd() }
+
+// terminal serialises escape sequence writes and wraps them for tmux passthrough
+type terminal struct {
+	mu   sync.Mutex
+	w    io.Writer
+	tmux bool
+}
+
+// write sends seq in a single write; OSC sequences are wrapped in a DCS
+// passthrough when running inside tmux so they reach the outer terminal
+func (t *terminal) write(seq string, osc bool) error {
+	if osc && t.tmux {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, err := io.WriteString(t.w, seq)
+	return err
+}
+
+// Bell rings the terminal bell; tmux and most terminals flag the window
+type Bell struct {
+	term *terminal
+}
+
+func (b Bell) Notify(ctx context.Context, e Event) error {
+	return b.term.write("\a", false)
+}
+
+// OSC emits an OSC 9 (iTerm2, Windows Terminal, WezTerm) or OSC 777 (rxvt,
+// foot, Ghostty, VTE) desktop notification escape sequence
+type OSC struct {
+	term *terminal
+	Code int
+}
+
+func (o OSC) Notify(ctx context.Context, e Event) error {
+	var seq string
+	if o.Code == 777 {
+		seq = fmt.Sprintf("\x1b]777;notify;%s;%s\a", sanitize(e.Title), sanitize(e.Message))
+	} else {
+		seq = fmt.Sprintf("\x1b]9;%s: %s\a", sanitize(e.Title), sanitize(e.Message))
+	}
+	return o.term.write(seq, true)
+}
+
+// sanitize strips control characters and the ";" separator from escape payloads
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return ' '
+		}
+		if r == ';' {
+			return ','
+		}
+		return r
+	}, s)
+}
+
+// Desktop sends a notification through notify-send, which talks to the
+// freedesktop notification service over D-Bus
+type Desktop struct{}
+
+func (Desktop) Notify(ctx context.Context, e Event) error {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+	args := []string{"--app-name=pim-tui"}
+	if e.Kind == KindExpiring || e.Kind == KindFailed {
+		args = append(args, "--urgency=critical")
+	}
+	args = append(args, e.Title, e.Message)
+	if out, err := exec.CommandContext(ctx, "notify-send", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("notify-send: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Command runs a shell command with the event in PIM_TUI_EVENT, PIM_TUI_TITLE
+// and PIM_TUI_MESSAGE
+type Command struct {
+	Command string
+}
+
+func (c Command) Notify(ctx context.Context, e Event) error {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", c.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", c.Command)
+	}
+	cmd.Env = append(os.Environ(),
+		"PIM_TUI_EVENT="+string(e.Kind),
+		"PIM_TUI_TITLE="+e.Title,
+		"PIM_TUI_MESSAGE="+e.Message,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notification command: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/seb07-cloud/pim-tui/internal/config"
+)
+
+type recordingSink struct {
+	events []Event
+	err    error
+}
+
+func (r *recordingSink) Notify(ctx context.Context, e Event) error {
+	r.events = append(r.events, e)
+	return r.err
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("TMUX", "")
+
+	tests := []struct {
+		name      string
+		cfg       config.NotificationConfig
+		wantSinks int
+		wantErr   bool
+	}{
+		{
+			name:      "defaults",
+			cfg:       config.DefaultNotifications(),
+			wantSinks: 1,
+		},
+		{
+			name:      "disabled",
+			cfg:       config.NotificationConfig{Enabled: false, Sinks: []string{"bell"}},
+			wantSinks: 0,
+		},
+		{
+			name:      "all sinks",
+			cfg:       config.NotificationConfig{Enabled: true, Sinks: []string{"bell", "OSC9", "osc777", "desktop", "command"}, Command: "true"},
+			wantSinks: 5,
+		},
+		{
+			name:      "unknown sink is skipped",
+			cfg:       config.NotificationConfig{Enabled: true, Sinks: []string{"bell", "pager"}},
+			wantSinks: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "command sink without command",
+			cfg:       config.NotificationConfig{Enabled: true, Sinks: []string{"command"}},
+			wantSinks: 0,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := New(tt.cfg, &bytes.Buffer{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(n.sinks) != tt.wantSinks {
+				t.Errorf("New() sinks = %d, want %d", len(n.sinks), tt.wantSinks)
+			}
+			if n.Enabled() != (tt.wantSinks > 0) {
+				t.Errorf("Enabled() = %v with %d sinks", n.Enabled(), tt.wantSinks)
+			}
+		})
+	}
+}
+
+func TestTerminalSinks(t *testing.T) {
+	e := Event{Kind: KindExpiring, Title: "PIM elevation expiring", Message: "Global Reader; expires in 5m"}
+
+	tests := []struct {
+		name string
+		sink string
+		tmux bool
+		want string
+	}{
+		{"bell", SinkBell, false, "\a"},
+		{"bell in tmux", SinkBell, true, "\a"},
+		{"osc 9", SinkOSC9, false, "\x1b]9;PIM elevation expiring: Global Reader, expires in 5m\a"},
+		{"osc 777", SinkOSC777, false, "\x1b]777;notify;PIM elevation expiring;Global Reader, expires in 5m\a"},
+		{"osc 9 in tmux", SinkOSC9, true, "\x1bPtmux;\x1b\x1b]9;PIM elevation expiring: Global Reader, expires in 5m\a\x1b\\"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.tmux {
+				t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+			} else {
+				t.Setenv("TMUX", "")
+			}
+			var buf bytes.Buffer
+			n, err := New(config.NotificationConfig{Enabled: true, Sinks: []string{tt.sink}}, &buf)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if err := n.Notify(context.Background(), e); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Notify() wrote %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	got := sanitize("a;b\x1b]c\n\x07d")
+	if got != "a,b ]c  d" {
+		t.Errorf("sanitize() = %q", got)
+	}
+}
+
+func TestNotifyJoinsSinkErrors(t *testing.T) {
+	ok := &recordingSink{}
+	failing := &recordingSink{err: errors.New("boom")}
+	n := NewWithSinks(failing, ok)
+
+	err := n.Notify(context.Background(), Event{Kind: KindFailed, Title: "t", Message: "m"})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Notify() error = %v, want boom", err)
+	}
+	// A failing sink doesn't stop the others
+	if len(ok.events) != 1 {
+		t.Errorf("second sink got %d events, want 1", len(ok.events))
+	}
+
+	var nilNotifier *Notifier
+	if err := nilNotifier.Notify(context.Background(), Event{}); err != nil {
+		t.Errorf("nil Notifier.Notify() error = %v", err)
+	}
+}
+
+func TestCommandSink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	out := filepath.Join(t.TempDir(), "event")
+	c := Command{Command: `printf '%s|%s|%s' "$PIM_TUI_EVENT" "$PIM_TUI_TITLE" "$PIM_TUI_MESSAGE" > "` + out + `"`}
+
+	e := Event{Kind: KindApproved, Title: "PIM request approved", Message: "Contributor now active"}
+	if err := c.Notify(context.Background(), e); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("command did not run: %v", err)
+	}
+	if want := "approved|PIM request approved|Contributor now active"; string(data) != want {
+		t.Errorf("command saw %q, want %q", data, want)
+	}
+
+	failing := Command{Command: "echo nope >&2; exit 3"}
+	if err := failing.Notify(context.Background(), e); err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("Notify() error = %v, want command output", err)
+	}
+}
+
+func TestOutput(t *testing.T) {
+	t.Setenv("TMUX", "")
+	f, err := os.Create(filepath.Join(t.TempDir(), "tty"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := NewOutput(f)
+	n, err := New(config.NotificationConfig{Enabled: true, Sinks: []string{SinkBell, SinkOSC9}}, out)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// Frames and notifications written concurrently must each arrive whole
+	frame := strings.Repeat("x", 4096)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			out.Write([]byte(frame))
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		n.Notify(context.Background(), Event{Kind: KindExpiring, Title: "t", Message: "m"})
+	}
+	<-done
+	f.Close()
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest := string(data)
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, frame):
+			rest = rest[len(frame):]
+		case strings.HasPrefix(rest, "\a"):
+			rest = rest[1:]
+		case strings.HasPrefix(rest, "\x1b]9;t: m\a"):
+			rest = rest[len("\x1b]9;t: m\a"):]
+		default:
+			t.Fatalf("interleaved write at %q", rest[:min(len(rest), 20)])
+		}
+	}
+	if out.Fd() != f.Fd() {
+		t.Error("Fd() should be the wrapped file's descriptor")
+	}
+}
@@ -0,0 +1,48 @@
+// Package pool runs a function over a slice with a bounded number of goroutines.
+package pool
+
+import (
+	"context"
+	"sync"
+)
+
+// Map calls fn for every item with at most limit calls in flight (at least one)
+// and returns the results in input order. progress, if set, is called with the
+// number of finished calls after each one; calls are serialised. Items are
+// started in order and none are started once ctx is cancelled: Map waits for
+// the running calls and returns how many items were started, leaving
+// results[started:] as zero values.
+func Map[T, R any](ctx context.Context, items []T, limit int, fn func(ctx context.Context, item T) R, progress func(done int)) (results []R, started int) {
+	if limit < 1 {
+		limit = 1
+	}
+	results = make([]R, len(items))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+
+	for started < len(items) && ctx.Err() == nil {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		i := started
+		started++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = fn(ctx, items[i])
+			if progress != nil {
+				mu.Lock()
+				done++
+				progress(done)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return results, started
+}
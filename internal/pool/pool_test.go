@@ -0,0 +1,64 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMap(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	var inFlight, peak int32
+	var updates []int
+
+	results, started := Map(context.Background(), items, 3, func(ctx context.Context, n int) int {
+		cur := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if cur <= p || atomic.CompareAndSwapInt32(&peak, p, cur) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return n * n
+	}, func(done int) { updates = append(updates, done) })
+
+	if started != len(items) {
+		t.Errorf("started = %d, want %d", started, len(items))
+	}
+	for i, n := range items {
+		if results[i] != n*n {
+			t.Errorf("results[%d] = %d, want %d", i, results[i], n*n)
+		}
+	}
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want <= 3", peak)
+	}
+	for i, d := range updates {
+		if d != i+1 {
+			t.Fatalf("progress = %v, want 1..%d in order", updates, len(items))
+		}
+	}
+	if len(updates) != len(items) {
+		t.Errorf("progress = %v, want one update per item", updates)
+	}
+}
+
+func TestMapCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	results, started := Map(ctx, []string{"a", "b", "c", "d"}, 1, func(ctx context.Context, s string) string {
+		if s == "b" {
+			cancel()
+		}
+		return s
+	}, nil)
+
+	if started != 2 {
+		t.Fatalf("started = %d, want 2 (nothing after the cancellation)", started)
+	}
+	if results[0] != "a" || results[1] != "b" || results[2] != "" || results[3] != "" {
+		t.Errorf("results = %q, want only the started items filled in", results)
+	}
+}
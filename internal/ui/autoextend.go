@@ -0,0 +1,406 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+	"github.com/seb07-cloud/pim-tui/internal/notify"
+)
+
+const (
+	// autoExtendMinActive is how long PIM requires an activation to be active
+	// before it can be deactivated (ActiveDurationTooShort)
+	autoExtendMinActive = 5 * time.Minute
+	// autoExtendRetry is the delay before retrying a failed automatic extension
+	autoExtendRetry = 2 * time.Minute
+	// autoExtendGrace tolerates refreshes that miss an elevation right after
+	// an automatic action before the rule is dropped
+	autoExtendGrace = 3 * time.Minute
+)
+
+// autoExtendRule keeps one active elevation alive until a wall-clock time
+type autoExtendRule struct {
+	name          string
+	until         time.Time
+	justification string
+	activeSince   time.Time // First seen active; gates re-activation
+	lastAction    time.Time // Last automatic request finished
+	retryAt       time.Time // No new request before this time
+	extendedFrom  time.Time // Expiry replaced by the last granted request; no new request while it still shows
+	inFlight      bool
+}
+
+type autoExtendDoneMsg struct {
+	key         string
+	item        interface{}
+	duration    time.Duration
+	result      azure.RequestResult
+	err         error
+	reactivated bool // Extension was refused and the item was deactivated and re-activated
+	stop        bool // Extension was refused and re-activation needs the user; the rule ends
+}
+
+// autoExtendStep is what a rule should do on this check
+type autoExtendStep int
+
+const (
+	autoExtendWait   autoExtendStep = iota // Nothing to do yet
+	autoExtendSubmit                       // Request more time now
+	autoExtendDone                         // Elevation already lasts until the target time
+)
+
+// planAutoExtend decides whether a rule should request more time for an elevation
+// expiring at expiresAt, and for how long. Each request covers the time left until
+// the rule's target, bounded by the policy maximum (0 if unknown) and the global cap.
+func planAutoExtend(rule autoExtendRule, expiresAt, now time.Time, lead, policyMax, maxCap time.Duration) (autoExtendStep, time.Duration) {
+	if rule.until.Sub(expiresAt) < time.Minute {
+		return autoExtendDone, 0
+	}
+	if rule.inFlight || now.Before(rule.retryAt) || expiresAt.Equal(rule.extendedFrom) || expiresAt.Sub(now) > lead {
+		return autoExtendWait, 0
+	}
+	duration := rule.until.Sub(now)
+	if policyMax > 0 && duration > policyMax {
+		duration = policyMax
+	}
+	if maxCap > 0 && duration > maxCap {
+		duration = maxCap
+	}
+	return autoExtendSubmit, duration.Truncate(time.Minute)
+}
+
+// parseAutoExtendUntil turns "HH:MM" into the next occurrence of that time
+func parseAutoExtendUntil(s string, now time.Time) (time.Time, error) {
+	t, err := time.ParseInLocation("15:04", strings.TrimSpace(s), now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("enter a time as HH:MM")
+	}
+	until := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+	if !until.After(now) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until, nil
+}
+
+// autoExtendLead is how long before expiry an extension is requested
+func (m Model) autoExtendLead() time.Duration {
+	return time.Duration(m.config.AutoExtendLeadMinutes) * time.Minute
+}
+
+// autoExtendCap is the longest time a rule may keep an elevation alive
+func (m Model) autoExtendCap() time.Duration {
+	return time.Duration(m.config.AutoExtendMaxHours) * time.Hour
+}
+
+// policyMaxDuration returns the longest activation the item's policy allows, or
+// the longest duration preset when the policy is unknown
+func (m Model) policyMaxDuration(item interface{}) time.Duration {
+	var longest time.Duration
+	switch v := item.(type) {
+	case azure.Role:
+		longest = v.MaxDuration
+	case azure.Group:
+		longest = v.MaxDuration
+	}
+	if longest > 0 {
+		return longest
+	}
+	for _, preset := range m.config.DurationPresets {
+		if d := time.Duration(preset) * time.Hour; d > longest {
+			longest = d
+		}
+	}
+	return longest
+}
+
+// autoExtendUntil returns the target time of the item's auto-extend rule, nil if none
+func (m Model) autoExtendUntil(item interface{}) *time.Time {
+	if rule, ok := m.autoExtendRules[itemKey(item)]; ok {
+		return &rule.until
+	}
+	return nil
+}
+
+// initiateAutoExtend opens the auto-extend dialog for the selected active elevations
+func (m *Model) initiateAutoExtend() (tea.Model, tea.Cmd) {
+	m.pendingAutoExtend = nil
+	for _, item := range m.renewalCandidates() {
+		if isTimeBoundActive(item) {
+			m.pendingAutoExtend = append(m.pendingAutoExtend, item)
+		}
+	}
+	if len(m.pendingAutoExtend) == 0 {
+		m.log(LogInfo, "Only active, time-bound elevations can be auto-extended")
+		return m, nil
+	}
+
+	m.autoExtendInput.SetValue("")
+	if until := m.autoExtendUntil(m.pendingAutoExtend[0]); until != nil {
+		m.autoExtendInput.SetValue(until.Format("15:04"))
+	}
+	m.autoExtendInput.Focus()
+	m.state = StateAutoExtend
+	return m, textinput.Blink
+}
+
+// isTimeBoundActive reports whether item is an active elevation with an end time
+func isTimeBoundActive(item interface{}) bool {
+	switch v := item.(type) {
+	case azure.Role:
+		return v.Status.IsActive() && !v.Standing && v.ExpiresAt != nil
+	case azure.Group:
+		return v.Status.IsActive() && !v.Standing && v.ExpiresAt != nil
+	case SubscriptionRoleActivation:
+		return v.Role.Status.IsActive() && !v.Role.Standing && v.Role.ExpiresAt != nil
+	}
+	return false
+}
+
+func (m Model) handleAutoExtendKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Input.Submit):
+		m.applyAutoExtend(time.Now())
+		return m, nil
+	case key.Matches(msg, m.keys.Input.Cancel):
+		m.state = StateNormal
+		m.pendingAutoExtend = nil
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.autoExtendInput, cmd = m.autoExtendInput.Update(msg)
+	return m, cmd
+}
+
+// applyAutoExtend sets or, for an empty time, clears the rules of the pending items
+func (m *Model) applyAutoExtend(now time.Time) {
+	value := strings.TrimSpace(m.autoExtendInput.Value())
+	if value == "" {
+		for _, item := range m.pendingAutoExtend {
+			if _, ok := m.autoExtendRules[itemKey(item)]; ok {
+				delete(m.autoExtendRules, itemKey(item))
+				m.log(LogInfo, "Auto-extend off for %s", pendingItemName(item))
+			}
+		}
+		m.state = StateNormal
+		m.pendingAutoExtend = nil
+		return
+	}
+
+	until, err := parseAutoExtendUntil(value, now)
+	if err != nil {
+		m.log(LogError, "%v", err)
+		return
+	}
+	if maxUntil := now.Add(m.autoExtendCap()); m.autoExtendCap() > 0 && until.After(maxUntil) {
+		until = maxUntil.Truncate(time.Minute)
+		m.log(LogInfo, "Auto-extend limited to %s by auto_extend_max_hours", until.Format("15:04"))
+	}
+
+	justification := fmt.Sprintf("Auto-extended by pim-tui until %s", until.Format("15:04"))
+	if prev := strings.TrimSpace(m.justificationInput.Value()); prev != "" {
+		justification = prev + " (" + justification + ")"
+	}
+	for _, item := range m.pendingAutoExtend {
+		m.autoExtendRules[itemKey(item)] = &autoExtendRule{
+			name:          pendingItemName(item),
+			until:         until,
+			justification: justification,
+			activeSince:   now,
+		}
+		m.log(LogInfo, "Auto-extend on for %s until %s", pendingItemName(item), until.Format("15:04"))
+	}
+	m.state = StateNormal
+	m.pendingAutoExtend = nil
+}
+
+// checkAutoExtend submits extensions for rules whose elevation is about to expire
+// and drops rules that are complete or whose elevation has ended
+func (m *Model) checkAutoExtend(now time.Time) tea.Cmd {
+	if len(m.autoExtendRules) == 0 || m.client == nil || now.Sub(m.lastAutoExtendCheck) < expiryCheckInterval {
+		return nil
+	}
+	m.lastAutoExtendCheck = now
+
+	seen := make(map[string]bool)
+	var cmds []tea.Cmd
+	for _, a := range m.activeElevations() {
+		key := itemKey(a.item)
+		rule, ok := m.autoExtendRules[key]
+		if !ok {
+			continue
+		}
+		seen[key] = true
+
+		step, duration := planAutoExtend(*rule, a.expiresAt, now, m.autoExtendLead(), m.policyMaxDuration(a.item), m.autoExtendCap())
+		switch step {
+		case autoExtendDone:
+			m.log(LogInfo, "Auto-extend done: %s stays active until %s", rule.name, a.expiresAt.Local().Format("15:04"))
+			delete(m.autoExtendRules, key)
+		case autoExtendSubmit:
+			reactivate := now.Sub(rule.activeSince) >= autoExtendMinActive
+			rule.inFlight = true
+			rule.extendedFrom = a.expiresAt
+			m.log(LogInfo, "Auto-extending %s by %s (target %s)", rule.name, formatDuration(duration), rule.until.Format("15:04"))
+			cmds = append(cmds, autoExtendCmd(m.client, key, a.item, rule.justification, duration, reactivate))
+		}
+	}
+
+	for key, rule := range m.autoExtendRules {
+		if seen[key] || rule.inFlight || now.Sub(rule.lastAction) < autoExtendGrace {
+			continue
+		}
+		m.log(LogInfo, "Auto-extend stopped: %s is no longer active", rule.name)
+		delete(m.autoExtendRules, key)
+	}
+	return tea.Batch(cmds...)
+}
+
+// autoExtendCmd extends an active elevation. When PIM refuses the extension and
+// reactivate is set, the elevation is deactivated and activated again instead,
+// but only if the policy lets the activation through without approval or a new
+// sign-in. Otherwise the elevation is left alone and the rule stops.
+func autoExtendCmd(client *azure.Client, key string, item interface{}, justification string, duration time.Duration, reactivate bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		var result azure.RequestResult
+		var err error
+		switch v := item.(type) {
+		case azure.Role:
+			result, err = client.ExtendRole(ctx, v.RoleDefinitionID, justification, duration)
+		case azure.Group:
+			result, err = client.ExtendGroup(ctx, v.ID, v.RoleDefinitionID, justification, duration)
+		case SubscriptionRoleActivation:
+			result, err = client.ExtendAzureRole(ctx, v.Role.Scope, v.Role.RoleDefinitionID, v.Role.RoleEligibilityID, justification, duration)
+		default:
+			err = fmt.Errorf("unsupported item type %T", item)
+		}
+		if err == nil || !reactivate {
+			return autoExtendDoneMsg{key: key, item: item, duration: duration, result: result, err: err}
+		}
+
+		extendErr := err
+		if req, err := activationRequirements(ctx, client, item); err != nil || !req.Unattended() {
+			reason := "it needs " + req.String()
+			if err != nil {
+				reason = "its policy could not be read: " + bulkErrorReason(err)
+			}
+			err = fmt.Errorf("extension refused (%s), not re-activating because %s", bulkErrorReason(extendErr), reason)
+			return autoExtendDoneMsg{key: key, item: item, duration: duration, err: err, stop: true}
+		}
+
+		switch v := item.(type) {
+		case azure.Role:
+			if _, err = client.DeactivateRole(ctx, v.RoleDefinitionID, v.DirectoryScopeID); err == nil {
+				result, err = client.ActivateRole(ctx, v.RoleDefinitionID, v.DirectoryScopeID, justification, duration)
+			}
+		case azure.Group:
+			if _, err = client.DeactivateGroup(ctx, v.ID, v.RoleDefinitionID); err == nil {
+				result, err = client.ActivateGroup(ctx, v.ID, v.RoleDefinitionID, justification, duration)
+			}
+		case SubscriptionRoleActivation:
+			// Azure RBAC activations are requested in whole hours
+			duration = max(duration.Truncate(time.Hour), time.Hour)
+			if _, err = client.DeactivateAzureRole(ctx, v.Role.Scope, v.Role.RoleDefinitionID); err == nil {
+				result, err = client.ActivateAzureRole(ctx, v.Role.Scope, v.Role.RoleDefinitionID, v.Role.RoleEligibilityID, justification, duration)
+			}
+		}
+		if err != nil {
+			err = fmt.Errorf("extension refused (%s), re-activation failed: %w", bulkErrorReason(extendErr), err)
+		}
+		return autoExtendDoneMsg{key: key, item: item, duration: duration, result: result, err: err, reactivated: true}
+	}
+}
+
+// activationRequirements looks up what re-activating item would ask for
+func activationRequirements(ctx context.Context, client *azure.Client, item interface{}) (azure.ActivationRequirements, error) {
+	switch v := item.(type) {
+	case azure.Role:
+		return client.GetRoleActivationRequirements(ctx, v.RoleDefinitionID, v.DirectoryScopeID)
+	case azure.Group:
+		return client.GetGroupActivationRequirements(ctx, v.ID, v.RoleDefinitionID)
+	case SubscriptionRoleActivation:
+		return client.GetAzureRoleActivationRequirements(ctx, v.Role.Scope, v.Role.RoleDefinitionID)
+	}
+	return azure.ActivationRequirements{}, fmt.Errorf("unsupported item type %T", item)
+}
+
+// finishAutoExtend logs and records the outcome of an automatic extension
+func (m *Model) finishAutoExtend(msg autoExtendDoneMsg) tea.Cmd {
+	now := time.Now()
+	name := pendingItemName(msg.item)
+	justification := ""
+	if rule, ok := m.autoExtendRules[msg.key]; ok {
+		justification = rule.justification
+		rule.inFlight = false
+		rule.lastAction = now
+		if msg.err != nil {
+			rule.retryAt = now.Add(autoExtendRetry)
+			rule.extendedFrom = time.Time{}
+		}
+		if msg.reactivated && msg.err == nil {
+			rule.activeSince = now
+		}
+		if msg.stop {
+			delete(m.autoExtendRules, msg.key)
+		}
+	}
+
+	action := "Extended"
+	if msg.reactivated {
+		action = "Re-activated"
+	}
+	var notifyFailed tea.Cmd
+	if msg.err != nil {
+		title := "PIM auto-extend failed"
+		if msg.stop {
+			title = "PIM auto-extend stopped"
+			m.log(LogError, "Auto-extend stopped for %s: %v", name, msg.err)
+		} else {
+			m.log(LogError, "Auto-extend failed for %s: %s", name, bulkErrorReason(msg.err))
+			m.log(LogDebug, "Auto-extend error for %s: %v", name, msg.err)
+		}
+		if m.config.Notifications.OnFailure {
+			notifyFailed = notifyCmd(m.notifier, notify.Event{
+				Kind:    notify.KindFailed,
+				Title:   title,
+				Message: fmt.Sprintf("%s: %s", name, bulkErrorReason(msg.err)),
+			})
+		}
+	} else {
+		m.log(LogInfo, "Auto-extend: %s %s for %s", action, name, formatDuration(msg.duration))
+	}
+
+	itemType, scope := pendingItemDetails(msg.item)
+	entry := ActivationHistoryEntry{
+		Time:          now,
+		Kind:          HistoryExtend,
+		Type:          itemType,
+		Name:          name,
+		Scope:         scope,
+		Duration:      msg.duration,
+		Justification: justification,
+		Success:       msg.err == nil,
+		RequestID:     msg.result.RequestID,
+		Status:        msg.result.Status,
+		ExpiresAt:     msg.result.ExpiresAt,
+	}
+	if msg.err != nil {
+		entry.Error = msg.err.Error()
+	}
+	m.appendHistory(entry)
+
+	cmds := []tea.Cmd{appendHistoryCmd(m.historyStore, []ActivationHistoryEntry{entry}), notifyFailed}
+	if msg.err == nil && m.client != nil {
+		cmds = append(cmds, m.refreshCmd(), delayedRefreshCmd(5*time.Second))
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+	"github.com/seb07-cloud/pim-tui/internal/pool"
+)
+
+// bulkResult is the outcome of activating or deactivating one pending item
+type bulkResult struct {
+	item   interface{}
+	result azure.RequestResult
+	err    error
+}
+
+// runBulk applies fn to every item with at most limit calls in flight and returns
+// the outcomes in input order. Items not started before ctx is cancelled fail with
+// the context error.
+func runBulk(ctx context.Context, items []interface{}, limit int, fn func(context.Context, interface{}) (azure.RequestResult, error)) []bulkResult {
+	results, started := pool.Map(ctx, items, limit, func(ctx context.Context, item interface{}) bulkResult {
+		r := bulkResult{item: item}
+		r.result, r.err = fn(ctx, item)
+		return r
+	}, nil)
+	for i := started; i < len(items); i++ {
+		results[i] = bulkResult{item: items[i], err: ctx.Err()}
+	}
+	return results
+}
+
+// splitResults separates succeeded and failed items
+func splitResults(results []bulkResult) (succeeded, failed []interface{}) {
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, r.item)
+		} else {
+			succeeded = append(succeeded, r.item)
+		}
+	}
+	return succeeded, failed
+}
+
+// withoutCancelled drops the results of items whose request was cancelled
+func withoutCancelled(results []bulkResult) []bulkResult {
+	var kept []bulkResult
+	for _, r := range results {
+		if !errors.Is(r.err, context.Canceled) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// pendingItemName returns a display name for a pending activation or deactivation
+func pendingItemName(item interface{}) string {
+	switch v := item.(type) {
+	case azure.Role:
+		return v.DisplayName
+	case azure.Group:
+		return v.DisplayName
+	case SubscriptionRoleActivation:
+		return fmt.Sprintf("%s on %s", v.Role.RoleDefinitionName, v.SubscriptionName)
+	}
+	return "unknown item"
+}
+
+// pendingItemDetails returns the history type and scope of a pending item
+func pendingItemDetails(item interface{}) (itemType, scope string) {
+	switch v := item.(type) {
+	case azure.Role:
+		return "role", v.DirectoryScopeID
+	case azure.Group:
+		return "group", v.ID
+	case SubscriptionRoleActivation:
+		return "azure-role", v.Role.Scope
+	}
+	return "", ""
+}
+
+// bulkErrorReason turns known PIM error codes into a short human-readable reason
+func bulkErrorReason(err error) string {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "ActiveDurationTooShort"):
+		return "must be active for at least 5 minutes before deactivation"
+	case strings.Contains(msg, "RoleAssignmentExists"):
+		return "already active"
+	case strings.Contains(msg, "RoleAssignmentRequestPolicyValidationFailed"):
+		return "rejected by role policy (check duration, justification or ticket requirements)"
+	case strings.Contains(msg, "JustificationRule"):
+		return "justification does not meet policy requirements"
+	case strings.Contains(msg, "ExpirationRule"):
+		return "duration exceeds the maximum allowed by policy"
+	}
+	return msg
+}
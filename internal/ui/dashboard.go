@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"sort"
+	"time"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+)
+
+// dashboardEntry is one active or pending elevation on the Active now tab
+type dashboardEntry struct {
+	item      interface{} // azure.Role, azure.Group or SubscriptionRoleActivation
+	kind      string      // "role", "group" or "azure role"
+	status    azure.ActivationStatus
+	expiresAt *time.Time
+	standing  bool
+	doc       searchDoc
+}
+
+// key identifies the entry across refreshes, see itemKey
+func (e dashboardEntry) key() string {
+	return itemKey(e.item)
+}
+
+// dashboardEntries returns the Active now entries matching the search and
+// status filter, in the tab's sort order
+func (m Model) dashboardEntries() []dashboardEntry {
+	entries := m.collectDashboard(m.search())
+	lastUsed := m.lastUsedLookup(TabActive)
+	items := make([]listItem, len(entries))
+	for i, e := range entries {
+		itemType, _ := pendingItemDetails(e.item)
+		items[i] = listItem{index: i, doc: e.doc, status: e.status, expiresAt: e.expiresAt, lastUsed: lastUsed(itemType, pendingItemName(e.item)), favorite: m.favorites[e.key()]}
+	}
+	indices := m.listViews[TabActive].arrange(items)
+	arranged := make([]dashboardEntry, len(indices))
+	for i, idx := range indices {
+		arranged[i] = entries[idx]
+	}
+	return arranged
+}
+
+// inventory returns every loaded role, group and subscription role, whatever its status
+func (m Model) inventory() []dashboardEntry {
+	var entries []dashboardEntry
+	for _, r := range m.roles {
+		entries = append(entries, dashboardEntry{item: r, kind: "role", status: r.Status, expiresAt: r.ExpiresAt, standing: r.Standing, doc: roleSearchDoc(r)})
+	}
+	for _, g := range m.groups {
+		entries = append(entries, dashboardEntry{item: g, kind: "group", status: g.Status, expiresAt: g.ExpiresAt, standing: g.Standing, doc: groupSearchDoc(g)})
+	}
+	for _, sub := range m.lighthouse {
+		for _, role := range sub.EligibleRoles {
+			item := SubscriptionRoleActivation{SubscriptionID: sub.ID, SubscriptionName: sub.DisplayName, Role: role}
+			entries = append(entries, dashboardEntry{item: item, kind: "azure role", status: role.Status, expiresAt: role.ExpiresAt, standing: role.Standing, doc: subRoleSearchDoc(sub, role)})
+		}
+	}
+	return entries
+}
+
+// collectDashboard returns every active or pending role, group and subscription
+// role matching q, soonest expiry first. Pending requests follow the time-bound
+// elevations and standing assignments come last.
+func (m Model) collectDashboard(q searchQuery) []dashboardEntry {
+	var entries []dashboardEntry
+	for _, e := range m.inventory() {
+		if (e.status.IsActive() || e.status == azure.StatusPending) && q.matches(e.doc) {
+			entries = append(entries, e)
+		}
+	}
+
+	group := func(e dashboardEntry) int {
+		switch {
+		case e.standing:
+			return 2
+		case e.expiresAt == nil:
+			return 1
+		}
+		return 0
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if group(a) != group(b) {
+			return group(a) < group(b)
+		}
+		if a.expiresAt != nil && b.expiresAt != nil && !a.expiresAt.Equal(*b.expiresAt) {
+			return a.expiresAt.Before(*b.expiresAt)
+		}
+		return pendingItemName(a.item) < pendingItemName(b.item)
+	})
+	return entries
+}
+
+// dashboardTargets returns the selected dashboard entries, or every entry when
+// nothing is selected, so bulk actions cover everything by default
+func (m Model) dashboardTargets() []dashboardEntry {
+	entries := m.dashboardEntries()
+	var selected []dashboardEntry
+	for _, e := range entries {
+		if m.selectedActive[e.key()] {
+			selected = append(selected, e)
+		}
+	}
+	if len(selected) > 0 {
+		return selected
+	}
+	return entries
+}
+
+// selectedActiveCount counts selected dashboard items that are still active or pending
+func (m Model) selectedActiveCount() int {
+	count := 0
+	for _, e := range m.collectDashboard(nil) {
+		if m.selectedActive[e.key()] {
+			count++
+		}
+	}
+	return count
+}
+
+// dashboardLoadState combines the load state of the tabs the dashboard is built from
+func (m Model) dashboardLoadState() (loading bool, err error) {
+	for _, tab := range []Tab{TabRoles, TabGroups, TabSubscriptions} {
+		l, e := m.tabLoadState(tab)
+		loading = loading || l
+		if err == nil {
+			err = e
+		}
+	}
+	return loading, err
+}
+
+func (m *Model) toggleDashboardSelection() {
+	entries := m.dashboardEntries()
+	if m.activeCursor >= len(entries) {
+		return
+	}
+	e := entries[m.activeCursor]
+	if e.standing {
+		m.log(LogInfo, "Standing assignments are not managed through PIM")
+		return
+	}
+	if m.selectedActive[e.key()] {
+		delete(m.selectedActive, e.key())
+	} else {
+		m.selectedActive[e.key()] = true
+	}
+}
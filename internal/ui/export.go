@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"io"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/seb07-cloud/pim-tui/internal/export"
+	"github.com/seb07-cloud/pim-tui/internal/history"
+)
+
+// exportTargets are the data sets offered by the export dialog
+var exportTargets = []string{"history", "inventory"}
+
+// Export dialog fields, in focus order
+const (
+	exportFieldWhat = iota
+	exportFieldFormat
+	exportFieldRange
+	exportFieldPath
+	exportFieldCount
+)
+
+type exportDoneMsg struct {
+	what  string
+	path  string
+	count int
+	err   error
+}
+
+func newExportPathInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "File path..."
+	ti.CharLimit = 500
+	return ti
+}
+
+// openExportDialog shows the export dialog, returning to the current state when closed
+func (m *Model) openExportDialog(what, dateRange int) {
+	m.exportReturnState = m.state
+	m.exportWhat = what
+	m.exportFormat = 0
+	m.exportRange = dateRange
+	m.exportField = exportFieldWhat
+	m.exportPathEdited = false
+	m.exportPathInput.Blur()
+	m.updateExportPath()
+	m.state = StateExport
+}
+
+// updateExportPath suggests a dated file name until the user edits the path
+func (m *Model) updateExportPath() {
+	if m.exportPathEdited {
+		return
+	}
+	m.exportPathInput.SetValue(export.DefaultFileName(exportTargets[m.exportWhat], export.Formats[m.exportFormat], time.Now()))
+	m.exportPathInput.CursorEnd()
+}
+
+func (m Model) handleExportKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := m.keys.Export
+	switch {
+	case key.Matches(msg, keys.Cancel):
+		m.exportPathInput.Blur()
+		m.state = m.exportReturnState
+		return m, nil
+	case key.Matches(msg, keys.Submit):
+		m.exportPathInput.Blur()
+		m.state = m.exportReturnState
+		return m, m.exportCmd()
+	case key.Matches(msg, keys.PrevField):
+		m.setExportField((m.exportField + exportFieldCount - 1) % exportFieldCount)
+		return m, nil
+	case key.Matches(msg, keys.NextField):
+		m.setExportField((m.exportField + 1) % exportFieldCount)
+		return m, nil
+	}
+
+	if m.exportField == exportFieldPath {
+		var cmd tea.Cmd
+		m.exportPathInput, cmd = m.exportPathInput.Update(msg)
+		m.exportPathEdited = true
+		return m, cmd
+	}
+
+	delta := 0
+	switch {
+	case key.Matches(msg, keys.Prev):
+		delta = -1
+	case key.Matches(msg, keys.Next):
+		delta = 1
+	}
+	if delta == 0 {
+		return m, nil
+	}
+	switch m.exportField {
+	case exportFieldWhat:
+		m.exportWhat = (m.exportWhat + len(exportTargets) + delta) % len(exportTargets)
+	case exportFieldFormat:
+		m.exportFormat = (m.exportFormat + len(export.Formats) + delta) % len(export.Formats)
+	case exportFieldRange:
+		m.exportRange = (m.exportRange + len(historyRanges) + delta) % len(historyRanges)
+	}
+	m.updateExportPath()
+	return m, nil
+}
+
+func (m *Model) setExportField(field int) {
+	m.exportField = field
+	if field == exportFieldPath {
+		m.exportPathInput.Focus()
+	} else {
+		m.exportPathInput.Blur()
+	}
+}
+
+// exportCmd writes the selected data set to the chosen path in the background
+func (m Model) exportCmd() tea.Cmd {
+	what := exportTargets[m.exportWhat]
+	format := export.Formats[m.exportFormat]
+	path := m.exportPathInput.Value()
+
+	var count int
+	var write func(io.Writer) error
+	if what == "history" {
+		filter := history.Filter{Since: historyRanges[m.exportRange].since(time.Now())}
+		entries := filter.Apply(m.mergedHistory())
+		count = len(entries)
+		write = func(w io.Writer) error { return export.WriteHistory(w, format, entries) }
+	} else {
+		items := export.Inventory(m.roles, m.groups, m.lighthouse)
+		count = len(items)
+		write = func(w io.Writer) error { return export.WriteInventory(w, format, items) }
+	}
+
+	return func() tea.Msg {
+		written, err := export.WriteFile(path, write)
+		return exportDoneMsg{what: what, path: written, count: count, err: err}
+	}
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+)
+
+type extensionDoneMsg struct {
+	results []bulkResult
+}
+
+// initiateExtension opens the extension dialog for the selected active elevations
+func (m *Model) initiateExtension() (tea.Model, tea.Cmd) {
+	m.pendingExtensions = nil
+	for _, item := range m.renewalCandidates() {
+		if isTimeBoundActive(item) {
+			m.pendingExtensions = append(m.pendingExtensions, item)
+		}
+	}
+	if len(m.pendingExtensions) == 0 {
+		m.log(LogInfo, "Only active, time-bound elevations can be extended")
+		return m, nil
+	}
+
+	m.justificationInput.SetValue("")
+	m.justificationInput.Focus()
+	m.state = StateExtend
+	return m, textinput.Blink
+}
+
+func (m Model) handleExtendKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := m.keys.Justification
+	switch {
+	case key.Matches(msg, keys.Submit):
+		if _, err := validateJustification(m.justificationInput.Value()); err != nil {
+			m.log(LogError, "%v", err)
+			return m, nil
+		}
+		return m.startExtension()
+	case key.Matches(msg, keys.Cancel):
+		m.state = StateNormal
+		m.pendingExtensions = nil
+		return m, nil
+	case key.Matches(msg, keys.CycleDuration):
+		m.cycleDuration()
+		return m, nil
+	}
+	if idx := durationIndex(msg, keys.Durations); idx >= 0 {
+		m.setDurationByIndex(idx)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.justificationInput, cmd = m.justificationInput.Update(msg)
+	return m, cmd
+}
+
+// startExtension asks PIM to keep every pending item active for the selected
+// duration from now
+func (m *Model) startExtension() (tea.Model, tea.Cmd) {
+	m.state = StateExtending
+	client := m.client
+	pending := m.pendingExtensions
+	justification := m.justificationInput.Value()
+	duration := m.duration
+	limit := m.config.ActivationConcurrency
+
+	return m, func() tea.Msg {
+		results := runBulk(context.Background(), pending, limit, func(ctx context.Context, item interface{}) (azure.RequestResult, error) {
+			switch v := item.(type) {
+			case azure.Role:
+				return client.ExtendRole(ctx, v.RoleDefinitionID, justification, duration)
+			case azure.Group:
+				return client.ExtendGroup(ctx, v.ID, v.RoleDefinitionID, justification, duration)
+			case SubscriptionRoleActivation:
+				return client.ExtendAzureRole(ctx, v.Role.Scope, v.Role.RoleDefinitionID, v.Role.RoleEligibilityID, justification, duration)
+			}
+			return azure.RequestResult{}, fmt.Errorf("unsupported item type %T", item)
+		})
+		return extensionDoneMsg{results: results}
+	}
+}
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+	"github.com/seb07-cloud/pim-tui/internal/fsutil"
+)
+
+// favoritesFile is what is kept on disk: the itemKey of every favorite role,
+// group and Azure role, plus subscriptionKey for whole subscriptions
+type favoritesFile struct {
+	Favorites []string `json:"favorites"`
+}
+
+type favoritesLoadedMsg struct {
+	keys []string
+	err  error
+}
+
+type favoritesSavedMsg struct{ err error }
+
+func favoritesPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config dir: %w", err)
+	}
+	return filepath.Join(configDir, "pim-tui", "favorites.json"), nil
+}
+
+// subscriptionKey identifies a subscription among the favorites
+func subscriptionKey(sub azure.LighthouseSubscription) string {
+	return "subscription|" + sub.ID
+}
+
+// loadFavorites reads the favorites file; a missing file has no favorites
+func loadFavorites(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var f favoritesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	return f.Favorites, nil
+}
+
+func saveFavorites(path string, keys []string) error {
+	data, err := json.MarshalIndent(favoritesFile{Favorites: keys}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, data)
+}
+
+func loadFavoritesCmd(path string) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		keys, err := loadFavorites(path)
+		return favoritesLoadedMsg{keys: keys, err: err}
+	}
+}
+
+// saveFavoritesCmd writes the current favorites, sorted so the file diffs
+// cleanly. Nothing is written until the file has been read, so a favorite
+// toggled early or after a failed load never overwrites the saved ones.
+func (m Model) saveFavoritesCmd() tea.Cmd {
+	if m.favoritesPath == "" || !m.favoritesLoaded {
+		return nil
+	}
+	keys := make([]string, 0, len(m.favorites))
+	for k := range m.favorites {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	path := m.favoritesPath
+	return func() tea.Msg {
+		return favoritesSavedMsg{saveFavorites(path, keys)}
+	}
+}
+
+// subscriptionFavorite reports whether the subscription or one of its roles is a favorite
+func (m Model) subscriptionFavorite(sub azure.LighthouseSubscription) bool {
+	if m.favorites[subscriptionKey(sub)] {
+		return true
+	}
+	for _, role := range sub.EligibleRoles {
+		if m.favorites[itemKey(SubscriptionRoleActivation{SubscriptionID: sub.ID, Role: role})] {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleFavorite adds or removes the item under the cursor from the favorites.
+// On the subscriptions tab that is the focused role, or else the subscription.
+func (m *Model) toggleFavorite() tea.Cmd {
+	var key, name string
+	switch m.activeTab {
+	case TabRoles:
+		if m.rolesCursor < len(m.roles) {
+			key, name = itemKey(m.roles[m.rolesCursor]), m.roles[m.rolesCursor].DisplayName
+		}
+	case TabGroups:
+		if m.groupsCursor < len(m.groups) {
+			key, name = itemKey(m.groups[m.groupsCursor]), m.groups[m.groupsCursor].DisplayName
+		}
+	case TabSubscriptions:
+		sub := m.getCurrentSubscription()
+		switch {
+		case sub == nil:
+		case m.subRoleFocus && m.subRoleCursor < len(sub.EligibleRoles):
+			item := SubscriptionRoleActivation{SubscriptionID: sub.ID, SubscriptionName: sub.DisplayName, Role: sub.EligibleRoles[m.subRoleCursor]}
+			key, name = itemKey(item), pendingItemName(item)
+		default:
+			key, name = subscriptionKey(*sub), sub.DisplayName
+		}
+	case TabActive:
+		if entries := m.dashboardEntries(); m.activeCursor < len(entries) {
+			key, name = entries[m.activeCursor].key(), pendingItemName(entries[m.activeCursor].item)
+		}
+	}
+	if key == "" {
+		return nil
+	}
+
+	if m.favorites[key] {
+		delete(m.favorites, key)
+		m.log(LogInfo, "Removed %s from favorites", name)
+	} else {
+		m.favorites[key] = true
+		m.log(LogInfo, "Added %s to favorites", name)
+	}
+	// The list reorders around the pinned favorites; keep the cursor on the item
+	if m.activeTab == TabActive {
+		for i, e := range m.dashboardEntries() {
+			if e.key() == key {
+				m.activeCursor = i
+			}
+		}
+	}
+	m.moveCursor(0)
+	return m.saveFavoritesCmd()
+}
@@ -0,0 +1,252 @@
+package ui
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+	"github.com/seb07-cloud/pim-tui/internal/history"
+)
+
+// remoteHistoryWindow is how far back the Azure request history is fetched
+const remoteHistoryWindow = 30 * 24 * time.Hour
+
+// remoteMatchWindow is how close a local entry and an Azure record without a
+// shared request ID must be to count as the same request
+const remoteMatchWindow = 2 * time.Minute
+
+// historyRanges are the date filters cycled with "d" in the history view
+var historyRanges = []struct {
+	label string
+	since func(now time.Time) time.Time
+}{
+	{"all time", func(time.Time) time.Time { return time.Time{} }},
+	{"today", func(now time.Time) time.Time {
+		y, mo, d := now.Date()
+		return time.Date(y, mo, d, 0, 0, 0, 0, now.Location())
+	}},
+	{"last 7 days", func(now time.Time) time.Time { return now.AddDate(0, 0, -7) }},
+	{"last 30 days", func(now time.Time) time.Time { return now.AddDate(0, 0, -30) }},
+}
+
+var historyKinds = []string{"", HistoryActivate, HistoryDeactivate, HistoryExtend, HistoryRenew}
+var historyOutcomes = []string{history.OutcomeAny, history.OutcomeSuccess, history.OutcomeFailure}
+
+func loadHistoryCmd(store *history.Store, retention history.Retention) tea.Cmd {
+	return func() tea.Msg {
+		if store == nil {
+			return historyLoadedMsg{}
+		}
+		entries, err := store.Load(retention)
+		return historyLoadedMsg{entries: entries, err: err}
+	}
+}
+
+func appendHistoryCmd(store *history.Store, entries []ActivationHistoryEntry) tea.Cmd {
+	if store == nil || len(entries) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return historySavedMsg{store.Append(entries...)}
+	}
+}
+
+func loadRemoteHistoryCmd(client *azure.Client) tea.Cmd {
+	return func() tea.Msg {
+		records, err := client.GetRequestHistory(context.Background(), time.Now().Add(-remoteHistoryWindow))
+		return remoteHistoryLoadedMsg{entries: remoteHistoryEntries(records), err: err}
+	}
+}
+
+// fetchRemoteHistory starts loading the Azure request history unless it is already in flight
+func (m *Model) fetchRemoteHistory() tea.Cmd {
+	if m.client == nil || m.remoteHistoryLoading {
+		return nil
+	}
+	m.remoteHistoryLoading = true
+	return loadRemoteHistoryCmd(m.client)
+}
+
+// remoteHistoryEntries converts Azure audit records to history entries.
+// Requests other than self activation, deactivation and extension are skipped.
+func remoteHistoryEntries(records []azure.AuditRecord) []ActivationHistoryEntry {
+	entries := make([]ActivationHistoryEntry, 0, len(records))
+	for _, r := range records {
+		var kind string
+		switch strings.ToLower(r.Action) {
+		case "selfactivate":
+			kind = HistoryActivate
+		case "selfdeactivate":
+			kind = HistoryDeactivate
+		case "selfextend":
+			kind = HistoryExtend
+		case "selfrenew":
+			kind = HistoryRenew
+		default:
+			continue
+		}
+		entry := ActivationHistoryEntry{
+			Time:          r.Time,
+			Kind:          kind,
+			Type:          r.Source,
+			Name:          r.Name,
+			Scope:         r.Scope,
+			Justification: r.Justification,
+			Success:       !r.Failed(),
+			RequestID:     r.RequestID,
+			Status:        r.Status,
+			ExpiresAt:     r.ExpiresAt,
+			Remote:        true,
+		}
+		if r.StartsAt != nil && r.ExpiresAt != nil {
+			entry.Duration = r.ExpiresAt.Sub(*r.StartsAt)
+		}
+		if !entry.Success {
+			entry.Error = r.Status
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// mergedHistory returns local entries plus Azure records that were not made
+// from this machine, oldest first. It is computed by mergeHistory.
+func (m Model) mergedHistory() []ActivationHistoryEntry {
+	return m.mergedEntries
+}
+
+// appendHistory adds entries to the local history
+func (m *Model) appendHistory(entries ...ActivationHistoryEntry) {
+	m.activationHistory = append(m.activationHistory, entries...)
+	m.mergeHistory()
+}
+
+// mergeHistory recomputes mergedHistory after the local or remote history changed.
+// A remote record is left out when a local entry has its request ID, or, since
+// Entra role and group requests go through a different API locally and their
+// IDs never match the Graph IDs, when a local entry for the same item and
+// action lies within remoteMatchWindow of it.
+func (m *Model) mergeHistory() {
+	if len(m.remoteHistory) == 0 {
+		m.mergedEntries = m.activationHistory
+		return
+	}
+	requestIDs := make(map[string]bool, len(m.activationHistory))
+	localTimes := make(map[string][]time.Time)
+	for _, e := range m.activationHistory {
+		if e.RequestID != "" {
+			requestIDs[strings.ToLower(e.RequestID)] = true
+		}
+		k := historyMatchKey(e)
+		localTimes[k] = append(localTimes[k], e.Time)
+	}
+	hasLocalMatch := func(r ActivationHistoryEntry) bool {
+		for _, t := range localTimes[historyMatchKey(r)] {
+			if d := t.Sub(r.Time); d < remoteMatchWindow && d > -remoteMatchWindow {
+				return true
+			}
+		}
+		return false
+	}
+
+	merged := append([]ActivationHistoryEntry(nil), m.activationHistory...)
+	for _, r := range m.remoteHistory {
+		if requestIDs[strings.ToLower(r.RequestID)] || hasLocalMatch(r) {
+			continue
+		}
+		merged = append(merged, r)
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Time.Before(merged[j].Time) })
+	m.mergedEntries = merged
+}
+
+// historyMatchKey groups entries that may describe the same request
+func historyMatchKey(e ActivationHistoryEntry) string {
+	return e.Kind + "|" + e.Type + "|" + strings.ToLower(e.Name)
+}
+
+func (m Model) historyRetention() history.Retention {
+	return history.Retention{
+		MaxAge:     time.Duration(m.config.HistoryRetentionDays) * 24 * time.Hour,
+		MaxEntries: m.config.HistoryMaxEntries,
+	}
+}
+
+// currentHistoryFilter combines the kind/name/outcome filter with the date range
+func (m Model) currentHistoryFilter() history.Filter {
+	f := m.historyFilter
+	f.Since = historyRanges[m.historyRange].since(time.Now())
+	return f
+}
+
+// filteredHistory returns matching entries newest first
+func (m Model) filteredHistory() []ActivationHistoryEntry {
+	matched := m.currentHistoryFilter().Apply(m.mergedHistory())
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+	return matched
+}
+
+func (m Model) handleHistoryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.historyFilterEditing {
+		if key.Matches(msg, m.keys.Input.Submit, m.keys.Input.Cancel) {
+			m.historyFilterEditing = false
+			m.historyFilterInput.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.historyFilterInput, cmd = m.historyFilterInput.Update(msg)
+		m.historyFilter.Name = m.historyFilterInput.Value()
+		m.historyCursor = 0
+		return m, cmd
+	}
+
+	keys := m.keys.History
+	switch {
+	case key.Matches(msg, keys.Up):
+		m.historyCursor = clampCursor(m.historyCursor, -1, len(m.filteredHistory()))
+	case key.Matches(msg, keys.Down):
+		m.historyCursor = clampCursor(m.historyCursor, 1, len(m.filteredHistory()))
+	case key.Matches(msg, keys.Range):
+		m.historyRange = (m.historyRange + 1) % len(historyRanges)
+		m.historyCursor = 0
+	case key.Matches(msg, keys.Kind):
+		m.historyFilter.Kind = nextValue(historyKinds, m.historyFilter.Kind)
+		m.historyCursor = 0
+	case key.Matches(msg, keys.Outcome):
+		m.historyFilter.Outcome = nextValue(historyOutcomes, m.historyFilter.Outcome)
+		m.historyCursor = 0
+	case key.Matches(msg, keys.Filter):
+		m.historyFilterEditing = true
+		m.historyFilterInput.Focus()
+		return m, nil
+	case key.Matches(msg, keys.Clear):
+		m.historyFilter = history.Filter{}
+		m.historyRange = 0
+		m.historyFilterInput.SetValue("")
+		m.historyCursor = 0
+	case key.Matches(msg, keys.Export):
+		m.openExportDialog(0, m.historyRange)
+	case key.Matches(msg, keys.Reload):
+		return m, m.fetchRemoteHistory()
+	case key.Matches(msg, keys.Close):
+		m.state = StateNormal
+	}
+	return m, nil
+}
+
+// nextValue returns the value after current in values, wrapping around
+func nextValue(values []string, current string) string {
+	for i, v := range values {
+		if v == current {
+			return values[(i+1)%len(values)]
+		}
+	}
+	return values[0]
+}
@@ -1,4 +1,675 @@
 package ui
 
-// Key bindings are handled directly in model.go's handleKeyPress method.
-// This file is reserved for future migration to bubbles/key bindings if needed.
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Keys holds one key map per UI state. Bindings can be overridden from the
+// keybindings section of config.yaml, keyed by key map and action name, e.g.
+//
+//	keybindings:
+//	  normal:
+//	    deactivate: [x, delete]
+//	  history:
+//	    close: [esc]
+//
+// An empty list disables the action.
+type Keys struct {
+	Global        GlobalKeyMap
+	Session       SessionKeyMap
+	Normal        NormalKeyMap
+	Confirm       ConfirmKeyMap
+	Deactivate    DeactivateKeyMap
+	Justification JustificationKeyMap
+	Input         InputKeyMap
+	Results       ResultsKeyMap
+	History       HistoryKeyMap
+	Timeline      TimelineKeyMap
+	Stats         StatsKeyMap
+	Export        ExportKeyMap
+	Search        SearchKeyMap
+	Palette       PaletteKeyMap
+	Help          HelpKeyMap
+}
+
+// namedBinding exposes a binding under its config name
+type namedBinding struct {
+	name    string
+	binding *key.Binding
+}
+
+// GlobalKeyMap is active in every state
+type GlobalKeyMap struct {
+	ForceQuit key.Binding
+}
+
+func (k *GlobalKeyMap) bindings() []namedBinding {
+	return []namedBinding{{"force_quit", &k.ForceQuit}}
+}
+
+// SessionKeyMap applies while signing in, loading or after a fatal error
+type SessionKeyMap struct {
+	Quit   key.Binding
+	Retry  key.Binding
+	Login  key.Binding
+	Cancel key.Binding
+}
+
+func (k *SessionKeyMap) bindings() []namedBinding {
+	return []namedBinding{{"quit", &k.Quit}, {"retry", &k.Retry}, {"login", &k.Login}, {"cancel", &k.Cancel}}
+}
+
+// NormalKeyMap applies to the main view and its tabs
+type NormalKeyMap struct {
+	Up             key.Binding
+	Down           key.Binding
+	Left           key.Binding
+	Right          key.Binding
+	NextTab        key.Binding
+	Select         key.Binding
+	Search         key.Binding
+	ClearSearch    key.Binding
+	Sort           key.Binding
+	Filter         key.Binding
+	Favorite       key.Binding
+	Palette        key.Binding
+	SearchDelete   key.Binding // Delete the last character of the inline subscription search
+	Activate       key.Binding
+	Deactivate     key.Binding
+	Extend         key.Binding
+	Refresh        key.Binding
+	RefreshNames   key.Binding
+	Renew          key.Binding
+	AutoExtend     key.Binding
+	Durations      [4]key.Binding
+	CycleDuration  key.Binding
+	LogLevel       key.Binding
+	CopyLogs       key.Binding
+	History        key.Binding
+	Timeline       key.Binding
+	Stats          key.Binding
+	Export         key.Binding
+	ToggleStanding key.Binding
+	AutoRefresh    key.Binding
+	Help           key.Binding
+	Quit           key.Binding
+}
+
+func (k *NormalKeyMap) bindings() []namedBinding {
+	b := []namedBinding{
+		{"up", &k.Up}, {"down", &k.Down}, {"left", &k.Left}, {"right", &k.Right},
+		{"next_tab", &k.NextTab}, {"select", &k.Select}, {"search", &k.Search},
+		{"clear_search", &k.ClearSearch}, {"sort", &k.Sort}, {"filter", &k.Filter}, {"favorite", &k.Favorite}, {"palette", &k.Palette}, {"search_delete", &k.SearchDelete},
+		{"activate", &k.Activate}, {"deactivate", &k.Deactivate}, {"extend", &k.Extend}, {"refresh", &k.Refresh},
+		{"refresh_names", &k.RefreshNames}, {"renew", &k.Renew}, {"auto_extend", &k.AutoExtend},
+		{"cycle_duration", &k.CycleDuration}, {"log_level", &k.LogLevel}, {"copy_logs", &k.CopyLogs},
+		{"history", &k.History}, {"timeline", &k.Timeline}, {"stats", &k.Stats}, {"export", &k.Export}, {"toggle_standing", &k.ToggleStanding},
+		{"auto_refresh", &k.AutoRefresh}, {"help", &k.Help}, {"quit", &k.Quit},
+	}
+	return append(b, durationBindings(&k.Durations)...)
+}
+
+// FullHelp returns the help screen groups, in the order of normalHelpTitles
+func (k NormalKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Left, k.Right, k.NextTab},
+		{k.Select, k.Search, k.ClearSearch, k.Sort, k.Filter, k.Favorite, k.Palette},
+		{k.Activate, k.Deactivate, k.Extend, k.Refresh, k.RefreshNames, k.Renew, k.AutoExtend},
+		append(k.Durations[:], k.CycleDuration),
+		{k.LogLevel, k.CopyLogs, k.History, k.Timeline, k.Stats, k.Export, k.ToggleStanding, k.AutoRefresh, k.Help, k.Quit},
+	}
+}
+
+// normalHelpTitles names the groups returned by NormalKeyMap.FullHelp
+var normalHelpTitles = []string{"Navigation", "Selection & Search", "Actions", "Duration", "Display & Settings"}
+
+// ConfirmKeyMap applies to the activation confirmation dialog
+type ConfirmKeyMap struct {
+	Confirm       key.Binding
+	Cancel        key.Binding
+	Durations     [4]key.Binding
+	CycleDuration key.Binding
+}
+
+func (k *ConfirmKeyMap) bindings() []namedBinding {
+	b := []namedBinding{{"confirm", &k.Confirm}, {"cancel", &k.Cancel}, {"cycle_duration", &k.CycleDuration}}
+	return append(b, durationBindings(&k.Durations)...)
+}
+
+// DeactivateKeyMap applies to the deactivation confirmation dialog
+type DeactivateKeyMap struct {
+	Confirm key.Binding
+	Cancel  key.Binding
+}
+
+func (k *DeactivateKeyMap) bindings() []namedBinding {
+	return []namedBinding{{"confirm", &k.Confirm}, {"cancel", &k.Cancel}}
+}
+
+// JustificationKeyMap applies while typing an activation or extension justification
+type JustificationKeyMap struct {
+	Submit        key.Binding
+	Cancel        key.Binding
+	Durations     [4]key.Binding
+	CycleDuration key.Binding
+}
+
+func (k *JustificationKeyMap) bindings() []namedBinding {
+	b := []namedBinding{{"submit", &k.Submit}, {"cancel", &k.Cancel}, {"cycle_duration", &k.CycleDuration}}
+	return append(b, durationBindings(&k.Durations)...)
+}
+
+// InputKeyMap applies to single-field dialogs: renewal, auto-extend, the
+// history name filter and the step-up sign-in wait
+type InputKeyMap struct {
+	Submit key.Binding
+	Cancel key.Binding
+}
+
+func (k *InputKeyMap) bindings() []namedBinding {
+	return []namedBinding{{"submit", &k.Submit}, {"cancel", &k.Cancel}}
+}
+
+// ResultsKeyMap applies to the bulk operation results dialog
+type ResultsKeyMap struct {
+	Retry key.Binding
+	Close key.Binding
+}
+
+func (k *ResultsKeyMap) bindings() []namedBinding {
+	return []namedBinding{{"retry", &k.Retry}, {"close", &k.Close}}
+}
+
+// HistoryKeyMap applies to the activation history view
+type HistoryKeyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	Range   key.Binding
+	Kind    key.Binding
+	Outcome key.Binding
+	Filter  key.Binding
+	Clear   key.Binding
+	Reload  key.Binding
+	Export  key.Binding
+	Close   key.Binding
+}
+
+func (k *HistoryKeyMap) bindings() []namedBinding {
+	return []namedBinding{
+		{"up", &k.Up}, {"down", &k.Down}, {"range", &k.Range}, {"kind", &k.Kind},
+		{"outcome", &k.Outcome}, {"filter", &k.Filter}, {"clear", &k.Clear},
+		{"reload", &k.Reload}, {"export", &k.Export}, {"close", &k.Close},
+	}
+}
+
+// TimelineKeyMap applies to the timeline view
+type TimelineKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Reload key.Binding
+	Close  key.Binding
+}
+
+func (k *TimelineKeyMap) bindings() []namedBinding {
+	return []namedBinding{{"up", &k.Up}, {"down", &k.Down}, {"reload", &k.Reload}, {"close", &k.Close}}
+}
+
+// StatsKeyMap applies to the usage statistics view
+type StatsKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Reload key.Binding
+	Close  key.Binding
+}
+
+func (k *StatsKeyMap) bindings() []namedBinding {
+	return []namedBinding{{"up", &k.Up}, {"down", &k.Down}, {"reload", &k.Reload}, {"close", &k.Close}}
+}
+
+// ExportKeyMap applies to the export dialog
+type ExportKeyMap struct {
+	PrevField key.Binding
+	NextField key.Binding
+	Prev      key.Binding
+	Next      key.Binding
+	Submit    key.Binding
+	Cancel    key.Binding
+}
+
+func (k *ExportKeyMap) bindings() []namedBinding {
+	return []namedBinding{
+		{"prev_field", &k.PrevField}, {"next_field", &k.NextField}, {"prev", &k.Prev},
+		{"next", &k.Next}, {"submit", &k.Submit}, {"cancel", &k.Cancel},
+	}
+}
+
+// SearchKeyMap applies to the search dialog; other keys edit the query
+type SearchKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Submit key.Binding
+	Cancel key.Binding
+}
+
+func (k *SearchKeyMap) bindings() []namedBinding {
+	return []namedBinding{{"up", &k.Up}, {"down", &k.Down}, {"submit", &k.Submit}, {"cancel", &k.Cancel}}
+}
+
+// PaletteKeyMap applies to the command palette; other keys edit the query
+type PaletteKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Submit key.Binding
+	Cancel key.Binding
+}
+
+func (k *PaletteKeyMap) bindings() []namedBinding {
+	return []namedBinding{{"up", &k.Up}, {"down", &k.Down}, {"submit", &k.Submit}, {"cancel", &k.Cancel}}
+}
+
+// HelpKeyMap applies to the help screen
+type HelpKeyMap struct {
+	Close key.Binding
+}
+
+func (k *HelpKeyMap) bindings() []namedBinding {
+	return []namedBinding{{"close", &k.Close}}
+}
+
+func durationBindings(d *[4]key.Binding) []namedBinding {
+	b := make([]namedBinding, len(d))
+	for i := range d {
+		b[i] = namedBinding{fmt.Sprintf("duration_%d", i+1), &d[i]}
+	}
+	return b
+}
+
+// newBinding creates an enabled binding whose help shows its keys
+func newBinding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKeys(keys), desc))
+}
+
+// helpKeys formats keys for help text, e.g. "↑/k" or "x/Del"
+func helpKeys(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = keyName(k)
+	}
+	return strings.Join(names, "/")
+}
+
+// keyName returns the display name of a single key
+func keyName(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case " ":
+		return "Space"
+	case "delete":
+		return "Del"
+	case "backspace":
+		return "BS"
+	}
+	if len(k) == 1 {
+		return k
+	}
+	// Named keys and chords: "enter" -> "Enter", "ctrl+c" -> "Ctrl+C"
+	parts := strings.Split(k, "+")
+	for i, p := range parts {
+		if p != "" { // "ctrl++" splits into an empty part for the plus key
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "+")
+}
+
+func defaultDurations() [4]key.Binding {
+	var d [4]key.Binding
+	for i := range d {
+		n := fmt.Sprintf("%d", i+1)
+		d[i] = newBinding("preset "+n, n)
+	}
+	return d
+}
+
+// DefaultKeys returns the built-in key bindings
+func DefaultKeys() Keys {
+	return Keys{
+		Global: GlobalKeyMap{
+			ForceQuit: newBinding("quit", "ctrl+c"),
+		},
+		Session: SessionKeyMap{
+			Quit:   newBinding("quit", "q"),
+			Retry:  newBinding("retry", "r"),
+			Login:  newBinding("sign in", "l", "L"),
+			Cancel: newBinding("cancel", "esc"),
+		},
+		Normal: NormalKeyMap{
+			Up:             newBinding("move up", "up", "k"),
+			Down:           newBinding("move down", "down", "j"),
+			Left:           newBinding("previous tab", "left", "h"),
+			Right:          newBinding("next tab / open roles", "right", "l"),
+			NextTab:        newBinding("cycle tabs / toggle role list", "tab"),
+			Select:         newBinding("select/deselect item", " "),
+			Search:         newBinding("search/filter", "/"),
+			ClearSearch:    newBinding("clear search filter", "esc"),
+			Sort:           newBinding("cycle sort order of this tab", "o"),
+			Filter:         newBinding("cycle status filter of this tab (incl. favorites only)", "f"),
+			Favorite:       newBinding("pin/unpin item as a favorite", "*"),
+			Palette:        newBinding("command palette: jump to any item or run an action", "ctrl+p"),
+			SearchDelete:   newBinding("delete search character", "backspace"),
+			Activate:       newBinding("activate selected items", "enter"),
+			Deactivate:     newBinding("deactivate active items", "x", "delete"),
+			Extend:         newBinding("extend active items by the selected duration", "+", "="),
+			Refresh:        newBinding("refresh data from Azure", "r", "R", "f5"),
+			RefreshNames:   newBinding("refresh and re-resolve cached names", "M"),
+			Renew:          newBinding("request renewal of expiring eligibilities", "n"),
+			AutoExtend:     newBinding("auto-extend active items until a time", "A"),
+			Durations:      defaultDurations(),
+			CycleDuration:  newBinding("cycle duration", "d"),
+			LogLevel:       newBinding("cycle log level", "v"),
+			CopyLogs:       newBinding("copy logs to clipboard", "c", "C"),
+			History:        newBinding("show activation history (local and Azure)", "H"),
+			Timeline:       newBinding("show a timeline of the last 24h and next 8h", "T"),
+			Stats:          newBinding("show usage statistics for the last 90 days", "U"),
+			Export:         newBinding("export history or inventory to a file", "e", "E"),
+			ToggleStanding: newBinding("show/hide standing (permanent) assignments", "S"),
+			AutoRefresh:    newBinding("toggle auto-refresh", "a"),
+			Help:           newBinding("show/hide this help", "?"),
+			Quit:           newBinding("quit application", "q"),
+		},
+		Confirm: ConfirmKeyMap{
+			Confirm:       newBinding("confirm", "y", "enter"),
+			Cancel:        newBinding("cancel", "n", "esc"),
+			Durations:     defaultDurations(),
+			CycleDuration: newBinding("cycle duration", "tab"),
+		},
+		Deactivate: DeactivateKeyMap{
+			Confirm: newBinding("confirm", "y", "enter"),
+			Cancel:  newBinding("cancel", "n", "esc"),
+		},
+		Justification: JustificationKeyMap{
+			Submit:        newBinding("submit", "enter"),
+			Cancel:        newBinding("cancel", "esc"),
+			Durations:     defaultDurations(),
+			CycleDuration: newBinding("cycle duration", "tab"),
+		},
+		Input: InputKeyMap{
+			Submit: newBinding("submit", "enter"),
+			Cancel: newBinding("cancel", "esc"),
+		},
+		Results: ResultsKeyMap{
+			Retry: newBinding("retry failed", "r", "R"),
+			Close: newBinding("close", "enter", "esc", "q"),
+		},
+		History: HistoryKeyMap{
+			Up:      newBinding("move up", "up", "k"),
+			Down:    newBinding("move down", "down", "j"),
+			Range:   newBinding("date", "d"),
+			Kind:    newBinding("kind", "t"),
+			Outcome: newBinding("outcome", "o"),
+			Filter:  newBinding("name", "/"),
+			Clear:   newBinding("clear", "c"),
+			Reload:  newBinding("reload Azure", "r"),
+			Export:  newBinding("export", "e", "E"),
+			Close:   newBinding("close", "esc", "H", "q"),
+		},
+		Timeline: TimelineKeyMap{
+			Up:     newBinding("move up", "up", "k"),
+			Down:   newBinding("move down", "down", "j"),
+			Reload: newBinding("reload Azure", "r"),
+			Close:  newBinding("close", "esc", "T", "q"),
+		},
+		Stats: StatsKeyMap{
+			Up:     newBinding("move up", "up", "k"),
+			Down:   newBinding("move down", "down", "j"),
+			Reload: newBinding("reload Azure", "r"),
+			Close:  newBinding("close", "esc", "U", "q"),
+		},
+		Export: ExportKeyMap{
+			PrevField: newBinding("previous field", "up", "shift+tab"),
+			NextField: newBinding("next field", "down", "tab"),
+			Prev:      newBinding("previous value", "left", "h"),
+			Next:      newBinding("next value", "right", "l", " "),
+			Submit:    newBinding("export", "enter"),
+			Cancel:    newBinding("cancel", "esc"),
+		},
+		Search: SearchKeyMap{
+			Up:     newBinding("previous result", "up", "ctrl+k"),
+			Down:   newBinding("next result", "down", "ctrl+j"),
+			Submit: newBinding("filter and jump", "enter"),
+			Cancel: newBinding("filter", "esc"),
+		},
+		Palette: PaletteKeyMap{
+			Up:     newBinding("previous result", "up", "ctrl+k"),
+			Down:   newBinding("next result", "down", "ctrl+j"),
+			Submit: newBinding("run", "enter"),
+			Cancel: newBinding("close", "esc", "ctrl+p"),
+		},
+		Help: HelpKeyMap{
+			Close: newBinding("close help", "?", "esc", "q"),
+		},
+	}
+}
+
+// keyMaps lists every key map under its config name
+func (k *Keys) keyMaps() map[string][]namedBinding {
+	return map[string][]namedBinding{
+		"global":        k.Global.bindings(),
+		"session":       k.Session.bindings(),
+		"normal":        k.Normal.bindings(),
+		"confirm":       k.Confirm.bindings(),
+		"deactivate":    k.Deactivate.bindings(),
+		"justification": k.Justification.bindings(),
+		"input":         k.Input.bindings(),
+		"results":       k.Results.bindings(),
+		"history":       k.History.bindings(),
+		"timeline":      k.Timeline.bindings(),
+		"stats":         k.Stats.bindings(),
+		"export":        k.Export.bindings(),
+		"search":        k.Search.bindings(),
+		"palette":       k.Palette.bindings(),
+		"help":          k.Help.bindings(),
+	}
+}
+
+// LoadKeys applies config overrides to the default key bindings. Unknown key maps
+// or actions are skipped; a key map whose overrides make two actions share a key,
+// or take a global key, keeps its default bindings. Every problem is reported in
+// the returned error, along with keys that are still usable.
+func LoadKeys(overrides map[string]map[string][]string) (Keys, error) {
+	keys := DefaultKeys()
+	defaults := DefaultKeys()
+	maps := keys.keyMaps()
+	defaultMaps := defaults.keyMaps()
+
+	var errs []error
+	for _, mapName := range sortedKeys(overrides) {
+		bindings, ok := maps[mapName]
+		if !ok {
+			errs = append(errs, fmt.Errorf("keybindings: unknown key map %q", mapName))
+			continue
+		}
+		for _, action := range sortedKeys(overrides[mapName]) {
+			b := findBinding(bindings, action)
+			if b == nil {
+				errs = append(errs, fmt.Errorf("keybindings: unknown action %q in %s", action, mapName))
+				continue
+			}
+			if slices.Contains(overrides[mapName][action], "") {
+				errs = append(errs, fmt.Errorf("keybindings: empty key for %s in %s - using defaults", action, mapName))
+				continue
+			}
+			rebind(b, overrides[mapName][action])
+		}
+	}
+
+	// Global keys are checked first so conflicting key maps fall back to defaults
+	if conflicts := keyConflicts(maps["global"], nil); len(conflicts) > 0 {
+		errs = append(errs, fmt.Errorf("keybindings: global: %s - using defaults", strings.Join(conflicts, "; ")))
+		restore(maps["global"], defaultMaps["global"])
+	}
+	for _, mapName := range sortedKeys(maps) {
+		if mapName == "global" {
+			continue
+		}
+		if conflicts := keyConflicts(maps[mapName], maps["global"]); len(conflicts) > 0 {
+			errs = append(errs, fmt.Errorf("keybindings: %s: %s - using defaults", mapName, strings.Join(conflicts, "; ")))
+			restore(maps[mapName], defaultMaps[mapName])
+		}
+	}
+	return keys, errors.Join(errs...)
+}
+
+func findBinding(bindings []namedBinding, name string) *key.Binding {
+	for _, b := range bindings {
+		if b.name == name {
+			return b.binding
+		}
+	}
+	return nil
+}
+
+// rebind replaces a binding's keys, keeping its description; no keys disables it
+func rebind(b *key.Binding, keys []string) {
+	desc := b.Help().Desc
+	if len(keys) == 0 {
+		*b = key.NewBinding(key.WithDisabled(), key.WithHelp("", desc))
+		return
+	}
+	*b = newBinding(desc, keys...)
+}
+
+func restore(bindings, defaults []namedBinding) {
+	for i := range bindings {
+		*bindings[i].binding = *defaults[i].binding
+	}
+}
+
+// keyConflicts describes keys bound to more than one action in bindings, or
+// already taken by an action in global
+func keyConflicts(bindings, global []namedBinding) []string {
+	owner := make(map[string]string)
+	for _, b := range global {
+		for _, k := range b.binding.Keys() {
+			owner[k] = "global " + b.name
+		}
+	}
+	var conflicts []string
+	for _, b := range bindings {
+		if !b.binding.Enabled() {
+			continue
+		}
+		for _, k := range b.binding.Keys() {
+			if other, ok := owner[k]; ok {
+				conflicts = append(conflicts, fmt.Sprintf("%q is bound to both %s and %s", k, other, b.name))
+				continue
+			}
+			owner[k] = b.name
+		}
+	}
+	return conflicts
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// keyHint renders the first key of each binding with a short label for footers,
+// e.g. "↑↓ navigate"; it is empty when every binding is disabled
+func keyHint(label string, bindings ...key.Binding) string {
+	var keys []string
+	glyphs := true
+	for _, b := range bindings {
+		if !b.Enabled() || len(b.Keys()) == 0 {
+			continue
+		}
+		k := keyName(b.Keys()[0])
+		glyphs = glyphs && len([]rune(k)) == 1
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	if glyphs {
+		return strings.Join(keys, "") + " " + label
+	}
+	return strings.Join(keys, "/") + " " + label
+}
+
+// bindingHint is keyHint labelled with the binding's own description
+func bindingHint(b key.Binding) string {
+	return keyHint(b.Help().Desc, b)
+}
+
+// keyHints joins footer hints, skipping empty ones
+func keyHints(hints ...string) string {
+	var parts []string
+	for _, h := range hints {
+		if h != "" {
+			parts = append(parts, h)
+		}
+	}
+	return strings.Join(parts, " │ ")
+}
+
+// keyButton renders a binding's first key as a dialog button label, e.g. "[Y] Yes"
+func keyButton(b key.Binding, label string) string {
+	if !b.Enabled() || len(b.Keys()) == 0 {
+		return label
+	}
+	name := keyName(b.Keys()[0])
+	if len(name) == 1 {
+		name = strings.ToUpper(name)
+	}
+	return fmt.Sprintf("[%s] %s", name, label)
+}
+
+// durationKeys describes the keys that change the duration, e.g. "1-4 or Tab"
+func durationKeys(durations [4]key.Binding, cycle key.Binding) string {
+	var keys []string
+	for _, d := range durations {
+		if d.Enabled() && len(d.Keys()) > 0 {
+			keys = append(keys, keyName(d.Keys()[0]))
+		}
+	}
+	presets := strings.Join(keys, "/")
+	if presets == "1/2/3/4" {
+		presets = "1-4"
+	}
+	if !cycle.Enabled() || len(cycle.Keys()) == 0 {
+		return presets
+	}
+	if presets == "" {
+		return keyName(cycle.Keys()[0])
+	}
+	return presets + " or " + keyName(cycle.Keys()[0])
+}
+
+// durationIndex returns which duration preset binding matches msg, or -1
+func durationIndex(msg tea.KeyMsg, durations [4]key.Binding) int {
+	for i, d := range durations {
+		if key.Matches(msg, d) {
+			return i
+		}
+	}
+	return -1
+}
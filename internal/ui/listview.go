@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+)
+
+// sortMode orders the items of a list
+type sortMode int
+
+const (
+	sortDefault sortMode = iota // API order; soonest expiry first on the Active now tab
+	sortName
+	sortStatus // Expiring, active, pending, then inactive
+	sortExpiry // Soonest expiry first, items without one last
+	sortRecent // Most recently activated, extended or renewed first
+	sortTenant
+	sortModeCount
+)
+
+func (s sortMode) String() string {
+	switch s {
+	case sortName:
+		return "name"
+	case sortStatus:
+		return "status"
+	case sortExpiry:
+		return "expiry"
+	case sortRecent:
+		return "recently used"
+	case sortTenant:
+		return "tenant"
+	default:
+		return "default"
+	}
+}
+
+// statusFilter limits a list to items in some states
+type statusFilter int
+
+const (
+	filterAll statusFilter = iota
+	filterActive
+	filterInactive
+	filterExpiring
+	filterPending
+	filterFavorites
+	statusFilterCount
+)
+
+func (f statusFilter) String() string {
+	switch f {
+	case filterActive:
+		return "active"
+	case filterInactive:
+		return "inactive"
+	case filterExpiring:
+		return "expiring"
+	case filterPending:
+		return "pending"
+	case filterFavorites:
+		return "favorites"
+	default:
+		return "all"
+	}
+}
+
+func (f statusFilter) matches(it listItem) bool {
+	switch f {
+	case filterActive:
+		return it.status.IsActive()
+	case filterInactive:
+		return it.status == azure.StatusInactive
+	case filterExpiring:
+		return it.status == azure.StatusExpiringSoon
+	case filterPending:
+		return it.status == azure.StatusPending
+	case filterFavorites:
+		return it.favorite
+	}
+	return true
+}
+
+// listView is how one tab orders and filters its items
+type listView struct {
+	sort   sortMode
+	filter statusFilter
+}
+
+// label describes a non-default view for the panel title, e.g. " [sort: name] [only active]"
+func (v listView) label() string {
+	var s string
+	if v.sort != sortDefault {
+		s += " [sort: " + v.sort.String() + "]"
+	}
+	if v.filter != filterAll {
+		s += " [only " + v.filter.String() + "]"
+	}
+	return s
+}
+
+// listItem is what sorting and filtering need to know about one list row
+type listItem struct {
+	index     int // Position in the underlying slice
+	doc       searchDoc
+	status    azure.ActivationStatus
+	expiresAt *time.Time
+	lastUsed  time.Time // Zero unless sorting by sortRecent
+	favorite  bool      // Pinned to the top whatever the sort mode
+}
+
+// statusRank orders statuses for sortStatus
+func statusRank(s azure.ActivationStatus) int {
+	switch s {
+	case azure.StatusExpiringSoon:
+		return 0
+	case azure.StatusActive:
+		return 1
+	case azure.StatusPending:
+		return 2
+	}
+	return 3
+}
+
+// arrange drops the items v filters out and returns the indices of the rest,
+// favorites first and then in v's order. Ties keep the order items were given in.
+func (v listView) arrange(items []listItem) []int {
+	kept := make([]listItem, 0, len(items))
+	for _, it := range items {
+		if v.filter.matches(it) {
+			kept = append(kept, it)
+		}
+	}
+
+	byName := func(a, b listItem) bool { return strings.ToLower(a.doc.name) < strings.ToLower(b.doc.name) }
+	var less func(a, b listItem) bool
+	switch v.sort {
+	case sortName:
+		less = byName
+	case sortStatus:
+		less = func(a, b listItem) bool { return statusRank(a.status) < statusRank(b.status) }
+	case sortExpiry:
+		less = func(a, b listItem) bool {
+			if a.expiresAt == nil || b.expiresAt == nil {
+				return a.expiresAt != nil && b.expiresAt == nil
+			}
+			return a.expiresAt.Before(*b.expiresAt)
+		}
+	case sortRecent:
+		less = func(a, b listItem) bool { return a.lastUsed.After(b.lastUsed) }
+	case sortTenant:
+		less = func(a, b listItem) bool {
+			ta, tb := strings.ToLower(a.doc.tenant), strings.ToLower(b.doc.tenant)
+			if ta != tb {
+				return ta < tb
+			}
+			return byName(a, b)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		if kept[i].favorite != kept[j].favorite {
+			return kept[i].favorite
+		}
+		return less != nil && less(kept[i], kept[j])
+	})
+
+	indices := make([]int, len(kept))
+	for i, it := range kept {
+		indices[i] = it.index
+	}
+	return indices
+}
+
+// lastUsedLookup returns when each item was last activated, extended or renewed,
+// keyed by timelineKey. It only reads the history when tab sorts by recent use.
+func (m Model) lastUsedLookup(tab Tab) func(itemType, name string) time.Time {
+	if m.listViews[tab].sort != sortRecent {
+		return func(string, string) time.Time { return time.Time{} }
+	}
+	used := make(map[string]time.Time)
+	for _, e := range m.mergedHistory() {
+		if e.Success && e.Kind != HistoryDeactivate {
+			k := timelineKey(e.Type, e.Name)
+			if e.Time.After(used[k]) {
+				used[k] = e.Time
+			}
+		}
+	}
+	return func(itemType, name string) time.Time { return used[timelineKey(itemType, name)] }
+}
+
+// subscriptionStatus sums up a subscription for filtering and sorting: the most
+// urgent status of its roles, and the soonest expiry among them
+func subscriptionStatus(sub azure.LighthouseSubscription) (azure.ActivationStatus, *time.Time) {
+	status := sub.Status
+	var expiresAt *time.Time
+	for _, role := range sub.EligibleRoles {
+		if statusRank(role.Status) < statusRank(status) {
+			status = role.Status
+		}
+		if role.ExpiresAt != nil && (expiresAt == nil || role.ExpiresAt.Before(*expiresAt)) {
+			expiresAt = role.ExpiresAt
+		}
+	}
+	return status, expiresAt
+}
+
+// cycleSort switches the current tab to the next sort mode
+func (m *Model) cycleSort() {
+	v := &m.listViews[m.activeTab]
+	v.sort = (v.sort + 1) % sortModeCount
+	m.log(LogInfo, "Sorted by %s", v.sort)
+	m.revealCursors()
+	m.moveCursor(0)
+}
+
+// cycleStatusFilter switches the current tab to the next status filter
+func (m *Model) cycleStatusFilter() {
+	v := &m.listViews[m.activeTab]
+	v.filter = (v.filter + 1) % statusFilterCount
+	m.log(LogInfo, "Showing %s items", v.filter)
+	m.revealCursors()
+	m.moveCursor(0)
+}
@@ -2,18 +2,23 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/seb07-cloud/pim-tui/internal/azure"
 	"github.com/seb07-cloud/pim-tui/internal/config"
+	"github.com/seb07-cloud/pim-tui/internal/history"
+	"github.com/seb07-cloud/pim-tui/internal/notify"
 )
 
 // Re-export azure types for convenience
@@ -32,8 +37,12 @@ const (
 	TabRoles Tab = iota
 	TabGroups
 	TabSubscriptions
+	TabActive // Everything active or pending across the other tabs
 )
 
+// tabCount is the number of tabs NextTab cycles through
+const tabCount = TabActive + 1
+
 type LogLevel int
 
 const (
@@ -59,14 +68,15 @@ type LogEntry struct {
 	Message string
 }
 
-type ActivationHistoryEntry struct {
-	Time          time.Time
-	Type          string // "role" or "group"
-	Name          string
-	Duration      time.Duration
-	Justification string
-	Success       bool
-}
+// Re-export history types for convenience
+type ActivationHistoryEntry = history.Entry
+
+const (
+	HistoryActivate   = history.KindActivate
+	HistoryDeactivate = history.KindDeactivate
+	HistoryExtend     = history.KindExtend
+	HistoryRenew      = history.KindRenew
+)
 
 // SubscriptionRoleActivation wraps subscription info with the role for activation
 type SubscriptionRoleActivation struct {
@@ -88,8 +98,20 @@ const (
 	StateHelp
 	StateSearch
 	StateError
-	StateUnauthenticated  // User needs to authenticate (not an error, a prompt)
-	StateAuthenticating   // Device code auth in progress
+	StateUnauthenticated // User needs to authenticate (not an error, a prompt)
+	StateAuthenticating  // Device code auth in progress
+	StateStepUp          // Activation needs MFA / authentication context sign-in
+	StateResults         // Per-item outcome of a bulk activation/deactivation
+	StateHistory         // Activation history view
+	StateExport          // Export history/inventory to a file
+	StateRenew           // Confirm eligibility renewal with justification
+	StateRenewing        // Renewal requests in flight
+	StateAutoExtend      // Set or clear auto-extend rules
+	StatePalette         // Command palette over items and actions
+	StateExtend          // Confirm extension of active elevations with justification
+	StateExtending       // Extension requests in flight
+	StateTimeline        // Elevations over the last day and the next hours
+	StateStats           // Usage statistics per role, group and Azure role
 )
 
 type Model struct {
@@ -101,7 +123,8 @@ type Model struct {
 	version string
 
 	// Browser authentication
-	authCancelFunc context.CancelFunc // Cancel function for auth context
+	authCancelFunc   context.CancelFunc // Cancel function for auth context
+	stepUpCancelFunc context.CancelFunc // Cancel function for step-up authentication
 
 	// Data
 	tenant          *azure.Tenant
@@ -117,24 +140,52 @@ type Model struct {
 	rolesCursor      int
 	groupsCursor     int
 	lightCursor      int
-	subRoleCursor    int  // Cursor for navigating roles within a subscription
-	subRoleFocus     bool // True when focus is on role list in detail panel
-	selectedRoles    map[int]bool
-	selectedGroups   map[int]bool
-	selectedLight    map[int]bool
-	selectedSubRoles map[string]map[int]bool // subscription ID -> role index -> selected
+	subRoleCursor    int                        // Cursor for navigating roles within a subscription
+	subRoleFocus     bool                       // True when focus is on role list in detail panel
+	selectedRoles    map[string]bool            // Item key -> selected
+	selectedGroups   map[string]bool            // Item key -> selected
+	selectedLight    map[string]bool            // Subscription key -> selected
+	selectedSubRoles map[string]map[string]bool // Subscription ID -> role item key -> selected
+	activeCursor     int                        // Cursor on the Active now tab
+	selectedActive   map[string]bool            // Item key -> selected on the Active now tab
 
 	// Scroll offsets - independent per panel, preserved across tab switches
 	rolesScrollOffset  int // Scroll offset for roles list (index of first visible item)
 	groupsScrollOffset int // Scroll offset for groups list
 	lightScrollOffset  int // Scroll offset for lighthouse/subscriptions list
+	activeScrollOffset int // Scroll offset for the Active now list
+
+	listViews       [tabCount]listView // Sort mode and status filter per tab
+	favorites       map[string]bool    // itemKey or subscriptionKey -> pinned to the top
+	favoritesPath   string             // Favorites file, empty if unavailable
+	favoritesLoaded bool               // The favorites file has been read; saving may overwrite it
+
+	// Session state restored from the last launch
+	sessionPath    string         // Session file, empty if unavailable
+	sessionLoaded  bool           // The saved session has been read; quitting may overwrite it
+	pendingCursors map[Tab]string // Tab -> item key to move the cursor onto once the list loads
+	pendingSubRole string         // itemKey of the subscription role to focus once subscriptions load
 
 	// Loading state
-	loading          bool
-	loadingMessage   string
-	rolesLoaded      bool
-	groupsLoaded     bool
-	lighthouseLoaded bool
+	loading            bool
+	loadingMessage     string
+	rolesLoaded        bool
+	groupsLoaded       bool
+	lighthouseLoaded   bool
+	rolesFetching      bool                       // Roles request in flight
+	groupsFetching     bool                       // Groups request in flight
+	lighthouseFetching bool                       // Subscriptions request in flight
+	rolesErr           error                      // Last roles load error, shown as a tab badge
+	groupsErr          error                      // Last groups load error
+	lighthouseErr      error                      // Last subscriptions load error
+	progressCh         chan loadProgressMsg       // Fan-out progress from the azure client
+	loadProgress       map[string]loadProgressMsg // Latest progress per stage
+
+	// Last known state shown while the first refresh runs
+	snapshotTime    time.Time // When the displayed snapshot was saved
+	staleRoles      bool      // Roles still come from the snapshot
+	staleGroups     bool      // Groups still come from the snapshot
+	staleLighthouse bool      // Subscriptions still come from the snapshot
 
 	// Duration
 	duration      time.Duration
@@ -155,16 +206,67 @@ type Model struct {
 	searchInput          textinput.Model
 	pendingActivations   []interface{}
 	pendingDeactivations []interface{}
+	pendingRenewals      []interface{}
+	pendingExtensions    []interface{}
+
+	// Bulk operation outcomes
+	stepUpResults   []bulkResult // Items already finished before a step-up retry
+	stepUpCancelled bool         // The user cancelled the step-up sign-in; its retry fails with context.Canceled
+	bulkResults     []bulkResult // Results shown in the results dialog
+	bulkOperation   string       // "activation", "deactivation", "renewal" or "extension"
 
 	// Search/filter
-	searchActive bool
-	searchQuery  string
+	searchActive  bool
+	searchQuery   string
+	searchResults []paletteEntry // Ranked matches across all tabs while typing
+	searchCursor  int
 
 	// Activation history
-	activationHistory []ActivationHistoryEntry
+	activationHistory    []ActivationHistoryEntry
+	remoteHistory        []ActivationHistoryEntry // Requests read from the Azure audit trail
+	mergedEntries        []ActivationHistoryEntry // Both of the above without duplicates, see mergeHistory
+	remoteHistoryLoading bool
+	remoteHistoryFetched time.Time
+	remoteHistoryErr     error
+	historyStore         *history.Store  // On-disk audit trail, nil if unavailable
+	historyCursor        int             // Selected entry in the history view (0 = newest)
+	historyFilter        history.Filter  // Kind, name and outcome filter; dates come from historyRange
+	historyRange         int             // Index into historyRanges
+	historyFilterInput   textinput.Model // Name filter input
+	historyFilterEditing bool            // Name filter input has focus
+	timelineCursor       int             // Selected row in the timeline view
+	statsCursor          int             // Selected row in the statistics view
+
+	// Export dialog
+	exportWhat        int             // Index into exportTargets
+	exportFormat      int             // Index into export.Formats
+	exportRange       int             // Index into historyRanges
+	exportField       int             // Focused dialog field
+	exportPathInput   textinput.Model // Destination file
+	exportPathEdited  bool            // User typed a path; stop suggesting names
+	exportReturnState State           // State to restore when the dialog closes
+
+	// Notifications
+	output           *notify.Output // Program output shared with the terminal sinks
+	notifier         *notify.Notifier
+	expiryTracker    *notify.ExpiryTracker
+	lastExpiryCheck  time.Time
+	awaitingApproval map[string]string // Item key -> name of activations pending approval
+
+	// Auto-extend
+	autoExtendRules     map[string]*autoExtendRule // Item key -> rule
+	pendingAutoExtend   []interface{}              // Items the auto-extend dialog applies to
+	autoExtendInput     textinput.Model            // Target time as HH:MM
+	lastAutoExtendCheck time.Time
+
+	// Command palette
+	paletteInput   textinput.Model
+	paletteResults []paletteEntry // Ranked results for the current query
+	paletteCursor  int
 
 	// Help
 	help   help.Model
+	keys   Keys
 	width  int
 	height int
 
@@ -184,9 +286,42 @@ type groupsLoadedMsg struct{ groups []azure.Group }
 type lighthouseLoadedMsg struct {
 	subs []azure.LighthouseSubscription
 }
-type activationDoneMsg struct{ err error }
-type deactivationDoneMsg struct{ err error }
+
+// activationDoneMsg reports per-item outcomes; err is set only when the whole
+// operation failed before any item was attempted
+type activationDoneMsg struct {
+	err     error
+	results []bulkResult
+}
+
+// stepUpRequiredMsg signals that activations hit a Conditional Access claims
+// challenge; remaining holds the challenged items and done the finished ones
+type stepUpRequiredMsg struct {
+	claims    string
+	remaining []interface{}
+	done      []bulkResult
+}
+type deactivationDoneMsg struct {
+	err     error
+	results []bulkResult
+}
+type historyLoadedMsg struct {
+	entries []ActivationHistoryEntry
+	err     error
+}
+type historySavedMsg struct{ err error }
+type remoteHistoryLoadedMsg struct {
+	entries []ActivationHistoryEntry
+	err     error
+}
 type delayedRefreshMsg struct{} // Triggers a refresh after a delay
+
+// loadProgressMsg reports progress of a fan-out lookup (group names, subscriptions, tenant names)
+type loadProgressMsg struct {
+	stage string
+	done  int
+	total int
+}
 type tickMsg time.Time
 type errMsg struct {
 	err    error
@@ -211,21 +346,58 @@ func NewModel(cfg config.Config, version string) Model {
 	si.Placeholder = "Type to filter..."
 	si.CharLimit = 100
 
-	return Model{
+	hi := textinput.New()
+	hi.Placeholder = "Filter by name..."
+	hi.CharLimit = 100
+
+	ai := textinput.New()
+	ai.Placeholder = "HH:MM (empty turns auto-extend off)"
+	ai.CharLimit = 5
+
+	var store *history.Store
+	if path, err := history.DefaultPath(); err == nil {
+		store = history.NewStore(path)
+	}
+	favPath, _ := favoritesPath()
+	sessPath, _ := sessionPath()
+
+	output := notify.NewOutput(os.Stdout)
+	notifier, notifyErr := notify.New(cfg.Notifications, output)
+	keys, keysErr := LoadKeys(cfg.KeyBindings)
+
+	m := Model{
 		config:             cfg,
 		version:            version,
-		selectedRoles:      make(map[int]bool),
-		selectedGroups:     make(map[int]bool),
-		selectedLight:      make(map[int]bool),
-		selectedSubRoles:   make(map[string]map[int]bool),
+		selectedRoles:      make(map[string]bool),
+		selectedGroups:     make(map[string]bool),
+		selectedLight:      make(map[string]bool),
+		selectedSubRoles:   make(map[string]map[string]bool),
+		selectedActive:     make(map[string]bool),
+		favorites:          make(map[string]bool),
+		favoritesPath:      favPath,
+		sessionPath:        sessPath,
+		pendingCursors:     make(map[Tab]string),
 		duration:           time.Duration(cfg.DefaultDuration) * time.Hour,
 		durationIndex:      indexOf(cfg.DurationPresets, cfg.DefaultDuration),
 		logLevel:           parseLogLevel(cfg.LogLevel),
 		autoRefresh:        cfg.AutoRefreshEnabled,
 		help:               help.New(),
+		keys:               keys,
 		justificationInput: ti,
 		searchInput:        si,
+		historyFilterInput: hi,
+		exportPathInput:    newExportPathInput(),
+		historyStore:       store,
+		output:             output,
+		notifier:           notifier,
+		expiryTracker:      notify.NewExpiryTracker(cfg.Notifications.Thresholds),
+		awaitingApproval:   make(map[string]string),
+		autoExtendRules:    make(map[string]*autoExtendRule),
+		autoExtendInput:    ai,
+		paletteInput:       newPaletteInput(),
 		logs:               make([]LogEntry, 0),
+		progressCh:         make(chan loadProgressMsg, 64),
+		loadProgress:       make(map[string]loadProgressMsg),
 		state:              StateLoading,
 		loading:            true,
 		loadingMessage:     "Authenticating with Azure...",
@@ -234,6 +406,13 @@ func NewModel(cfg config.Config, version string) Model {
 		groupsScrollOffset: 0,
 		lightScrollOffset:  0,
 	}
+	if notifyErr != nil {
+		m.log(LogError, "Notifications: %v", notifyErr)
+	}
+	if keysErr != nil {
+		m.log(LogError, "Key bindings: %v", keysErr)
+	}
+	return m
 }
 
 func indexOf(slice []int, val int) int {
@@ -256,10 +435,21 @@ func parseLogLevel(s string) LogLevel {
 	}
 }
 
+// Output is the terminal the program must render to, shared with the bell and
+// OSC notification sinks so their escapes don't interleave with frames
+func (m Model) Output() *notify.Output {
+	return m.output
+}
+
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		initClientCmd(),
+		loadSnapshotCmd(),
+		loadHistoryCmd(m.historyStore, m.historyRetention()),
+		loadFavoritesCmd(m.favoritesPath),
+		loadSessionCmd(m.sessionPath),
 		tickCmd(),
+		waitForProgressCmd(m.progressCh),
 	)
 }
 
@@ -364,12 +554,12 @@ func loadUserInfoCmd(client *azure.Client) tea.Cmd {
 	}
 }
 
-func loadLighthouseCmd(client *azure.Client, groups []azure.Group) tea.Cmd {
+func loadLighthouseCmd(client *azure.Client) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		subs, err := client.GetLighthouseSubscriptions(ctx, groups)
+		subs, err := client.GetLighthouseSubscriptions(ctx)
 		if err != nil {
 			return errMsg{fmt.Errorf("failed to load lighthouse: %w", err), "lighthouse"}
 		}
@@ -377,6 +567,42 @@ func loadLighthouseCmd(client *azure.Client, groups []azure.Group) tea.Cmd {
 	}
 }
 
+// waitForProgressCmd blocks until the azure client reports fan-out progress
+func waitForProgressCmd(ch chan loadProgressMsg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// configureClient applies config limits to a new client and forwards its progress
+// reports to the UI. Progress is dropped rather than blocking workers when the UI lags.
+func (m *Model) configureClient() {
+	m.client.SetConcurrency(m.config.MaxConcurrency)
+	m.client.SetIncludeStanding(m.config.ShowStanding)
+	ch := m.progressCh
+	m.client.SetProgressFunc(func(stage string, done, total int) {
+		select {
+		case ch <- loadProgressMsg{stage: stage, done: done, total: total}:
+		default:
+		}
+	})
+}
+
+// openMetadataCache attaches the on-disk name cache for the signed-in tenant
+func (m *Model) openMetadataCache() {
+	if m.config.MetadataCacheTTL <= 0 || m.client == nil || m.tenant == nil {
+		return
+	}
+	ttl := time.Duration(m.config.MetadataCacheTTL) * time.Hour
+	cache, err := azure.OpenMetadataCache(m.tenant.ID, ttl)
+	if err != nil {
+		m.log(LogDebug, "Metadata cache disabled: %v", err)
+		return
+	}
+	m.client.SetMetadataCache(cache)
+	m.log(LogDebug, "Metadata cache: %d cached names", cache.Len())
+}
+
 func tickCmd() tea.Cmd {
 	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
 		return tickMsg(t)
@@ -424,6 +650,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case clientReadyMsg:
 		m.client = msg.client
+		m.configureClient()
 		m.loading = true
 		m.loadingMessage = "Loading tenant info..."
 		m.log(LogInfo, "Authentication successful")
@@ -448,18 +675,104 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		// Auth succeeded, proceed to loading
 		m.client = msg.client
+		m.configureClient()
 		m.state = StateLoading
 		m.loading = true
 		m.loadingMessage = "Loading tenant info..."
 		m.log(LogInfo, "Authentication successful")
 		return m, loadTenantCmd(m.client)
 
+	case historyLoadedMsg:
+		if msg.err != nil {
+			m.log(LogError, "Failed to load activation history: %v", msg.err)
+		}
+		// Entries recorded before the file finished loading are newer
+		m.activationHistory = append(msg.entries, m.activationHistory...)
+		m.mergeHistory()
+		m.log(LogDebug, "Loaded %d history entries", len(msg.entries))
+		return m, nil
+
+	case exportDoneMsg:
+		if msg.err != nil {
+			m.log(LogError, "Export failed: %v", msg.err)
+			return m, nil
+		}
+		m.log(LogInfo, "Exported %d %s entries to %s", msg.count, msg.what, msg.path)
+		return m, nil
+
+	case remoteHistoryLoadedMsg:
+		m.remoteHistoryLoading = false
+		m.remoteHistoryFetched = time.Now()
+		m.remoteHistoryErr = msg.err
+		if msg.err != nil {
+			m.log(LogError, "Azure request history incomplete: %v", msg.err)
+		}
+		// Keep what we had if every source failed
+		if msg.err == nil || len(msg.entries) > 0 {
+			m.remoteHistory = msg.entries
+			m.mergeHistory()
+		}
+		m.log(LogDebug, "Loaded %d requests from the Azure audit trail", len(msg.entries))
+		return m, nil
+
+	case historySavedMsg:
+		if msg.err != nil {
+			m.log(LogError, "Failed to save activation history: %v", msg.err)
+		}
+		return m, nil
+
+	case favoritesLoadedMsg:
+		if msg.err != nil {
+			m.log(LogError, "Failed to load favorites: %v - changes will not be saved", msg.err)
+			return m, nil
+		}
+		for _, k := range msg.keys {
+			m.favorites[k] = true
+		}
+		m.favoritesLoaded = true
+		return m, nil
+
+	case favoritesSavedMsg:
+		if msg.err != nil {
+			m.log(LogError, "Failed to save favorites: %v", msg.err)
+		}
+		return m, nil
+
+	case sessionLoadedMsg:
+		if msg.err != nil {
+			m.sessionLoaded = true
+			m.log(LogError, "Failed to restore the last session: %v", msg.err)
+			return m, nil
+		}
+		m.applySession(msg.state)
+		return m, nil
+
+	case sessionSavedMsg:
+		if msg.err != nil {
+			m.log(LogError, "Failed to save the session: %v", msg.err)
+		}
+		return m, nil
+
+	case snapshotLoadedMsg:
+		m.applySnapshot(msg.snap)
+		m.restoreCursors()
+		return m, nil
+
 	case tenantLoadedMsg:
+		if m.isStale() && m.tenant != nil && m.tenant.ID != msg.tenant.ID {
+			m.log(LogInfo, "Signed in to a different tenant - discarding cached data")
+			m.discardSnapshot()
+		}
 		m.tenant = msg.tenant
 		m.loadingMessage = "Loading PIM roles and groups..."
 		m.log(LogInfo, "Connected to tenant: %s", m.tenant.DisplayName)
+		m.openMetadataCache()
 		return m, tea.Batch(m.refreshCmd(), loadUserInfoCmd(m.client))
 
+	case loadProgressMsg:
+		m.loadProgress[msg.stage] = msg
+		return m, waitForProgressCmd(m.progressCh)
+
 	case userInfoLoadedMsg:
 		m.userDisplayName = msg.displayName
 		m.userEmail = msg.email
@@ -467,8 +780,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case rolesLoadedMsg:
+		cursors := m.cursorKeys()
 		m.roles = msg.roles
 		m.rolesLoaded = true
+		m.rolesFetching = false
+		m.rolesErr = nil
+		m.staleRoles = false
 		// Clamp scroll offset if list got shorter
 		if m.rolesScrollOffset >= len(m.roles) && len(m.roles) > 0 {
 			m.rolesScrollOffset = len(m.roles) - 1
@@ -476,12 +793,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.rolesScrollOffset = 0
 		}
 		m.log(LogInfo, "Loaded %d eligible roles", len(m.roles))
-		m.checkLoadingComplete()
-		return m, nil
+		m.keepCursors(cursors)
+		m.reconcileSelections()
+		m.restoreCursors()
+		return m, tea.Batch(m.checkLoadingComplete(), m.checkApprovals())
 
 	case groupsLoadedMsg:
+		cursors := m.cursorKeys()
 		m.groups = msg.groups
 		m.groupsLoaded = true
+		m.groupsFetching = false
+		m.groupsErr = nil
+		m.staleGroups = false
 		// Clamp scroll offset if list got shorter
 		if m.groupsScrollOffset >= len(m.groups) && len(m.groups) > 0 {
 			m.groupsScrollOffset = len(m.groups) - 1
@@ -489,12 +812,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.groupsScrollOffset = 0
 		}
 		m.log(LogInfo, "Loaded %d eligible groups", len(m.groups))
-		m.checkLoadingComplete()
-		return m, nil
+		m.keepCursors(cursors)
+		m.reconcileSelections()
+		m.restoreCursors()
+		return m, tea.Batch(m.checkLoadingComplete(), m.checkApprovals())
 
 	case lighthouseLoadedMsg:
+		cursors := m.cursorKeys()
 		m.lighthouse = msg.subs
 		m.lighthouseLoaded = true
+		m.lighthouseFetching = false
+		m.lighthouseErr = nil
+		m.staleLighthouse = false
 		// Sort by tenant name (already populated during load with cache)
 		sort.Slice(m.lighthouse, func(i, j int) bool {
 			if m.lighthouse[i].TenantName != m.lighthouse[j].TenantName {
@@ -514,34 +843,59 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			totalRoles += len(sub.EligibleRoles)
 		}
 		m.log(LogInfo, "Loaded %d subscriptions with %d eligible roles", len(m.lighthouse), totalRoles)
-		m.checkLoadingComplete()
-		return m, nil
+		m.keepCursors(cursors)
+		m.reconcileSelections()
+		m.restoreCursors()
+		return m, tea.Batch(m.checkLoadingComplete(), m.checkApprovals())
+
+	case stepUpRequiredMsg:
+		// Role is protected by an authentication context - re-authenticate with the
+		// requested claims and retry the remaining activations
+		ctx, cancel := context.WithCancel(context.Background())
+		m.stepUpCancelFunc = cancel
+		m.state = StateStepUp
+		m.pendingActivations = msg.remaining
+		m.stepUpResults = append(m.stepUpResults, msg.done...)
+		m.log(LogInfo, "Additional authentication required - complete sign-in in your browser")
+		m.log(LogDebug, "Claims challenge: %s", msg.claims)
+		return m, activateCmd(azure.WithClaims(ctx, msg.claims), m.client, msg.remaining,
+			m.justificationInput.Value(), m.duration, m.config.ActivationConcurrency)
 
 	case activationDoneMsg:
-		m.state = StateNormal
+		m.stepUpCancelFunc = nil
+		results := append(m.stepUpResults, msg.results...)
+		m.stepUpResults = nil
+		if m.stepUpCancelled {
+			// Items the user gave up on are neither failures nor history
+			m.stepUpCancelled = false
+			if errors.Is(msg.err, context.Canceled) {
+				msg.err = nil
+			}
+			results = withoutCancelled(results)
+			if len(results) == 0 {
+				return m, nil
+			}
+		}
 		if msg.err != nil {
+			m.state = StateNormal
 			m.log(LogError, "Activation failed: %v", msg.err)
-			return m, nil
+			return m, m.activationFailedNotification(msg.err)
 		}
-		m.log(LogInfo, "Activation completed successfully")
-		m.clearSelections()
-		// Immediate refresh + delayed refresh after 5s for Azure to process
-		return m, tea.Batch(m.refreshCmd(), delayedRefreshCmd(5*time.Second))
+		return m.finishBulk("activation", results)
 
 	case deactivationDoneMsg:
-		m.state = StateNormal
 		if msg.err != nil {
-			if strings.Contains(msg.err.Error(), "ActiveDurationTooShort") {
-				m.log(LogError, "Cannot deactivate: role must be active for at least 5 minutes")
-			} else {
-				m.log(LogError, "Deactivation failed: %v", msg.err)
-			}
+			m.state = StateNormal
+			m.log(LogError, "Deactivation failed: %v", msg.err)
 			return m, nil
 		}
-		m.log(LogInfo, "Deactivation completed successfully")
-		m.clearSelections()
-		// Immediate refresh + delayed refresh after 5s for Azure to process
-		return m, tea.Batch(m.refreshCmd(), delayedRefreshCmd(5*time.Second))
+		return m.finishBulk("deactivation", msg.results)
+
+	case renewalDoneMsg:
+		return m.finishBulk("renewal", msg.results)
+
+	case extensionDoneMsg:
+		return m.finishBulk("extension", msg.results)
 
 	case delayedRefreshMsg:
 		// Delayed refresh triggered after activation/deactivation
@@ -552,14 +906,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tickMsg:
+		expiryCmd := m.checkExpiryNotifications(time.Time(msg))
+		extendCmd := m.checkAutoExtend(time.Time(msg))
 		// Auto-refresh check (only in normal state)
 		if m.autoRefresh && m.client != nil && m.state == StateNormal &&
 			time.Since(m.lastRefresh) > time.Duration(m.config.AutoRefreshInterval)*time.Second {
 			m.lastRefresh = time.Now()
 			m.log(LogDebug, "Auto-refreshing...")
-			return m, tea.Batch(tickCmd(), m.refreshCmd())
+			return m, tea.Batch(tickCmd(), m.refreshCmd(), expiryCmd, extendCmd)
+		}
+		return m, tea.Batch(tickCmd(), expiryCmd, extendCmd)
+
+	case autoExtendDoneMsg:
+		return m, m.finishAutoExtend(msg)
+
+	case notifyDoneMsg:
+		if msg.err != nil {
+			m.log(LogError, "Notification failed: %v", msg.err)
 		}
-		return m, tickCmd()
+		return m, nil
 
 	case errMsg:
 		m.err = msg.err
@@ -572,13 +937,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.state = StateError
 		case "roles":
 			m.rolesLoaded = true // Mark as loaded even on error so UI progresses
-			m.checkLoadingComplete()
+			m.rolesFetching = false
+			m.rolesErr = msg.err
+			m.restoreCursors()
+			return m, m.checkLoadingComplete()
 		case "groups":
 			m.groupsLoaded = true // Mark as loaded even on error so UI progresses
-			m.checkLoadingComplete()
+			m.groupsFetching = false
+			m.groupsErr = msg.err
+			m.restoreCursors()
+			return m, m.checkLoadingComplete()
 		case "lighthouse":
 			m.lighthouseLoaded = true // Mark as loaded even on error so UI progresses
-			m.checkLoadingComplete()
+			m.lighthouseFetching = false
+			m.lighthouseErr = msg.err
+			m.restoreCursors()
+			return m, m.checkLoadingComplete()
 		case "tenant":
 			m.loading = false
 			m.state = StateError
@@ -589,42 +963,78 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *Model) checkLoadingComplete() {
-	// Consider loading complete when we have tenant and all data sources have loaded
-	if m.tenant != nil && m.rolesLoaded && m.groupsLoaded && m.lighthouseLoaded {
-		m.loading = false
+// checkLoadingComplete leaves the loading screen as soon as the first data source
+// has reported - tabs still loading show their own spinner. Once every source has
+// reported it returns a command saving the snapshot when all data is fresh.
+func (m *Model) checkLoadingComplete() tea.Cmd {
+	if m.tenant == nil {
+		return nil
+	}
+	// Snapshot data may already be on screen - don't pull the user out of a dialog
+	if m.state == StateLoading && (m.rolesLoaded || m.groupsLoaded || m.lighthouseLoaded) {
 		m.state = StateNormal
-		m.lastRefresh = time.Now()
 	}
+	if !m.rolesLoaded || !m.groupsLoaded || !m.lighthouseLoaded {
+		return nil
+	}
+	m.loading = false
+	m.lastRefresh = time.Now()
+	if m.isStale() {
+		return nil
+	}
+	m.snapshotTime = time.Time{}
+	return m.saveSnapshotCmd()
 }
 
+// refreshCmd reloads every tab independently; each tab renders as soon as its own loader returns
 func (m *Model) refreshCmd() tea.Cmd {
+	m.rolesFetching = true
+	m.groupsFetching = true
+	m.lighthouseFetching = true
 	return tea.Batch(
 		loadRolesCmd(m.client),
 		loadGroupsCmd(m.client),
-		loadLighthouseCmd(m.client, nil), // groups not needed for lighthouse API
+		loadLighthouseCmd(m.client),
 	)
 }
 
+// tabLoadState reports whether a tab is waiting for data and its last load error
+func (m Model) tabLoadState(tab Tab) (loading bool, err error) {
+	switch tab {
+	case TabRoles:
+		return m.rolesFetching || !m.rolesLoaded, m.rolesErr
+	case TabGroups:
+		return m.groupsFetching || !m.groupsLoaded, m.groupsErr
+	case TabSubscriptions:
+		return m.lighthouseFetching || !m.lighthouseLoaded, m.lighthouseErr
+	case TabActive:
+		return m.dashboardLoadState()
+	}
+	return false, nil
+}
+
 func (m *Model) clearSelections() {
-	m.selectedRoles = make(map[int]bool)
-	m.selectedGroups = make(map[int]bool)
-	m.selectedLight = make(map[int]bool)
-	m.selectedSubRoles = make(map[string]map[int]bool)
+	m.selectedRoles = make(map[string]bool)
+	m.selectedGroups = make(map[string]bool)
+	m.selectedLight = make(map[string]bool)
+	m.selectedSubRoles = make(map[string]map[string]bool)
+	m.selectedActive = make(map[string]bool)
 }
 
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Always allow quit
-	if msg.String() == "ctrl+c" {
-		return m, tea.Quit
+	if key.Matches(msg, m.keys.Global.ForceQuit) {
+		return m, m.quit()
 	}
 
+	session := m.keys.Session
+
 	// In error state, only allow quit or retry
 	if m.state == StateError {
-		switch msg.String() {
-		case "q":
-			return m, tea.Quit
-		case "r":
+		switch {
+		case key.Matches(msg, session.Quit):
+			return m, m.quit()
+		case key.Matches(msg, session.Retry):
 			m.state = StateLoading
 			m.loading = true
 			m.loadingMessage = "Retrying authentication..."
@@ -636,18 +1046,18 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// In loading state, only allow quit
 	if m.state == StateLoading {
-		if msg.String() == "q" {
-			return m, tea.Quit
+		if key.Matches(msg, session.Quit) {
+			return m, m.quit()
 		}
 		return m, nil
 	}
 
 	// In unauthenticated state, allow login or quit
 	if m.state == StateUnauthenticated {
-		switch msg.String() {
-		case "q":
-			return m, tea.Quit
-		case "l", "L":
+		switch {
+		case key.Matches(msg, session.Quit):
+			return m, m.quit()
+		case key.Matches(msg, session.Login):
 			// Start browser authentication
 			ctx, cancel := context.WithCancel(context.Background())
 			m.authCancelFunc = cancel
@@ -660,13 +1070,13 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// In authenticating state, allow cancel or quit
 	if m.state == StateAuthenticating {
-		switch msg.String() {
-		case "q":
+		switch {
+		case key.Matches(msg, session.Quit):
 			if m.authCancelFunc != nil {
 				m.authCancelFunc()
 			}
-			return m, tea.Quit
-		case "esc":
+			return m, m.quit()
+		case key.Matches(msg, session.Cancel):
 			if m.authCancelFunc != nil {
 				m.authCancelFunc()
 			}
@@ -679,99 +1089,140 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Handle special states
 	switch m.state {
 	case StateHelp:
-		if msg.String() == "?" || msg.String() == "esc" || msg.String() == "q" {
+		if key.Matches(msg, m.keys.Help.Close) {
 			m.state = StateNormal
 		}
 		return m, nil
 
 	case StateConfirm:
-		switch msg.String() {
-		case "y", "enter":
+		keys := m.keys.Confirm
+		switch {
+		case key.Matches(msg, keys.Confirm):
 			m.state = StateJustification
 			m.justificationInput.Focus()
 			return m, textinput.Blink
-		case "n", "esc":
+		case key.Matches(msg, keys.Cancel):
 			m.state = StateNormal
 			m.pendingActivations = nil
-		case "1", "2", "3", "4":
-			idx := int(msg.String()[0] - '1')
-			m.setDurationByIndex(idx)
-		case "tab":
+		case key.Matches(msg, keys.CycleDuration):
 			m.cycleDuration()
+		default:
+			if idx := durationIndex(msg, keys.Durations); idx >= 0 {
+				m.setDurationByIndex(idx)
+			}
 		}
 		return m, nil
 
 	case StateConfirmDeactivate:
-		switch msg.String() {
-		case "y", "enter":
+		switch {
+		case key.Matches(msg, m.keys.Deactivate.Confirm):
 			return m.startDeactivation()
-		case "n", "esc":
+		case key.Matches(msg, m.keys.Deactivate.Cancel):
 			m.state = StateNormal
 			m.pendingDeactivations = nil
 		}
 		return m, nil
 
+	case StateHistory:
+		return m.handleHistoryKey(msg)
+
+	case StateTimeline:
+		return m.handleTimelineKey(msg)
+
+	case StateStats:
+		return m.handleStatsKey(msg)
+
+	case StateExport:
+		return m.handleExportKey(msg)
+
+	case StateRenew:
+		return m.handleRenewKey(msg)
+
+	case StateRenewing, StateExtending:
+		return m, nil
+
+	case StateExtend:
+		return m.handleExtendKey(msg)
+
+	case StateAutoExtend:
+		return m.handleAutoExtendKey(msg)
+
+	case StatePalette:
+		return m.handlePaletteKey(msg)
+
+	case StateResults:
+		switch {
+		case key.Matches(msg, m.keys.Results.Retry):
+			return m.retryFailed()
+		case key.Matches(msg, m.keys.Results.Close):
+			m.state = StateNormal
+			m.bulkResults = nil
+		}
+		return m, nil
+
 	case StateJustification:
-		switch msg.String() {
-		case "enter":
+		keys := m.keys.Justification
+		switch {
+		case key.Matches(msg, keys.Submit):
 			_, err := validateJustification(m.justificationInput.Value())
 			if err != nil {
 				m.log(LogError, "%v", err)
 				return m, nil
 			}
 			return m.startActivation()
-		case "esc":
+		case key.Matches(msg, keys.Cancel):
 			m.state = StateNormal
 			m.pendingActivations = nil
 			return m, nil
-		case "1", "2", "3", "4":
-			idx := int(msg.String()[0] - '1')
-			m.setDurationByIndex(idx)
-			return m, nil
-		case "tab":
+		case key.Matches(msg, keys.CycleDuration):
 			m.cycleDuration()
 			return m, nil
-		default:
-			var cmd tea.Cmd
-			m.justificationInput, cmd = m.justificationInput.Update(msg)
-			return m, cmd
 		}
+		if idx := durationIndex(msg, keys.Durations); idx >= 0 {
+			m.setDurationByIndex(idx)
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.justificationInput, cmd = m.justificationInput.Update(msg)
+		return m, cmd
 
 	case StateActivating:
 		return m, nil
 
-	case StateSearch:
-		switch msg.String() {
-		case "enter", "esc":
+	case StateStepUp:
+		if key.Matches(msg, m.keys.Input.Cancel) {
+			if m.stepUpCancelFunc != nil {
+				m.stepUpCancelFunc()
+			}
+			m.stepUpCancelFunc = nil
+			m.stepUpCancelled = true
 			m.state = StateNormal
-			m.searchQuery = m.searchInput.Value()
-			m.searchActive = m.searchQuery != ""
-			return m, nil
-		default:
-			var cmd tea.Cmd
-			m.searchInput, cmd = m.searchInput.Update(msg)
-			m.searchQuery = m.searchInput.Value()
-			m.searchActive = m.searchQuery != ""
-			return m, cmd
+			m.pendingActivations = nil
+			m.log(LogInfo, "Step-up authentication cancelled")
 		}
+		return m, nil
+
+	case StateSearch:
+		return m.handleSearchKey(msg)
 	}
 
 	// Normal state key handling
-	switch msg.String() {
-	case "q":
-		return m, tea.Quit
+	keys := m.keys.Normal
+	switch {
+	case key.Matches(msg, keys.Quit):
+		return m, m.quit()
 
-	case "?":
+	case key.Matches(msg, keys.Help):
 		m.state = StateHelp
 		return m, nil
 
-	case "up", "k":
+	case key.Matches(msg, keys.Up):
 		m.moveCursor(-1)
 
-	case "down", "j":
+	case key.Matches(msg, keys.Down):
 		m.moveCursor(1)
 
-	case "left", "h":
+	case key.Matches(msg, keys.Left):
 		// If in subscription role focus, exit back to subscription list
 		if m.activeTab == TabSubscriptions && m.subRoleFocus {
 			m.subRoleFocus = false
@@ -783,7 +1234,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.subRoleFocus = false
 		}
 
-	case "right", "l":
+	case key.Matches(msg, keys.Right):
 		// If on subscriptions tab with roles available, enter role focus mode
 		if m.activeTab == TabSubscriptions && !m.subRoleFocus {
 			if m.lightCursor < len(m.lighthouse) && len(m.lighthouse[m.lightCursor].EligibleRoles) > 0 {
@@ -793,12 +1244,12 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 		// Switch tabs - scroll offsets preserved independently per panel
-		if m.activeTab < TabSubscriptions {
+		if m.activeTab < TabActive {
 			m.activeTab++
 			m.subRoleFocus = false
 		}
 
-	case "tab":
+	case key.Matches(msg, keys.NextTab):
 		// If on subscriptions tab, toggle between list and role detail
 		if m.activeTab == TabSubscriptions {
 			if m.lightCursor < len(m.lighthouse) && len(m.lighthouse[m.lightCursor].EligibleRoles) > 0 {
@@ -810,20 +1261,32 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 		// Cycle tabs - scroll offsets preserved independently per panel
-		m.activeTab = (m.activeTab + 1) % 3
+		m.activeTab = (m.activeTab + 1) % tabCount
 		m.subRoleFocus = false
 
-	case " ":
+	case key.Matches(msg, keys.Select):
 		m.toggleSelection()
 
-	case "enter":
+	case key.Matches(msg, keys.Activate):
+		if !m.requireClient() {
+			return m, nil
+		}
 		return m.initiateActivation()
 
-	case "x", "delete":
+	case key.Matches(msg, keys.Deactivate):
+		if !m.requireClient() {
+			return m, nil
+		}
 		return m.initiateDeactivation()
 
-	case "backspace":
-		// In subscriptions tab with active search, backspace removes last char
+	case key.Matches(msg, keys.Extend):
+		if !m.requireClient() {
+			return m, nil
+		}
+		return m.initiateExtension()
+
+	case key.Matches(msg, keys.SearchDelete):
+		// Removes the last character of the inline subscriptions search
 		if m.activeTab == TabSubscriptions && m.searchQuery != "" {
 			m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
 			m.searchInput.SetValue(m.searchQuery)
@@ -833,45 +1296,70 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if len(visibleIndices) > 0 {
 				m.lightCursor = visibleIndices[0]
 			}
+			m.revealCursors()
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Refresh):
+		return m, m.refresh()
+
+	case key.Matches(msg, keys.Renew):
+		if !m.requireClient() {
 			return m, nil
 		}
-		// Otherwise, treat as deactivation
-		return m.initiateDeactivation()
+		return m.initiateRenewal()
 
-	case "r", "R":
-		if m.client != nil {
-			m.log(LogInfo, "Refreshing...")
-			m.lastRefresh = time.Now()
-			return m, m.refreshCmd()
+	case key.Matches(msg, keys.AutoExtend):
+		if !m.requireClient() {
+			return m, nil
 		}
+		return m.initiateAutoExtend()
+
+	case key.Matches(msg, keys.ToggleStanding):
+		return m, m.toggleStanding()
 
-	case "a":
-		m.autoRefresh = !m.autoRefresh
-		m.log(LogInfo, "Auto-refresh %s", map[bool]string{true: "enabled", false: "disabled"}[m.autoRefresh])
+	case key.Matches(msg, keys.RefreshNames):
+		return m, m.refreshNames()
 
-	case "1", "2", "3", "4":
-		idx := int(msg.String()[0] - '1')
-		m.setDurationByIndex(idx)
+	case key.Matches(msg, keys.History):
+		return m, m.openHistory()
 
-	case "d":
+	case key.Matches(msg, keys.Timeline):
+		return m, m.openTimeline()
+	case key.Matches(msg, keys.Stats):
+		return m, m.openStats()
+	case key.Matches(msg, keys.Sort):
+		m.cycleSort()
+		return m, nil
+	case key.Matches(msg, keys.Filter):
+		m.cycleStatusFilter()
+		return m, nil
+	case key.Matches(msg, keys.Favorite):
+		return m, m.toggleFavorite()
+
+	case key.Matches(msg, keys.AutoRefresh):
+		m.toggleAutoRefresh()
+
+	case key.Matches(msg, keys.CycleDuration):
 		m.cycleDuration()
 
-	case "v":
+	case key.Matches(msg, keys.LogLevel):
 		m.cycleLogLevel()
 
-	case "c", "C":
+	case key.Matches(msg, keys.CopyLogs):
 		m.copyLogs()
 
-	case "e", "E":
-		m.exportHistory()
+	case key.Matches(msg, keys.Export):
+		m.openExportDialog(0, 0)
+		return m, nil
 
-	case "/":
-		m.state = StateSearch
-		m.searchInput.SetValue(m.searchQuery)
-		m.searchInput.Focus()
-		return m, textinput.Blink
+	case key.Matches(msg, keys.Search):
+		return m, m.openSearch()
 
-	case "escape":
+	case key.Matches(msg, keys.Palette):
+		return m, m.openPalette()
+
+	case key.Matches(msg, keys.ClearSearch):
 		// Clear search if active
 		if m.searchActive {
 			m.searchActive = false
@@ -880,6 +1368,10 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	default:
+		if idx := durationIndex(msg, keys.Durations); idx >= 0 {
+			m.setDurationByIndex(idx)
+			return m, nil
+		}
 		// Inline search for subscriptions tab - typing filters directly
 		if m.activeTab == TabSubscriptions && !m.subRoleFocus {
 			// Only handle printable characters (letters, numbers, spaces)
@@ -895,6 +1387,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					if len(visibleIndices) > 0 {
 						m.lightCursor = visibleIndices[0]
 					}
+					m.revealCursors()
 				}
 			}
 		}
@@ -903,6 +1396,64 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// requireClient logs a notice and returns false while the Azure client is not ready
+func (m *Model) requireClient() bool {
+	if m.client == nil {
+		m.log(LogInfo, "Still connecting to Azure - showing cached data")
+		return false
+	}
+	return true
+}
+
+// refresh reloads roles, groups and subscriptions from Azure
+func (m *Model) refresh() tea.Cmd {
+	if m.client == nil {
+		return nil
+	}
+	m.log(LogInfo, "Refreshing...")
+	m.lastRefresh = time.Now()
+	return m.refreshCmd()
+}
+
+// refreshNames refreshes after dropping cached group, subscription and tenant names
+func (m *Model) refreshNames() tea.Cmd {
+	if m.client == nil {
+		return nil
+	}
+	if err := m.client.ClearMetadataCache(); err != nil {
+		m.log(LogError, "Failed to clear metadata cache: %v", err)
+	}
+	m.log(LogInfo, "Refreshing with fresh group, subscription and tenant names...")
+	m.lastRefresh = time.Now()
+	return m.refreshCmd()
+}
+
+func (m *Model) toggleStanding() tea.Cmd {
+	m.config.ShowStanding = !m.config.ShowStanding
+	m.log(LogInfo, "Standing assignments %s", map[bool]string{true: "shown", false: "hidden"}[m.config.ShowStanding])
+	if m.client == nil {
+		return nil
+	}
+	m.client.SetIncludeStanding(m.config.ShowStanding)
+	m.clearSelections()
+	return m.refreshCmd()
+}
+
+func (m *Model) toggleAutoRefresh() {
+	m.autoRefresh = !m.autoRefresh
+	m.log(LogInfo, "Auto-refresh %s", map[bool]string{true: "enabled", false: "disabled"}[m.autoRefresh])
+}
+
+// openHistory shows the history view, loading the Azure request history once
+func (m *Model) openHistory() tea.Cmd {
+	m.historyCursor = 0
+	m.state = StateHistory
+	if m.remoteHistoryFetched.IsZero() {
+		return m.fetchRemoteHistory()
+	}
+	return nil
+}
+
 func (m *Model) copyLogs() {
 	if len(m.logs) == 0 {
 		return
@@ -924,39 +1475,6 @@ func (m *Model) copyLogs() {
 	}
 }
 
-func (m *Model) exportHistory() {
-	if len(m.activationHistory) == 0 {
-		m.log(LogInfo, "No activation history to export")
-		return
-	}
-
-	var lines []string
-	lines = append(lines, "Activation History Export")
-	lines = append(lines, fmt.Sprintf("Generated: %s", time.Now().Format(time.RFC3339)))
-	lines = append(lines, "")
-	lines = append(lines, "Time\tType\tName\tDuration\tJustification\tSuccess")
-	lines = append(lines, strings.Repeat("-", 80))
-
-	for _, entry := range m.activationHistory {
-		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%v",
-			entry.Time.Format("2006-01-02 15:04:05"),
-			entry.Type,
-			entry.Name,
-			formatDuration(entry.Duration),
-			entry.Justification,
-			entry.Success,
-		)
-		lines = append(lines, line)
-	}
-
-	text := strings.Join(lines, "\n")
-	if err := clipboard.WriteAll(text); err != nil {
-		m.log(LogError, "Failed to export history: %v", err)
-	} else {
-		m.log(LogInfo, "Exported %d activation history entries to clipboard", len(m.activationHistory))
-	}
-}
-
 func clampCursor(cursor, delta, length int) int {
 	if length == 0 {
 		return 0
@@ -971,30 +1489,26 @@ func clampCursor(cursor, delta, length int) int {
 	return cursor
 }
 
-// getVisibleSubscriptionIndices returns indices of subscriptions that match the current search filter
+// getVisibleSubscriptionIndices returns indices of subscriptions that match the
+// current search and status filter, in the tab's sort order
 func (m *Model) getVisibleSubscriptionIndices() []int {
-	indices := make([]int, 0, len(m.lighthouse))
+	q := m.search()
+	lastUsed := m.lastUsedLookup(TabSubscriptions)
+	items := make([]listItem, 0, len(m.lighthouse))
 	for i, sub := range m.lighthouse {
-		if m.searchActive && m.searchQuery != "" {
-			query := strings.ToLower(m.searchQuery)
-			// Search in subscription name, tenant name, and role names
-			match := strings.Contains(strings.ToLower(sub.DisplayName), query) ||
-				strings.Contains(strings.ToLower(sub.TenantName), query)
-			if !match {
-				for _, role := range sub.EligibleRoles {
-					if strings.Contains(strings.ToLower(role.RoleDefinitionName), query) {
-						match = true
-						break
-					}
-				}
-			}
-			if !match {
-				continue
+		if !subscriptionMatches(q, sub) {
+			continue
+		}
+		status, expiresAt := subscriptionStatus(sub)
+		item := listItem{index: i, doc: subscriptionSearchDoc(sub), status: status, expiresAt: expiresAt, favorite: m.subscriptionFavorite(sub)}
+		for _, role := range sub.EligibleRoles {
+			if used := lastUsed("azure-role", role.RoleDefinitionName+" on "+sub.DisplayName); used.After(item.lastUsed) {
+				item.lastUsed = used
 			}
 		}
-		indices = append(indices, i)
+		items = append(items, item)
 	}
-	return indices
+	return m.listViews[TabSubscriptions].arrange(items)
 }
 
 // getCurrentSubscription returns the subscription that should be displayed in the detail pane
@@ -1038,18 +1552,8 @@ func (m *Model) moveCursor(delta int) {
 			if len(visibleIndices) == 0 {
 				return
 			}
-			// Find current position in visible list
-			currentVisibleIdx := 0
-			for idx, i := range visibleIndices {
-				if i == m.lightCursor {
-					currentVisibleIdx = idx
-					break
-				}
-			}
-			// Move within visible list
-			newVisibleIdx := clampCursor(currentVisibleIdx, delta, len(visibleIndices))
 			oldCursor := m.lightCursor
-			m.lightCursor = visibleIndices[newVisibleIdx]
+			newVisibleIdx := moveWithin(&m.lightCursor, delta, visibleIndices)
 			// Reset role cursor when changing subscriptions
 			if oldCursor != m.lightCursor {
 				m.subRoleCursor = 0
@@ -1058,16 +1562,45 @@ func (m *Model) moveCursor(delta int) {
 			m.lightScrollOffset = m.adjustScrollOffset(newVisibleIdx, m.lightScrollOffset, len(visibleIndices), displayHeight)
 		}
 	case TabRoles:
-		m.rolesCursor = clampCursor(m.rolesCursor, delta, len(m.roles))
+		// Navigate through roles matching the search only
+		visibleIndices := m.visibleRoleIndices()
+		if len(visibleIndices) == 0 {
+			return
+		}
+		pos := moveWithin(&m.rolesCursor, delta, visibleIndices)
 		// Adjust scroll offset to keep cursor visible
-		m.rolesScrollOffset = m.adjustScrollOffset(m.rolesCursor, m.rolesScrollOffset, len(m.roles), displayHeight)
+		m.rolesScrollOffset = m.adjustScrollOffset(pos, m.rolesScrollOffset, len(visibleIndices), displayHeight)
 	case TabGroups:
-		m.groupsCursor = clampCursor(m.groupsCursor, delta, len(m.groups))
+		visibleIndices := m.visibleGroupIndices()
+		if len(visibleIndices) == 0 {
+			return
+		}
+		pos := moveWithin(&m.groupsCursor, delta, visibleIndices)
 		// Adjust scroll offset to keep cursor visible
-		m.groupsScrollOffset = m.adjustScrollOffset(m.groupsCursor, m.groupsScrollOffset, len(m.groups), displayHeight)
+		m.groupsScrollOffset = m.adjustScrollOffset(pos, m.groupsScrollOffset, len(visibleIndices), displayHeight)
+	case TabActive:
+		// The dashboard list has one more line of bulk action hints
+		count := len(m.dashboardEntries())
+		m.activeCursor = clampCursor(m.activeCursor, delta, count)
+		m.activeScrollOffset = m.adjustScrollOffset(m.activeCursor, m.activeScrollOffset, count, displayHeight-1)
 	}
 }
 
+// moveWithin moves cursor by delta through the visible item indices and returns
+// its new position in visible. A cursor on a hidden item starts from the first.
+func moveWithin(cursor *int, delta int, visible []int) int {
+	current := 0
+	for pos, i := range visible {
+		if i == *cursor {
+			current = pos
+			break
+		}
+	}
+	pos := clampCursor(current, delta, len(visible))
+	*cursor = visible[pos]
+	return pos
+}
+
 // adjustScrollOffset adjusts scroll offset to keep cursor visible within display window
 func (m *Model) adjustScrollOffset(cursor, currentOffset, listLen, displayHeight int) int {
 	if listLen <= displayHeight {
@@ -1106,12 +1639,18 @@ func (m *Model) toggleSelection() {
 			if m.lightCursor < len(m.lighthouse) {
 				sub := m.lighthouse[m.lightCursor]
 				if m.subRoleCursor < len(sub.EligibleRoles) {
+					role := sub.EligibleRoles[m.subRoleCursor]
+					if role.Standing {
+						m.log(LogInfo, "Standing assignments are not managed through PIM")
+						return
+					}
 					if m.selectedSubRoles[sub.ID] == nil {
-						m.selectedSubRoles[sub.ID] = make(map[int]bool)
+						m.selectedSubRoles[sub.ID] = make(map[string]bool)
 					}
-					m.selectedSubRoles[sub.ID][m.subRoleCursor] = !m.selectedSubRoles[sub.ID][m.subRoleCursor]
-					if !m.selectedSubRoles[sub.ID][m.subRoleCursor] {
-						delete(m.selectedSubRoles[sub.ID], m.subRoleCursor)
+					key := subRoleKey(sub, role)
+					m.selectedSubRoles[sub.ID][key] = !m.selectedSubRoles[sub.ID][key]
+					if !m.selectedSubRoles[sub.ID][key] {
+						delete(m.selectedSubRoles[sub.ID], key)
 					}
 				}
 			}
@@ -1131,55 +1670,66 @@ func (m *Model) toggleSelection() {
 					// Deselect all
 					delete(m.selectedSubRoles, sub.ID)
 				} else {
-					// Select all roles
-					m.selectedSubRoles[sub.ID] = make(map[int]bool)
-					for i := range sub.EligibleRoles {
-						m.selectedSubRoles[sub.ID][i] = true
+					// Select all roles that PIM can activate
+					m.selectedSubRoles[sub.ID] = make(map[string]bool)
+					for _, role := range sub.EligibleRoles {
+						if !role.Standing {
+							m.selectedSubRoles[sub.ID][subRoleKey(sub, role)] = true
+						}
 					}
 				}
 			}
 		}
 	case TabRoles:
-		m.selectedRoles[m.rolesCursor] = !m.selectedRoles[m.rolesCursor]
+		if m.rolesCursor < len(m.roles) && m.roles[m.rolesCursor].Standing {
+			m.log(LogInfo, "Standing assignments are not managed through PIM")
+			return
+		}
+		if m.rolesCursor < len(m.roles) {
+			toggleKey(m.selectedRoles, itemKey(m.roles[m.rolesCursor]))
+		}
 	case TabGroups:
-		m.selectedGroups[m.groupsCursor] = !m.selectedGroups[m.groupsCursor]
+		if m.groupsCursor < len(m.groups) && m.groups[m.groupsCursor].Standing {
+			m.log(LogInfo, "Standing assignments are not managed through PIM")
+			return
+		}
+		if m.groupsCursor < len(m.groups) {
+			toggleKey(m.selectedGroups, itemKey(m.groups[m.groupsCursor]))
+		}
+	case TabActive:
+		m.toggleDashboardSelection()
 	}
 }
 
 func (m *Model) initiateActivation() (tea.Model, tea.Cmd) {
-	// Collect pending activations
+	// Collect pending activations; standing assignments are already active
+	// and never go through PIM
 	m.pendingActivations = nil
 
 	switch m.activeTab {
 	case TabSubscriptions:
 		// Collect selected roles from subscriptions
-		for subID, roleSelections := range m.selectedSubRoles {
-			// Find the subscription
-			for _, sub := range m.lighthouse {
-				if sub.ID == subID {
-					for roleIdx := range roleSelections {
-						if roleIdx < len(sub.EligibleRoles) {
-							m.pendingActivations = append(m.pendingActivations, SubscriptionRoleActivation{
-								SubscriptionID:   sub.ID,
-								SubscriptionName: sub.DisplayName,
-								Role:             sub.EligibleRoles[roleIdx],
-							})
-						}
-					}
-					break
+		for _, sub := range m.lighthouse {
+			for _, role := range sub.EligibleRoles {
+				if !role.Standing && m.selectedSubRoles[sub.ID][subRoleKey(sub, role)] {
+					m.pendingActivations = append(m.pendingActivations, SubscriptionRoleActivation{
+						SubscriptionID:   sub.ID,
+						SubscriptionName: sub.DisplayName,
+						Role:             role,
+					})
 				}
 			}
 		}
 	case TabRoles:
-		for idx := range m.selectedRoles {
-			if idx < len(m.roles) {
-				m.pendingActivations = append(m.pendingActivations, m.roles[idx])
+		for _, role := range m.roles {
+			if !role.Standing && m.selectedRoles[itemKey(role)] {
+				m.pendingActivations = append(m.pendingActivations, role)
 			}
 		}
 	case TabGroups:
-		for idx := range m.selectedGroups {
-			if idx < len(m.groups) {
-				m.pendingActivations = append(m.pendingActivations, m.groups[idx])
+		for _, group := range m.groups {
+			if !group.Standing && m.selectedGroups[itemKey(group)] {
+				m.pendingActivations = append(m.pendingActivations, group)
 			}
 		}
 	}
@@ -1199,85 +1749,197 @@ func (m *Model) startActivation() (tea.Model, tea.Cmd) {
 	duration := m.duration
 	pending := m.pendingActivations
 
-	// Track activation history
-	for _, item := range pending {
-		entry := ActivationHistoryEntry{
-			Time:          time.Now(),
-			Duration:      duration,
-			Justification: justification,
-			Success:       true, // Will be updated if failed
-		}
-		switch v := item.(type) {
-		case azure.Role:
-			entry.Type = "role"
-			entry.Name = v.DisplayName
-		case azure.Group:
-			entry.Type = "group"
-			entry.Name = v.DisplayName
-		case SubscriptionRoleActivation:
-			entry.Type = "azure-role"
-			entry.Name = fmt.Sprintf("%s on %s", v.Role.RoleDefinitionName, v.SubscriptionName)
-		}
-		m.activationHistory = append(m.activationHistory, entry)
-	}
+	return m, activateCmd(context.Background(), client, pending, justification, duration, m.config.ActivationConcurrency)
+}
 
-	return m, func() tea.Msg {
-		ctx := context.Background()
-		for _, item := range pending {
+// activateCmd activates the items concurrently with at most limit requests in flight.
+// Items rejected with a claims challenge are reported as stepUpRequiredMsg unless ctx
+// already carries step-up claims, in which case they are plain failures to avoid
+// prompting in a loop. All challenged items are retried with the first challenge's
+// claims; items needing a different authentication context fail on the retry.
+func activateCmd(ctx context.Context, client *azure.Client, items []interface{}, justification string, duration time.Duration, limit int) tea.Cmd {
+	return func() tea.Msg {
+		results := runBulk(ctx, items, limit, func(ctx context.Context, item interface{}) (azure.RequestResult, error) {
 			switch v := item.(type) {
 			case azure.Role:
-				if err := client.ActivateRole(ctx, v.RoleDefinitionID, v.DirectoryScopeID, justification, duration); err != nil {
-					return activationDoneMsg{err}
-				}
+				return client.ActivateRole(ctx, v.RoleDefinitionID, v.DirectoryScopeID, justification, duration)
 			case azure.Group:
-				if err := client.ActivateGroup(ctx, v.ID, v.RoleDefinitionID, justification, duration); err != nil {
-					return activationDoneMsg{err}
-				}
+				return client.ActivateGroup(ctx, v.ID, v.RoleDefinitionID, justification, duration)
 			case SubscriptionRoleActivation:
-				if err := client.ActivateAzureRole(ctx, v.Role.Scope, v.Role.RoleDefinitionID, v.Role.RoleEligibilityID, justification, duration); err != nil {
-					return activationDoneMsg{err}
+				return client.ActivateAzureRole(ctx, v.Role.Scope, v.Role.RoleDefinitionID, v.Role.RoleEligibilityID, justification, duration)
+			}
+			return azure.RequestResult{}, fmt.Errorf("unsupported item type %T", item)
+		})
+
+		if azure.HasClaims(ctx) {
+			return activationDoneMsg{results: results}
+		}
+		var claims string
+		var remaining []interface{}
+		var done []bulkResult
+		for _, r := range results {
+			var challenge *azure.ClaimsChallengeError
+			if errors.As(r.err, &challenge) {
+				if claims == "" {
+					claims = challenge.Claims
 				}
+				remaining = append(remaining, r.item)
+				continue
 			}
+			done = append(done, r)
 		}
-		return activationDoneMsg{nil}
+		if len(remaining) > 0 {
+			return stepUpRequiredMsg{claims: claims, remaining: remaining, done: done}
+		}
+		return activationDoneMsg{results: results}
 	}
 }
 
+// finishBulk records and logs per-item outcomes and shows the results dialog if anything failed
+func (m *Model) finishBulk(operation string, results []bulkResult) (tea.Model, tea.Cmd) {
+	saveCmd := tea.Batch(appendHistoryCmd(m.historyStore, m.recordHistory(operation, results)),
+		m.trackBulkNotifications(operation, results))
+	succeeded, failed := splitResults(results)
+	for _, r := range results {
+		if r.err != nil {
+			m.log(LogError, "%s failed for %s: %s", capitalize(operation), pendingItemName(r.item), bulkErrorReason(r.err))
+			m.log(LogDebug, "%s error for %s: %v", capitalize(operation), pendingItemName(r.item), r.err)
+		} else {
+			m.log(LogDebug, "%s succeeded for %s", capitalize(operation), pendingItemName(r.item))
+		}
+	}
+
+	// Immediate refresh + delayed refresh after 5s for Azure to process
+	cmd := saveCmd
+	if len(succeeded) > 0 {
+		cmd = tea.Batch(saveCmd, m.refreshCmd(), delayedRefreshCmd(5*time.Second))
+	}
+
+	if len(failed) == 0 {
+		m.state = StateNormal
+		m.log(LogInfo, "%s completed successfully", capitalize(operation))
+		m.clearSelections()
+		return *m, cmd
+	}
+
+	m.log(LogError, "%d of %d %ss failed", len(failed), len(results), operation)
+	m.bulkResults = results
+	m.bulkOperation = operation
+	m.state = StateResults
+	return *m, cmd
+}
+
+// recordHistory appends one history entry per attempted item with its real outcome
+// and returns the new entries
+func (m *Model) recordHistory(operation string, results []bulkResult) []ActivationHistoryEntry {
+	kind := HistoryActivate
+	duration := m.duration
+	justification := m.justificationInput.Value()
+	switch operation {
+	case "deactivation":
+		kind = HistoryDeactivate
+		duration = 0
+		justification = ""
+	case "renewal":
+		kind = HistoryRenew
+		duration = m.renewalDuration()
+	case "extension":
+		kind = HistoryExtend
+	}
+
+	now := time.Now()
+	entries := make([]ActivationHistoryEntry, 0, len(results))
+	for _, r := range results {
+		itemType, scope := pendingItemDetails(r.item)
+		entry := ActivationHistoryEntry{
+			Time:          now,
+			Kind:          kind,
+			Type:          itemType,
+			Name:          pendingItemName(r.item),
+			Scope:         scope,
+			Duration:      duration,
+			Justification: justification,
+			Success:       r.err == nil,
+			RequestID:     r.result.RequestID,
+			Status:        r.result.Status,
+			ExpiresAt:     r.result.ExpiresAt,
+		}
+		if r.err != nil {
+			entry.Error = r.err.Error()
+		}
+		entries = append(entries, entry)
+	}
+	m.appendHistory(entries...)
+	return entries
+}
+
+// retryFailed re-runs only the failed items of the last bulk operation
+func (m *Model) retryFailed() (tea.Model, tea.Cmd) {
+	_, failed := splitResults(m.bulkResults)
+	m.bulkResults = nil
+	if len(failed) == 0 {
+		m.state = StateNormal
+		return m, nil
+	}
+	m.log(LogInfo, "Retrying %d failed %s(s)...", len(failed), m.bulkOperation)
+	switch m.bulkOperation {
+	case "deactivation":
+		m.pendingDeactivations = failed
+		return m.startDeactivation()
+	case "renewal":
+		m.pendingRenewals = failed
+		return m.startRenewal()
+	case "extension":
+		m.pendingExtensions = failed
+		return m.startExtension()
+	}
+	m.pendingActivations = failed
+	return m.startActivation()
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
 func (m *Model) initiateDeactivation() (tea.Model, tea.Cmd) {
 	// Collect active items for deactivation
 	m.pendingDeactivations = nil
 
 	switch m.activeTab {
 	case TabRoles:
-		for idx := range m.selectedRoles {
-			if idx < len(m.roles) && m.roles[idx].Status.IsActive() {
-				m.pendingDeactivations = append(m.pendingDeactivations, m.roles[idx])
+		for _, role := range m.roles {
+			if m.selectedRoles[itemKey(role)] && role.Status.IsActive() {
+				m.pendingDeactivations = append(m.pendingDeactivations, role)
 			}
 		}
 	case TabGroups:
-		for idx := range m.selectedGroups {
-			if idx < len(m.groups) && m.groups[idx].Status.IsActive() {
-				m.pendingDeactivations = append(m.pendingDeactivations, m.groups[idx])
+		for _, group := range m.groups {
+			if m.selectedGroups[itemKey(group)] && group.Status.IsActive() {
+				m.pendingDeactivations = append(m.pendingDeactivations, group)
 			}
 		}
 	case TabSubscriptions:
 		// Collect selected active roles from subscriptions
-		for subID, roleSelections := range m.selectedSubRoles {
-			for _, sub := range m.lighthouse {
-				if sub.ID == subID {
-					for roleIdx := range roleSelections {
-						if roleIdx < len(sub.EligibleRoles) && sub.EligibleRoles[roleIdx].Status.IsActive() {
-							m.pendingDeactivations = append(m.pendingDeactivations, SubscriptionRoleActivation{
-								SubscriptionID:   sub.ID,
-								SubscriptionName: sub.DisplayName,
-								Role:             sub.EligibleRoles[roleIdx],
-							})
-						}
-					}
-					break
+		for _, sub := range m.lighthouse {
+			for _, role := range sub.EligibleRoles {
+				if m.selectedSubRoles[sub.ID][subRoleKey(sub, role)] && role.Status.IsActive() {
+					m.pendingDeactivations = append(m.pendingDeactivations, SubscriptionRoleActivation{
+						SubscriptionID:   sub.ID,
+						SubscriptionName: sub.DisplayName,
+						Role:             role,
+					})
 				}
 			}
 		}
+	case TabActive:
+		for _, e := range m.dashboardTargets() {
+			if e.status.IsActive() && !e.standing {
+				m.pendingDeactivations = append(m.pendingDeactivations, e.item)
+			}
+		}
 	}
 
 	if len(m.pendingDeactivations) == 0 {
@@ -1294,25 +1956,21 @@ func (m *Model) startDeactivation() (tea.Model, tea.Cmd) {
 	client := m.client
 	pending := m.pendingDeactivations
 
+	limit := m.config.ActivationConcurrency
+
 	return m, func() tea.Msg {
-		ctx := context.Background()
-		for _, item := range pending {
+		results := runBulk(context.Background(), pending, limit, func(ctx context.Context, item interface{}) (azure.RequestResult, error) {
 			switch v := item.(type) {
 			case azure.Role:
-				if err := client.DeactivateRole(ctx, v.RoleDefinitionID, v.DirectoryScopeID); err != nil {
-					return deactivationDoneMsg{err}
-				}
+				return client.DeactivateRole(ctx, v.RoleDefinitionID, v.DirectoryScopeID)
 			case azure.Group:
-				if err := client.DeactivateGroup(ctx, v.ID, v.RoleDefinitionID); err != nil {
-					return deactivationDoneMsg{err}
-				}
+				return client.DeactivateGroup(ctx, v.ID, v.RoleDefinitionID)
 			case SubscriptionRoleActivation:
-				if err := client.DeactivateAzureRole(ctx, v.Role.Scope, v.Role.RoleDefinitionID); err != nil {
-					return deactivationDoneMsg{err}
-				}
+				return client.DeactivateAzureRole(ctx, v.Role.Scope, v.Role.RoleDefinitionID)
 			}
-		}
-		return deactivationDoneMsg{nil}
+			return azure.RequestResult{}, fmt.Errorf("unsupported item type %T", item)
+		})
+		return deactivationDoneMsg{results: results}
 	}
 }
 
@@ -1,8 +1,14 @@
 package ui
 
 import (
+	"context"
+	"fmt"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
 )
 
 func TestClampCursor(t *testing.T) {
@@ -368,3 +374,118 @@ func TestLogLevel_String(t *testing.T) {
 		})
 	}
 }
+
+// TestRunBulk tests that bulk operations stay within the limit and keep input order
+func TestRunBulk(t *testing.T) {
+	items := []interface{}{"a", "b", "c", "d", "e", "f"}
+	var inFlight, peak int32
+
+	results := runBulk(context.Background(), items, 2, func(ctx context.Context, item interface{}) (azure.RequestResult, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		if item == "c" {
+			return azure.RequestResult{}, fmt.Errorf("boom")
+		}
+		return azure.RequestResult{RequestID: item.(string)}, nil
+	})
+
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", peak)
+	}
+	if len(results) != len(items) {
+		t.Fatalf("results length = %d, want %d", len(results), len(items))
+	}
+	for i, r := range results {
+		if r.item != items[i] {
+			t.Errorf("results[%d].item = %v, want %v", i, r.item, items[i])
+		}
+		if (r.err != nil) != (items[i] == "c") {
+			t.Errorf("results[%d].err = %v", i, r.err)
+		}
+		if r.err == nil && r.result.RequestID != items[i] {
+			t.Errorf("results[%d].result.RequestID = %q, want %q", i, r.result.RequestID, items[i])
+		}
+	}
+
+	succeeded, failed := splitResults(results)
+	if len(succeeded) != 5 || len(failed) != 1 {
+		t.Errorf("splitResults = %d succeeded, %d failed, want 5, 1", len(succeeded), len(failed))
+	}
+}
+
+// TestBulkErrorReason tests mapping of PIM error codes to readable reasons
+func TestBulkErrorReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"active too short", fmt.Errorf("API error: ActiveDurationTooShort"), "must be active for at least 5 minutes before deactivation"},
+		{"already active", fmt.Errorf("RoleAssignmentExists: conflict"), "already active"},
+		{"unknown error passes through", fmt.Errorf("network down"), "network down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bulkErrorReason(tt.err); got != tt.want {
+				t.Errorf("bulkErrorReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMergedHistory tests that Azure records are merged without duplicating local entries
+func TestMergedHistory(t *testing.T) {
+	now := time.Now()
+	end := now.Add(2 * time.Hour)
+	remote := remoteHistoryEntries([]azure.AuditRecord{
+		{RequestID: "arm-1", Source: "azure-role", Action: "SelfActivate", Time: now.Add(-time.Hour), Name: "Reader", Status: "Provisioned"},
+		{RequestID: "graph-1", Source: "role", Action: "selfActivate", Time: now.Add(-30 * time.Second), Name: "Global Reader", Status: "Provisioned", StartsAt: &now, ExpiresAt: &end},
+		{RequestID: "graph-2", Source: "role", Action: "selfActivate", Time: now.Add(-48 * time.Hour), Name: "Global Reader", Status: "Denied"},
+		{RequestID: "graph-3", Source: "role", Action: "adminAssign", Time: now, Name: "Owner", Status: "Provisioned"},
+	})
+
+	if len(remote) != 3 {
+		t.Fatalf("remoteHistoryEntries() = %d entries, want 3 (admin requests skipped)", len(remote))
+	}
+	if !remote[1].Remote || remote[1].Kind != HistoryActivate || remote[1].Duration != 2*time.Hour {
+		t.Errorf("remote[1] = %+v, want remote activation lasting 2h", remote[1])
+	}
+	if remote[2].Success || remote[2].Error != "Denied" {
+		t.Errorf("remote[2] = %+v, want failure with Denied", remote[2])
+	}
+
+	m := testModel(StateHistory)
+	m.activationHistory = []ActivationHistoryEntry{
+		{Time: now.Add(-time.Hour), Kind: HistoryActivate, Type: "azure-role", Name: "Reader", RequestID: "ARM-1", Success: true},
+		{Time: now, Kind: HistoryActivate, Type: "role", Name: "Global Reader", RequestID: "pim-legacy-id", Success: true},
+	}
+	m.remoteHistory = remote
+	m.mergeHistory()
+
+	merged := m.mergedHistory()
+	if len(merged) != 3 {
+		t.Fatalf("mergedHistory() = %d entries, want 3", len(merged))
+	}
+	if merged[0].RequestID != "graph-2" || !merged[0].Remote {
+		t.Errorf("merged[0] = %+v, want the older remote-only request first", merged[0])
+	}
+	for _, e := range merged[1:] {
+		if e.Remote {
+			t.Errorf("entry %q duplicated from Azure", e.RequestID)
+		}
+	}
+
+	// Entries recorded later are merged in right away
+	m.appendHistory(ActivationHistoryEntry{Time: now.Add(time.Minute), Kind: HistoryDeactivate, Type: "role", Name: "Global Reader", Success: true})
+	if merged := m.mergedHistory(); len(merged) != 4 || merged[3].Kind != HistoryDeactivate {
+		t.Errorf("mergedHistory() after appendHistory = %+v, want the new entry last", merged)
+	}
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/seb07-cloud/pim-tui/internal/notify"
+)
+
+// expiryCheckInterval throttles expiry threshold checks on the UI tick
+const expiryCheckInterval = time.Second
+
+type notifyDoneMsg struct {
+	event notify.Event
+	err   error
+}
+
+// notifyCmd delivers an event to the configured sinks off the UI goroutine
+func notifyCmd(n *notify.Notifier, e notify.Event) tea.Cmd {
+	if !n.Enabled() {
+		return nil
+	}
+	return func() tea.Msg {
+		return notifyDoneMsg{event: e, err: n.Notify(context.Background(), e)}
+	}
+}
+
+// activeItem is an active elevation with a known end time
+type activeItem struct {
+	item      interface{}
+	expiresAt time.Time
+}
+
+// activeElevations returns every active, time-bound elevation across all tabs.
+// Standing assignments never expire and are left out.
+func (m Model) activeElevations() []activeItem {
+	var items []activeItem
+	for _, r := range m.roles {
+		if r.Status.IsActive() && !r.Standing && r.ExpiresAt != nil {
+			items = append(items, activeItem{r, *r.ExpiresAt})
+		}
+	}
+	for _, g := range m.groups {
+		if g.Status.IsActive() && !g.Standing && g.ExpiresAt != nil {
+			items = append(items, activeItem{g, *g.ExpiresAt})
+		}
+	}
+	for _, sub := range m.lighthouse {
+		for _, role := range sub.EligibleRoles {
+			if role.Status.IsActive() && !role.Standing && role.ExpiresAt != nil {
+				item := SubscriptionRoleActivation{SubscriptionID: sub.ID, SubscriptionName: sub.DisplayName, Role: role}
+				items = append(items, activeItem{item, *role.ExpiresAt})
+			}
+		}
+	}
+	return items
+}
+
+// checkExpiryNotifications raises a notification for each elevation that crossed
+// one of the configured thresholds since the last check
+func (m *Model) checkExpiryNotifications(now time.Time) tea.Cmd {
+	if !m.notifier.Enabled() || m.expiryTracker == nil || now.Sub(m.lastExpiryCheck) < expiryCheckInterval {
+		return nil
+	}
+	m.lastExpiryCheck = now
+	m.expiryTracker.Prune(now)
+
+	var cmds []tea.Cmd
+	for _, a := range m.activeElevations() {
+		left, due := m.expiryTracker.Due(itemKey(a.item), a.expiresAt, now)
+		if !due {
+			continue
+		}
+		name := pendingItemName(a.item)
+		m.log(LogInfo, "%s expires in %s", name, formatDuration(left))
+		cmds = append(cmds, notifyCmd(m.notifier, notify.Event{
+			Kind:    notify.KindExpiring,
+			Title:   "PIM elevation expiring",
+			Message: fmt.Sprintf("%s expires in %s", name, formatDuration(left)),
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// trackBulkNotifications remembers activations waiting for approval and raises
+// a notification for failed activations
+func (m *Model) trackBulkNotifications(operation string, results []bulkResult) tea.Cmd {
+	if operation != "activation" {
+		return nil
+	}
+	var failed []string
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, pendingItemName(r.item))
+			continue
+		}
+		if strings.EqualFold(r.result.Status, "PendingApproval") {
+			m.awaitingApproval[itemKey(r.item)] = pendingItemName(r.item)
+			m.log(LogInfo, "%s is waiting for approval", pendingItemName(r.item))
+		}
+	}
+	if len(failed) == 0 || !m.config.Notifications.OnFailure {
+		return nil
+	}
+	return notifyCmd(m.notifier, notify.Event{
+		Kind:    notify.KindFailed,
+		Title:   "PIM activation failed",
+		Message: strings.Join(failed, ", "),
+	})
+}
+
+// activationFailedNotification reports an activation that failed before any item was attempted
+func (m Model) activationFailedNotification(err error) tea.Cmd {
+	if !m.config.Notifications.OnFailure {
+		return nil
+	}
+	return notifyCmd(m.notifier, notify.Event{
+		Kind:    notify.KindFailed,
+		Title:   "PIM activation failed",
+		Message: err.Error(),
+	})
+}
+
+// checkApprovals raises a notification for each request that was waiting for
+// approval and is now active
+func (m *Model) checkApprovals() tea.Cmd {
+	if len(m.awaitingApproval) == 0 {
+		return nil
+	}
+	var approved []string
+	for _, a := range m.activeElevations() {
+		key := itemKey(a.item)
+		if name, ok := m.awaitingApproval[key]; ok {
+			approved = append(approved, name)
+			delete(m.awaitingApproval, key)
+			m.log(LogInfo, "%s was approved and is now active", name)
+		}
+	}
+	if len(approved) == 0 || !m.config.Notifications.OnApproval {
+		return nil
+	}
+	return notifyCmd(m.notifier, notify.Event{
+		Kind:    notify.KindApproved,
+		Title:   "PIM request approved",
+		Message: strings.Join(approved, ", ") + " now active",
+	})
+}
@@ -0,0 +1,315 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteMaxResults caps how many ranked results the palette keeps
+const paletteMaxResults = 50
+
+// paletteKind groups palette entries; it also orders results of equal score
+type paletteKind int
+
+const (
+	paletteAction paletteKind = iota
+	paletteRole
+	paletteGroup
+	paletteSubRole
+)
+
+func (k paletteKind) String() string {
+	switch k {
+	case paletteRole:
+		return "role"
+	case paletteGroup:
+		return "group"
+	case paletteSubRole:
+		return "azure role"
+	default:
+		return "action"
+	}
+}
+
+// paletteEntry is one command palette result: an action to run or an item to jump to
+type paletteEntry struct {
+	kind   paletteKind
+	title  string
+	detail string // Shown dimmed after the title
+	hint   string // Key that runs the action outside the palette
+	doc    searchDoc
+	run    func(m *Model) tea.Cmd
+}
+
+func newPaletteInput() textinput.Model {
+	pi := textinput.New()
+	pi.Placeholder = "Type a role, group, subscription or action..."
+	pi.CharLimit = 100
+	pi.Width = 50
+	return pi
+}
+
+func (m *Model) openPalette() tea.Cmd {
+	m.paletteInput.SetValue("")
+	m.paletteInput.Focus()
+	m.paletteCursor = 0
+	m.paletteResults = rankPalette(m.paletteEntries(), "")
+	m.state = StatePalette
+	return textinput.Blink
+}
+
+func (m Model) handlePaletteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := m.keys.Palette
+	switch {
+	case key.Matches(msg, keys.Cancel):
+		m.paletteInput.Blur()
+		m.state = StateNormal
+		return m, nil
+	case key.Matches(msg, keys.Up):
+		m.paletteCursor = clampCursor(m.paletteCursor, -1, len(m.paletteResults))
+		return m, nil
+	case key.Matches(msg, keys.Down):
+		m.paletteCursor = clampCursor(m.paletteCursor, 1, len(m.paletteResults))
+		return m, nil
+	case key.Matches(msg, keys.Submit):
+		m.paletteInput.Blur()
+		m.state = StateNormal
+		if m.paletteCursor >= len(m.paletteResults) {
+			return m, nil
+		}
+		cmd := m.paletteResults[m.paletteCursor].run(&m)
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	m.paletteResults = rankPalette(m.paletteEntries(), m.paletteInput.Value())
+	m.paletteCursor = 0
+	return m, cmd
+}
+
+// paletteEntries lists every action followed by every role, group and
+// subscription role currently loaded.
+//
+// There are no profile entries and no "switch tenant" action: pim-tui has no
+// profiles and always works in the tenant of the Azure CLI or browser sign-in,
+// so there is nothing for either to select yet. They belong here once those
+// features exist.
+func (m Model) paletteEntries() []paletteEntry {
+	return append(m.paletteActions(), m.itemEntries()...)
+}
+
+// itemEntries lists every role, group and subscription role as an entry that
+// jumps to it
+func (m Model) itemEntries() []paletteEntry {
+	var entries []paletteEntry
+	for _, r := range m.roles {
+		entries = append(entries, paletteEntry{
+			kind:   paletteRole,
+			title:  r.DisplayName,
+			detail: r.Status.String(),
+			doc:    roleSearchDoc(r),
+			run:    jumpTo(itemKey(r)),
+		})
+	}
+	for _, g := range m.groups {
+		entries = append(entries, paletteEntry{
+			kind:   paletteGroup,
+			title:  g.DisplayName,
+			detail: g.RoleDefinitionID + " · " + g.Status.String(),
+			doc:    groupSearchDoc(g),
+			run:    jumpTo(itemKey(g)),
+		})
+	}
+	for _, sub := range m.lighthouse {
+		for _, role := range sub.EligibleRoles {
+			item := SubscriptionRoleActivation{SubscriptionID: sub.ID, SubscriptionName: sub.DisplayName, Role: role}
+			detail := "on " + sub.DisplayName
+			if sub.TenantName != "" {
+				detail += " (" + sub.TenantName + ")"
+			}
+			entries = append(entries, paletteEntry{
+				kind:   paletteSubRole,
+				title:  role.RoleDefinitionName,
+				detail: detail,
+				doc:    subRoleSearchDoc(sub, role),
+				run:    jumpTo(itemKey(item)),
+			})
+		}
+	}
+	return entries
+}
+
+// paletteActions mirrors the main view key bindings, so disabled bindings still
+// leave their action reachable from the palette
+func (m Model) paletteActions() []paletteEntry {
+	keys := m.keys.Normal
+	action := func(b key.Binding, run func(m *Model) tea.Cmd) paletteEntry {
+		title := capitalize(b.Help().Desc)
+		e := paletteEntry{kind: paletteAction, title: title, doc: searchDoc{kind: "action", name: title}, run: run}
+		if b.Enabled() {
+			e.hint = b.Help().Key
+		}
+		return e
+	}
+	connected := func(initiate func(m *Model) (tea.Model, tea.Cmd)) func(m *Model) tea.Cmd {
+		return func(m *Model) tea.Cmd {
+			if !m.requireClient() {
+				return nil
+			}
+			_, cmd := initiate(m)
+			return cmd
+		}
+	}
+
+	actions := []paletteEntry{
+		action(keys.Activate, connected((*Model).initiateActivation)),
+		action(keys.Deactivate, connected((*Model).initiateDeactivation)),
+		action(keys.Extend, connected((*Model).initiateExtension)),
+		action(keys.Renew, connected((*Model).initiateRenewal)),
+		action(keys.AutoExtend, connected((*Model).initiateAutoExtend)),
+		action(keys.Refresh, (*Model).refresh),
+		action(keys.RefreshNames, (*Model).refreshNames),
+		action(keys.Search, (*Model).openSearch),
+		action(keys.Sort, func(m *Model) tea.Cmd {
+			m.cycleSort()
+			return nil
+		}),
+		action(keys.Filter, func(m *Model) tea.Cmd {
+			m.cycleStatusFilter()
+			return nil
+		}),
+		action(keys.Favorite, (*Model).toggleFavorite),
+		action(keys.History, (*Model).openHistory),
+		action(keys.Timeline, (*Model).openTimeline),
+		action(keys.Stats, (*Model).openStats),
+		action(keys.Export, func(m *Model) tea.Cmd {
+			m.openExportDialog(0, 0)
+			return nil
+		}),
+		action(keys.ToggleStanding, (*Model).toggleStanding),
+		action(keys.AutoRefresh, func(m *Model) tea.Cmd {
+			m.toggleAutoRefresh()
+			return nil
+		}),
+		action(keys.LogLevel, func(m *Model) tea.Cmd {
+			m.cycleLogLevel()
+			return nil
+		}),
+		action(keys.CopyLogs, func(m *Model) tea.Cmd {
+			m.copyLogs()
+			return nil
+		}),
+		action(keys.Help, func(m *Model) tea.Cmd {
+			m.state = StateHelp
+			return nil
+		}),
+	}
+
+	for i, preset := range m.config.DurationPresets {
+		if i >= len(keys.Durations) {
+			break
+		}
+		e := action(keys.Durations[i], func(m *Model) tea.Cmd {
+			m.setDurationByIndex(i)
+			return nil
+		})
+		e.title = fmt.Sprintf("Set duration to %dh", preset)
+		e.doc.name = e.title
+		actions = append(actions, e)
+	}
+
+	return append(actions, action(keys.Quit, (*Model).quit))
+}
+
+// jumpTo returns an action that switches to the tab holding the item with the
+// given itemKey and moves the cursor onto it. An active search that hides the
+// item is cleared.
+func jumpTo(target string) func(m *Model) tea.Cmd {
+	return func(m *Model) tea.Cmd {
+		return m.jumpToItem(target)
+	}
+}
+
+func (m *Model) jumpToItem(target string) tea.Cmd {
+	q := m.search()
+	m.subRoleFocus = false
+
+	for i, r := range m.roles {
+		if itemKey(r) == target {
+			m.revealSearch(q.matches(roleSearchDoc(r)))
+			m.activeTab = TabRoles
+			m.rolesCursor = i
+			m.moveCursor(0)
+			return nil
+		}
+	}
+	for i, g := range m.groups {
+		if itemKey(g) == target {
+			m.revealSearch(q.matches(groupSearchDoc(g)))
+			m.activeTab = TabGroups
+			m.groupsCursor = i
+			m.moveCursor(0)
+			return nil
+		}
+	}
+	for i, sub := range m.lighthouse {
+		for j, role := range sub.EligibleRoles {
+			item := SubscriptionRoleActivation{SubscriptionID: sub.ID, SubscriptionName: sub.DisplayName, Role: role}
+			if itemKey(item) == target {
+				m.revealSearch(subscriptionMatches(q, sub))
+				m.activeTab = TabSubscriptions
+				m.lightCursor = i
+				m.moveCursor(0)
+				m.subRoleFocus = true
+				m.subRoleCursor = j
+				return nil
+			}
+		}
+	}
+	m.log(LogInfo, "Item is no longer listed")
+	return nil
+}
+
+// revealSearch clears the applied search unless the item being jumped to is visible
+func (m *Model) revealSearch(visible bool) {
+	if visible {
+		return
+	}
+	m.searchActive = false
+	m.searchQuery = ""
+	m.searchInput.SetValue("")
+}
+
+// rankPalette returns the entries matching query, best first. An empty query
+// keeps every entry in its original order.
+func rankPalette(entries []paletteEntry, query string) []paletteEntry {
+	q := parseSearch(query)
+	type scored struct {
+		entry paletteEntry
+		score int
+	}
+	var matches []scored
+	for _, e := range entries {
+		if score, ok := q.match(e.doc); ok {
+			matches = append(matches, scored{e, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].entry.kind < matches[j].entry.kind
+	})
+
+	results := make([]paletteEntry, 0, min(len(matches), paletteMaxResults))
+	for i := 0; i < len(matches) && i < paletteMaxResults; i++ {
+		results = append(results, matches[i].entry)
+	}
+	return results
+}
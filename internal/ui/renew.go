@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+)
+
+type renewalDoneMsg struct {
+	results []bulkResult
+}
+
+// eligibleUntil returns when the eligibility behind a pending item ends, nil if permanent
+func eligibleUntil(item interface{}) *time.Time {
+	switch v := item.(type) {
+	case azure.Role:
+		return v.EligibleUntil
+	case azure.Group:
+		return v.EligibleUntil
+	case SubscriptionRoleActivation:
+		return v.Role.EligibleUntil
+	}
+	return nil
+}
+
+// eligibilityWarnWindow is how far ahead eligibility end dates are highlighted
+func (m Model) eligibilityWarnWindow() time.Duration {
+	return time.Duration(m.config.EligibilityWarnDays) * 24 * time.Hour
+}
+
+// renewalDuration is the eligibility length requested by renewals
+func (m Model) renewalDuration() time.Duration {
+	days := m.config.RenewalDays
+	if days <= 0 {
+		days = 365
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// renewalCandidates returns the selected items of the active tab, or the item
+// under the cursor when nothing is selected. On the Active now tab it falls back
+// to every listed item instead.
+func (m Model) renewalCandidates() []interface{} {
+	var items []interface{}
+	switch m.activeTab {
+	case TabActive:
+		for _, e := range m.dashboardTargets() {
+			items = append(items, e.item)
+		}
+	case TabRoles:
+		for _, role := range m.roles {
+			if m.selectedRoles[itemKey(role)] {
+				items = append(items, role)
+			}
+		}
+		if len(items) == 0 && m.rolesCursor < len(m.roles) {
+			items = append(items, m.roles[m.rolesCursor])
+		}
+	case TabGroups:
+		for _, group := range m.groups {
+			if m.selectedGroups[itemKey(group)] {
+				items = append(items, group)
+			}
+		}
+		if len(items) == 0 && m.groupsCursor < len(m.groups) {
+			items = append(items, m.groups[m.groupsCursor])
+		}
+	case TabSubscriptions:
+		for _, sub := range m.lighthouse {
+			for _, role := range sub.EligibleRoles {
+				if m.selectedSubRoles[sub.ID][subRoleKey(sub, role)] {
+					items = append(items, SubscriptionRoleActivation{
+						SubscriptionID:   sub.ID,
+						SubscriptionName: sub.DisplayName,
+						Role:             role,
+					})
+				}
+			}
+		}
+		if len(items) == 0 && m.subRoleFocus && m.lightCursor < len(m.lighthouse) {
+			sub := m.lighthouse[m.lightCursor]
+			if m.subRoleCursor < len(sub.EligibleRoles) {
+				items = append(items, SubscriptionRoleActivation{
+					SubscriptionID:   sub.ID,
+					SubscriptionName: sub.DisplayName,
+					Role:             sub.EligibleRoles[m.subRoleCursor],
+				})
+			}
+		}
+	}
+	return items
+}
+
+// initiateRenewal opens the renewal dialog for eligibilities that have an end date
+func (m *Model) initiateRenewal() (tea.Model, tea.Cmd) {
+	m.pendingRenewals = nil
+	for _, item := range m.renewalCandidates() {
+		if eligibleUntil(item) != nil {
+			m.pendingRenewals = append(m.pendingRenewals, item)
+		}
+	}
+	if len(m.pendingRenewals) == 0 {
+		m.log(LogInfo, "No time-bound eligibilities selected - permanent ones need no renewal")
+		return m, nil
+	}
+
+	m.justificationInput.SetValue("")
+	m.justificationInput.Focus()
+	m.state = StateRenew
+	return m, nil
+}
+
+func (m Model) handleRenewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Input.Submit):
+		if _, err := validateJustification(m.justificationInput.Value()); err != nil {
+			m.log(LogError, "%v", err)
+			return m, nil
+		}
+		return m.startRenewal()
+	case key.Matches(msg, m.keys.Input.Cancel):
+		m.state = StateNormal
+		m.pendingRenewals = nil
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.justificationInput, cmd = m.justificationInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) startRenewal() (tea.Model, tea.Cmd) {
+	m.state = StateRenewing
+	client := m.client
+	pending := m.pendingRenewals
+	justification := m.justificationInput.Value()
+	duration := m.renewalDuration()
+	limit := m.config.ActivationConcurrency
+
+	return m, func() tea.Msg {
+		results := runBulk(context.Background(), pending, limit, func(ctx context.Context, item interface{}) (azure.RequestResult, error) {
+			switch v := item.(type) {
+			case azure.Role:
+				return client.RenewRole(ctx, v.RoleDefinitionID, justification, duration)
+			case azure.Group:
+				return client.RenewGroup(ctx, v.ID, v.RoleDefinitionID, justification, duration)
+			case SubscriptionRoleActivation:
+				return client.RenewAzureRole(ctx, v.Role.Scope, v.Role.RoleDefinitionID, justification, duration)
+			}
+			return azure.RequestResult{}, fmt.Errorf("unsupported item type %T", item)
+		})
+		return renewalDoneMsg{results: results}
+	}
+}
@@ -0,0 +1,427 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+)
+
+// searchFieldAliases maps the field prefixes accepted in search queries, as in
+// "tenant:contoso role:owner status:active", to searchDoc fields
+var searchFieldAliases = map[string]string{
+	"name":         "name",
+	"role":         "role",
+	"group":        "group",
+	"sub":          "sub",
+	"subscription": "sub",
+	"tenant":       "tenant",
+	"scope":        "scope",
+	"status":       "status",
+	"type":         "type",
+	"kind":         "type",
+}
+
+// searchTerm is one whitespace-separated part of a query
+type searchTerm struct {
+	field string // searchDoc field the term is limited to; empty matches any
+	value string
+}
+
+// searchQuery is a parsed search; an empty query matches everything
+type searchQuery []searchTerm
+
+// parseSearch splits a query into terms. Tokens with a known "field:" prefix
+// only match that field; unknown prefixes are searched for literally.
+func parseSearch(s string) searchQuery {
+	var q searchQuery
+	for _, token := range strings.Fields(s) {
+		if field, value, ok := strings.Cut(token, ":"); ok {
+			if name, known := searchFieldAliases[strings.ToLower(field)]; known {
+				// A prefix still being typed does not filter yet
+				if value != "" {
+					q = append(q, searchTerm{field: name, value: value})
+				}
+				continue
+			}
+		}
+		q = append(q, searchTerm{value: token})
+	}
+	return q
+}
+
+// searchDoc is the searchable text of one role, group, subscription or action
+type searchDoc struct {
+	kind   string // "role", "group", "azure role", "subscription" or "action"
+	name   string
+	role   string // Role name, or member/owner for groups
+	group  string
+	sub    string // Subscription display name
+	tenant string
+	scope  string
+	status string // Space separated status words, see statusWords
+}
+
+func (d searchDoc) field(name string) string {
+	switch name {
+	case "name":
+		return d.name
+	case "role":
+		return d.role
+	case "group":
+		return d.group
+	case "sub":
+		return d.sub
+	case "tenant":
+		return d.tenant
+	case "scope":
+		return d.scope
+	case "status":
+		return d.status
+	case "type":
+		return d.kind
+	}
+	return ""
+}
+
+// statusWords describes a status for status: terms; expiring items are active too
+func statusWords(s azure.ActivationStatus, standing bool) string {
+	words := strings.ToLower(s.String())
+	if s == azure.StatusExpiringSoon {
+		words = "expiring active"
+	}
+	if standing {
+		words += " standing"
+	}
+	return words
+}
+
+func roleSearchDoc(r azure.Role) searchDoc {
+	return searchDoc{kind: "role", name: r.DisplayName, role: r.DisplayName, scope: r.DirectoryScopeID, status: statusWords(r.Status, r.Standing)}
+}
+
+func groupSearchDoc(g azure.Group) searchDoc {
+	return searchDoc{kind: "group", name: g.DisplayName, group: g.DisplayName, role: g.RoleDefinitionID, status: statusWords(g.Status, g.Standing)}
+}
+
+func subscriptionSearchDoc(sub azure.LighthouseSubscription) searchDoc {
+	return searchDoc{kind: "subscription", name: sub.DisplayName, sub: sub.DisplayName, tenant: sub.TenantName, scope: "/subscriptions/" + sub.ID, status: statusWords(sub.Status, false)}
+}
+
+func subRoleSearchDoc(sub azure.LighthouseSubscription, role azure.EligibleAzureRole) searchDoc {
+	return searchDoc{
+		kind:   "azure role",
+		name:   role.RoleDefinitionName,
+		role:   role.RoleDefinitionName,
+		sub:    sub.DisplayName,
+		tenant: sub.TenantName,
+		scope:  role.Scope,
+		status: statusWords(role.Status, role.Standing),
+	}
+}
+
+// match scores d against every term of the query. Free terms fuzzy match the
+// name, or at half the score the role, group, subscription or tenant; status:
+// and type: terms match the start of a word.
+func (q searchQuery) match(d searchDoc) (int, bool) {
+	total := 0
+	for _, t := range q {
+		var score int
+		var ok bool
+		switch t.field {
+		case "":
+			score, ok = fuzzyScore(t.value, d.name)
+			for _, other := range []string{d.role, d.group, d.sub, d.tenant} {
+				if s, matched := fuzzyScore(t.value, other); matched && (!ok || s/2 > score) {
+					score, ok = s/2, true
+				}
+			}
+		case "status", "type":
+			ok = hasWordPrefix(d.field(t.field), t.value)
+		default:
+			score, ok = fuzzyScore(t.value, d.field(t.field))
+		}
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+func (q searchQuery) matches(d searchDoc) bool {
+	_, ok := q.match(d)
+	return ok
+}
+
+// hasWordPrefix reports whether any word of s starts with prefix, ignoring case
+func hasWordPrefix(s, prefix string) bool {
+	prefix = strings.ToLower(prefix)
+	for _, word := range strings.Fields(strings.ToLower(s)) {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Model) openSearch() tea.Cmd {
+	m.state = StateSearch
+	m.searchInput.SetValue(m.searchQuery)
+	m.searchInput.Focus()
+	m.updateSearchResults()
+	return textinput.Blink
+}
+
+// handleSearchKey edits the query, applying it as the filter on every tab as
+// the user types. Submit also jumps to the chosen result in the combined list.
+func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := m.keys.Search
+	switch {
+	case key.Matches(msg, keys.Up):
+		m.searchCursor = clampCursor(m.searchCursor, -1, len(m.searchResults))
+		return m, nil
+	case key.Matches(msg, keys.Down):
+		m.searchCursor = clampCursor(m.searchCursor, 1, len(m.searchResults))
+		return m, nil
+	case key.Matches(msg, keys.Submit, keys.Cancel):
+		m.state = StateNormal
+		m.searchInput.Blur()
+		m.applySearch()
+		m.revealCursors()
+		if key.Matches(msg, keys.Submit) && m.searchCursor < len(m.searchResults) {
+			return m, m.searchResults[m.searchCursor].run(&m)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	m.updateSearchResults()
+	return m, cmd
+}
+
+// updateSearchResults applies the query being typed and ranks the items it matches
+func (m *Model) updateSearchResults() {
+	m.applySearch()
+	m.searchCursor = 0
+	m.searchResults = nil
+	if m.searchActive {
+		m.searchResults = rankPalette(m.itemEntries(), m.searchQuery)
+	}
+}
+
+// applySearch filters the tabs by the query in the search input
+func (m *Model) applySearch() {
+	m.searchQuery = m.searchInput.Value()
+	m.searchActive = m.searchQuery != ""
+}
+
+// search returns the applied search, empty when no filter is active
+func (m Model) search() searchQuery {
+	if !m.searchActive {
+		return nil
+	}
+	return parseSearch(m.searchQuery)
+}
+
+// visibleRoleIndices returns the indices of the roles matching the search and
+// status filter, in the tab's sort order
+func (m Model) visibleRoleIndices() []int {
+	q := m.search()
+	lastUsed := m.lastUsedLookup(TabRoles)
+	items := make([]listItem, 0, len(m.roles))
+	for i, r := range m.roles {
+		if doc := roleSearchDoc(r); q.matches(doc) {
+			items = append(items, listItem{index: i, doc: doc, status: r.Status, expiresAt: r.ExpiresAt, lastUsed: lastUsed("role", r.DisplayName), favorite: m.favorites[itemKey(r)]})
+		}
+	}
+	return m.listViews[TabRoles].arrange(items)
+}
+
+// visibleGroupIndices returns the indices of the groups matching the search and
+// status filter, in the tab's sort order
+func (m Model) visibleGroupIndices() []int {
+	q := m.search()
+	lastUsed := m.lastUsedLookup(TabGroups)
+	items := make([]listItem, 0, len(m.groups))
+	for i, g := range m.groups {
+		if doc := groupSearchDoc(g); q.matches(doc) {
+			items = append(items, listItem{index: i, doc: doc, status: g.Status, expiresAt: g.ExpiresAt, lastUsed: lastUsed("group", g.DisplayName), favorite: m.favorites[itemKey(g)]})
+		}
+	}
+	return m.listViews[TabGroups].arrange(items)
+}
+
+// subscriptionMatches reports whether the subscription itself or one of its
+// eligible roles matches q
+func subscriptionMatches(q searchQuery, sub azure.LighthouseSubscription) bool {
+	if q.matches(subscriptionSearchDoc(sub)) {
+		return true
+	}
+	for _, role := range sub.EligibleRoles {
+		if q.matches(subRoleSearchDoc(sub, role)) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchCounts returns how many roles, groups and subscriptions the query matches
+func (m Model) searchCounts(q searchQuery) (roles, groups, subs int) {
+	for _, r := range m.roles {
+		if q.matches(roleSearchDoc(r)) {
+			roles++
+		}
+	}
+	for _, g := range m.groups {
+		if q.matches(groupSearchDoc(g)) {
+			groups++
+		}
+	}
+	for _, sub := range m.lighthouse {
+		if subscriptionMatches(q, sub) {
+			subs++
+		}
+	}
+	return roles, groups, subs
+}
+
+// revealCursors moves each tab's cursor onto its first visible item when the
+// search hid the item it was on
+func (m *Model) revealCursors() {
+	reveal := func(cursor *int, visible []int) {
+		for _, i := range visible {
+			if i == *cursor {
+				return
+			}
+		}
+		if len(visible) > 0 {
+			*cursor = visible[0]
+		}
+	}
+	reveal(&m.rolesCursor, m.visibleRoleIndices())
+	reveal(&m.groupsCursor, m.visibleGroupIndices())
+	before := m.lightCursor
+	reveal(&m.lightCursor, m.getVisibleSubscriptionIndices())
+	if m.lightCursor != before {
+		m.subRoleCursor = 0
+		m.subRoleFocus = false
+	}
+}
+
+// fuzzyScore reports whether the runes of query appear in order in target,
+// ignoring case, and scores the best such match: whole substrings, consecutive
+// runs and matches at the start of words score higher; gaps and late starts lower.
+func fuzzyScore(query, target string) (int, bool) {
+	score, _, ok := fuzzyMatch(query, target)
+	return score, ok
+}
+
+// fuzzyMatch is fuzzyScore that also returns the matched rune positions in target
+func fuzzyMatch(query, target string) (int, []int, bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(target))
+	if len(q) == 0 {
+		return 0, nil, true
+	}
+
+	best, found := 0, false
+	var positions []int
+	for start := range t {
+		if t[start] != q[0] {
+			continue
+		}
+		score, pos, ok := fuzzyMatchFrom(q, t, start)
+		if ok && (!found || score > best) {
+			best, positions, found = score, pos, true
+		}
+	}
+	if !found {
+		return 0, nil, false
+	}
+
+	if idx := strings.Index(string(t), string(q)); idx >= 0 {
+		best += 3 * len(q)
+		if idx == 0 {
+			best += 10
+		}
+		// Prefer highlighting the contiguous match
+		start := len([]rune(string(t)[:idx]))
+		positions = positions[:0]
+		for i := range q {
+			positions = append(positions, start+i)
+		}
+	}
+	return best, positions, true
+}
+
+// fuzzyMatchFrom greedily matches q against t starting at t[start]
+func fuzzyMatchFrom(q, t []rune, start int) (int, []int, bool) {
+	score := -min(start, 10) / 2
+	positions := make([]int, 0, len(q))
+	prev := start - 1
+	for ti := start; ti < len(t) && len(positions) < len(q); ti++ {
+		if t[ti] != q[len(positions)] {
+			continue
+		}
+		score++
+		if ti == prev+1 {
+			score += 4
+		} else {
+			score -= min(ti-prev-1, 3)
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 6
+		}
+		positions = append(positions, ti)
+		prev = ti
+	}
+	return score, positions, len(positions) == len(q)
+}
+
+// highlightSearchMatch highlights the characters of text matched by the query's
+// free and name: terms
+func highlightSearchMatch(text, query string) string {
+	matched := make(map[int]bool)
+	for _, t := range parseSearch(query) {
+		if t.field != "" && t.field != "name" {
+			continue
+		}
+		if _, positions, ok := fuzzyMatch(t.value, text); ok {
+			for _, p := range positions {
+				matched[p] = true
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return text
+	}
+
+	highlightStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#000000")).
+		Background(colorExpiring).
+		Bold(true)
+
+	// Style runs of matched runes together
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && matched[j] == matched[i] {
+			j++
+		}
+		if matched[i] {
+			b.WriteString(highlightStyle.Render(string(runes[i:j])))
+		} else {
+			b.WriteString(string(runes[i:j]))
+		}
+		i = j
+	}
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+)
+
+// Selections are kept by item key and cursors are re-resolved by item key
+// whenever a list is reloaded, so a refresh that reorders or shrinks a list
+// never moves them onto different items.
+
+// itemKey identifies an item across refreshes. A role can be assigned
+// permanently and be eligible at the same scope, so standing assignments get a
+// key of their own.
+func itemKey(item interface{}) string {
+	var key string
+	var standing bool
+	switch v := item.(type) {
+	case azure.Role:
+		key, standing = "role|"+v.DirectoryScopeID+"|"+v.RoleDefinitionID, v.Standing
+	case azure.Group:
+		key, standing = "group|"+v.ID+"|"+v.RoleDefinitionID, v.Standing
+	case SubscriptionRoleActivation:
+		key, standing = "azure-role|"+v.Role.Scope+"|"+v.Role.RoleDefinitionID, v.Role.Standing
+	default:
+		return ""
+	}
+	if standing {
+		key += "|standing"
+	}
+	return key
+}
+
+// subRoleKey is the itemKey of one eligible role of a subscription
+func subRoleKey(sub azure.LighthouseSubscription, role azure.EligibleAzureRole) string {
+	return itemKey(SubscriptionRoleActivation{SubscriptionID: sub.ID, Role: role})
+}
+
+// cursorKeys is the item under each cursor, captured before a list is replaced
+type cursorKeys struct {
+	role, group, sub, subRole, active string
+}
+
+func (m Model) cursorKeys() cursorKeys {
+	var k cursorKeys
+	if m.rolesCursor < len(m.roles) {
+		k.role = itemKey(m.roles[m.rolesCursor])
+	}
+	if m.groupsCursor < len(m.groups) {
+		k.group = itemKey(m.groups[m.groupsCursor])
+	}
+	if m.lightCursor < len(m.lighthouse) {
+		sub := m.lighthouse[m.lightCursor]
+		k.sub = subscriptionKey(sub)
+		if m.subRoleCursor < len(sub.EligibleRoles) {
+			k.subRole = subRoleKey(sub, sub.EligibleRoles[m.subRoleCursor])
+		}
+	}
+	if entries := m.dashboardEntries(); m.activeCursor < len(entries) {
+		k.active = entries[m.activeCursor].key()
+	}
+	return k
+}
+
+// keepCursors moves each cursor back onto the item it was on before the lists
+// were reloaded. A cursor whose item is gone keeps its position in the list.
+func (m *Model) keepCursors(k cursorKeys) {
+	find := func(key string, count int, keyAt func(int) string, cursor *int) bool {
+		if key == "" {
+			return false
+		}
+		for i := 0; i < count; i++ {
+			if keyAt(i) == key {
+				*cursor = i
+				return true
+			}
+		}
+		*cursor = max(min(*cursor, count-1), 0)
+		return false
+	}
+
+	find(k.role, len(m.roles), func(i int) string { return itemKey(m.roles[i]) }, &m.rolesCursor)
+	find(k.group, len(m.groups), func(i int) string { return itemKey(m.groups[i]) }, &m.groupsCursor)
+	if find(k.sub, len(m.lighthouse), func(i int) string { return subscriptionKey(m.lighthouse[i]) }, &m.lightCursor) {
+		sub := m.lighthouse[m.lightCursor]
+		find(k.subRole, len(sub.EligibleRoles), func(i int) string { return subRoleKey(sub, sub.EligibleRoles[i]) }, &m.subRoleCursor)
+	} else if k.sub != "" {
+		// The subscription is gone; start at the top of whichever took its place
+		m.subRoleCursor, m.subRoleFocus = 0, false
+	}
+	entries := m.dashboardEntries()
+	find(k.active, len(entries), func(i int) string { return entries[i].key() }, &m.activeCursor)
+}
+
+// toggleKey selects key, or deselects it when already selected
+func toggleKey(selected map[string]bool, key string) {
+	if selected[key] {
+		delete(selected, key)
+	} else {
+		selected[key] = true
+	}
+}
+
+// pruneSelection drops the selected keys missing from present and returns how many went
+func pruneSelection(selected, present map[string]bool) int {
+	dropped := 0
+	for key := range selected {
+		if !present[key] {
+			delete(selected, key)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// reconcileSelections drops selections whose items are no longer listed
+func (m *Model) reconcileSelections() {
+	present := make(map[string]bool)
+	for _, r := range m.roles {
+		present[itemKey(r)] = true
+	}
+	for _, g := range m.groups {
+		present[itemKey(g)] = true
+	}
+	dropped := pruneSelection(m.selectedRoles, present) + pruneSelection(m.selectedGroups, present)
+
+	subs := make(map[string]azure.LighthouseSubscription, len(m.lighthouse))
+	for _, sub := range m.lighthouse {
+		subs[sub.ID] = sub
+		present[subscriptionKey(sub)] = true
+	}
+	dropped += pruneSelection(m.selectedLight, present)
+	for subID, selected := range m.selectedSubRoles {
+		roles := make(map[string]bool)
+		if sub, ok := subs[subID]; ok {
+			for _, role := range sub.EligibleRoles {
+				roles[subRoleKey(sub, role)] = true
+			}
+		}
+		dropped += pruneSelection(selected, roles)
+		if len(selected) == 0 {
+			delete(m.selectedSubRoles, subID)
+		}
+	}
+
+	if dropped == 1 {
+		m.log(LogInfo, "Deselected 1 item that is no longer eligible")
+	} else if dropped > 1 {
+		m.log(LogInfo, "Deselected %d items that are no longer eligible", dropped)
+	}
+}
@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/seb07-cloud/pim-tui/internal/fsutil"
+	"github.com/seb07-cloud/pim-tui/internal/history"
+)
+
+// tabNames names the tabs in the session file
+var tabNames = map[Tab]string{
+	TabRoles:         "roles",
+	TabGroups:        "groups",
+	TabSubscriptions: "subscriptions",
+	TabActive:        "active",
+}
+
+// sessionState is the UI state saved on quit and restored on the next launch.
+// Cursors are saved as item keys, not indices, since the order of the lists
+// may differ between loads.
+type sessionState struct {
+	SavedAt   time.Time              `json:"saved_at"`
+	ActiveTab string                 `json:"active_tab"`
+	Cursors   map[string]string      `json:"cursors,omitempty"`  // Tab name -> itemKey (subscriptionKey on the subscriptions tab)
+	SubRole   string                 `json:"sub_role,omitempty"` // itemKey of the focused subscription role
+	Search    string                 `json:"search,omitempty"`
+	Duration  int                    `json:"duration_hours,omitempty"`
+	LogLevel  string                 `json:"log_level,omitempty"`
+	Views     map[string]sessionView `json:"views,omitempty"` // Tab name -> sort mode and status filter
+}
+
+type sessionView struct {
+	Sort   string `json:"sort,omitempty"`
+	Filter string `json:"filter,omitempty"`
+}
+
+type sessionLoadedMsg struct {
+	state *sessionState
+	err   error
+}
+
+type sessionSavedMsg struct{ err error }
+
+func sessionPath() (string, error) {
+	dir, err := history.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "session.json"), nil
+}
+
+// loadSession reads the session file; a missing file yields no state
+func loadSession(path string) (*sessionState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s sessionState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func saveSession(path string, s sessionState) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, data)
+}
+
+func loadSessionCmd(path string) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		state, err := loadSession(path)
+		return sessionLoadedMsg{state: state, err: err}
+	}
+}
+
+// quit saves the session and exits. Until the saved session has been read
+// there is nothing new worth keeping, so an early quit leaves the file alone.
+func (m *Model) quit() tea.Cmd {
+	if m.sessionPath == "" || !m.sessionLoaded {
+		return tea.Quit
+	}
+	path, state := m.sessionPath, m.sessionState()
+	return tea.Sequence(func() tea.Msg {
+		return sessionSavedMsg{saveSession(path, state)}
+	}, tea.Quit)
+}
+
+// sessionState captures the UI state to restore on the next launch
+func (m Model) sessionState() sessionState {
+	s := sessionState{
+		SavedAt:   time.Now(),
+		ActiveTab: tabNames[m.activeTab],
+		Cursors:   make(map[string]string),
+		Duration:  int(m.duration.Hours()),
+		LogLevel:  strings.ToLower(m.logLevel.String()),
+		Views:     make(map[string]sessionView),
+	}
+	if m.searchActive {
+		s.Search = m.searchQuery
+	}
+
+	// Lists that never loaded keep the cursor restored from the last session
+	for tab, key := range m.pendingCursors {
+		s.Cursors[tabNames[tab]] = key
+	}
+	if m.rolesCursor < len(m.roles) {
+		s.Cursors[tabNames[TabRoles]] = itemKey(m.roles[m.rolesCursor])
+	}
+	if m.groupsCursor < len(m.groups) {
+		s.Cursors[tabNames[TabGroups]] = itemKey(m.groups[m.groupsCursor])
+	}
+	if m.lightCursor < len(m.lighthouse) {
+		sub := m.lighthouse[m.lightCursor]
+		s.Cursors[tabNames[TabSubscriptions]] = subscriptionKey(sub)
+		if m.subRoleFocus && m.subRoleCursor < len(sub.EligibleRoles) {
+			s.SubRole = itemKey(SubscriptionRoleActivation{SubscriptionID: sub.ID, Role: sub.EligibleRoles[m.subRoleCursor]})
+		}
+	} else {
+		s.SubRole = m.pendingSubRole
+	}
+	if entries := m.dashboardEntries(); m.activeCursor < len(entries) {
+		s.Cursors[tabNames[TabActive]] = entries[m.activeCursor].key()
+	}
+
+	for tab, v := range m.listViews {
+		if v != (listView{}) {
+			s.Views[tabNames[Tab(tab)]] = sessionView{Sort: v.sort.String(), Filter: v.filter.String()}
+		}
+	}
+	return s
+}
+
+// applySession restores the state saved by the last session. Cursors move onto
+// their items once the lists load, see restoreCursors.
+func (m *Model) applySession(s *sessionState) {
+	m.sessionLoaded = true
+	if s == nil {
+		return
+	}
+
+	for tab, name := range tabNames {
+		if name == s.ActiveTab {
+			m.activeTab = tab
+		}
+		if key, ok := s.Cursors[name]; ok {
+			m.pendingCursors[tab] = key
+		}
+		if v, ok := s.Views[name]; ok {
+			m.listViews[tab] = listView{sort: parseSortMode(v.Sort), filter: parseStatusFilter(v.Filter)}
+		}
+	}
+	m.pendingSubRole = s.SubRole
+
+	if s.Search != "" {
+		m.searchQuery = s.Search
+		m.searchInput.SetValue(s.Search)
+		m.searchActive = true
+	}
+	for i, preset := range m.config.DurationPresets {
+		if preset == s.Duration {
+			m.durationIndex = i
+			m.duration = time.Duration(preset) * time.Hour
+		}
+	}
+	if s.LogLevel != "" {
+		m.logLevel = parseLogLevel(s.LogLevel)
+	}
+	m.restoreCursors()
+}
+
+// restoreCursors moves each cursor onto the item saved in the session once its
+// list has loaded. Until the list's loader reports, the list may be cached
+// snapshot data that is simply out of date, so a missing item is waited for;
+// afterwards, even when the load failed, the cursor stays put.
+func (m *Model) restoreCursors() {
+	if len(m.pendingCursors) == 0 && m.pendingSubRole == "" {
+		return
+	}
+	resolve := func(tab Tab, count int, fresh bool, keyAt func(int) string, cursor *int) {
+		key, ok := m.pendingCursors[tab]
+		if !ok || count == 0 {
+			return
+		}
+		for i := 0; i < count; i++ {
+			if keyAt(i) == key {
+				*cursor = i
+				delete(m.pendingCursors, tab)
+				return
+			}
+		}
+		if fresh {
+			delete(m.pendingCursors, tab)
+		}
+	}
+
+	resolve(TabRoles, len(m.roles), m.rolesLoaded,
+		func(i int) string { return itemKey(m.roles[i]) }, &m.rolesCursor)
+	resolve(TabGroups, len(m.groups), m.groupsLoaded,
+		func(i int) string { return itemKey(m.groups[i]) }, &m.groupsCursor)
+
+	_, subPending := m.pendingCursors[TabSubscriptions]
+	resolve(TabSubscriptions, len(m.lighthouse), m.lighthouseLoaded,
+		func(i int) string { return subscriptionKey(m.lighthouse[i]) }, &m.lightCursor)
+	if _, still := m.pendingCursors[TabSubscriptions]; subPending && !still && m.lightCursor < len(m.lighthouse) {
+		sub := m.lighthouse[m.lightCursor]
+		for j, role := range sub.EligibleRoles {
+			if itemKey(SubscriptionRoleActivation{SubscriptionID: sub.ID, Role: role}) == m.pendingSubRole {
+				m.subRoleCursor, m.subRoleFocus = j, true
+			}
+		}
+		m.pendingSubRole = ""
+	}
+
+	entries := m.dashboardEntries()
+	resolve(TabActive, len(entries), m.rolesLoaded && m.groupsLoaded && m.lighthouseLoaded,
+		func(i int) string { return entries[i].key() }, &m.activeCursor)
+
+	// Scroll each list to its restored cursor
+	active := m.activeTab
+	for tab := TabRoles; tab < tabCount; tab++ {
+		m.activeTab = tab
+		m.moveCursor(0)
+	}
+	m.activeTab = active
+}
+
+func parseSortMode(s string) sortMode {
+	for mode := sortDefault; mode < sortModeCount; mode++ {
+		if mode.String() == s {
+			return mode
+		}
+	}
+	return sortDefault
+}
+
+func parseStatusFilter(s string) statusFilter {
+	for f := filterAll; f < statusFilterCount; f++ {
+		if f.String() == s {
+			return f
+		}
+	}
+	return filterAll
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/seb07-cloud/pim-tui/internal/azure"
+	"github.com/seb07-cloud/pim-tui/internal/fsutil"
+)
+
+// snapshot is the last fully loaded state, shown on the next launch while the
+// first refresh runs so the user is not stuck on the loading screen
+type snapshot struct {
+	SavedAt    time.Time                      `json:"saved_at"`
+	Tenant     *azure.Tenant                  `json:"tenant"`
+	Roles      []azure.Role                   `json:"roles"`
+	Groups     []azure.Group                  `json:"groups"`
+	Lighthouse []azure.LighthouseSubscription `json:"lighthouse"`
+}
+
+type snapshotLoadedMsg struct{ snap *snapshot }
+
+func snapshotPath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find cache dir: %w", err)
+	}
+	return filepath.Join(cacheDir, "pim-tui", "snapshot.json"), nil
+}
+
+func loadSnapshot(path string) (*snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, err
+	}
+	if snap.Tenant == nil {
+		return nil, fmt.Errorf("snapshot has no tenant")
+	}
+	return &snap, nil
+}
+
+func saveSnapshot(path string, snap snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, data)
+}
+
+// loadSnapshotCmd reads the last known state; a missing snapshot is not an error
+func loadSnapshotCmd() tea.Cmd {
+	return func() tea.Msg {
+		path, err := snapshotPath()
+		if err != nil {
+			return snapshotLoadedMsg{}
+		}
+		snap, err := loadSnapshot(path)
+		if err != nil {
+			return snapshotLoadedMsg{}
+		}
+		return snapshotLoadedMsg{snap}
+	}
+}
+
+// saveSnapshotCmd writes the current data so the next launch can show it instantly.
+// Failures are ignored - the snapshot only speeds up startup.
+func (m Model) saveSnapshotCmd() tea.Cmd {
+	snap := snapshot{
+		SavedAt:    time.Now(),
+		Tenant:     m.tenant,
+		Roles:      m.roles,
+		Groups:     m.groups,
+		Lighthouse: m.lighthouse,
+	}
+	return func() tea.Msg {
+		if path, err := snapshotPath(); err == nil {
+			_ = saveSnapshot(path, snap)
+		}
+		return nil
+	}
+}
+
+// applySnapshot shows snapshot data as stale while the first refresh is still running
+func (m *Model) applySnapshot(snap *snapshot) {
+	if snap == nil || m.state != StateLoading || m.rolesLoaded || m.groupsLoaded || m.lighthouseLoaded {
+		return
+	}
+	// A real tenant may already be known if the snapshot was slow to read
+	if m.tenant != nil && m.tenant.ID != snap.Tenant.ID {
+		return
+	}
+
+	if m.tenant == nil {
+		m.tenant = snap.Tenant
+	}
+	m.roles = snap.Roles
+	m.groups = snap.Groups
+	m.lighthouse = snap.Lighthouse
+	expireSnapshotItems(m.roles, m.groups, m.lighthouse)
+
+	m.snapshotTime = snap.SavedAt
+	m.staleRoles = true
+	m.staleGroups = true
+	m.staleLighthouse = true
+	m.state = StateNormal
+	m.log(LogInfo, "Showing cached data from %s while refreshing", snap.SavedAt.Format("Jan 2 15:04"))
+}
+
+// discardSnapshot drops stale data that belongs to a different tenant
+func (m *Model) discardSnapshot() {
+	if m.staleRoles {
+		m.roles = nil
+	}
+	if m.staleGroups {
+		m.groups = nil
+	}
+	if m.staleLighthouse {
+		m.lighthouse = nil
+	}
+	m.staleRoles, m.staleGroups, m.staleLighthouse = false, false, false
+	m.snapshotTime = time.Time{}
+	m.clearSelections()
+	if m.state == StateNormal {
+		m.state = StateLoading
+	}
+}
+
+// isStale reports whether any displayed data still comes from the snapshot
+func (m Model) isStale() bool {
+	return m.staleRoles || m.staleGroups || m.staleLighthouse
+}
+
+// refreshFailed reports whether some list still shows cached data because its
+// last refresh failed, rather than because it is still loading
+func (m Model) refreshFailed() bool {
+	return (m.staleRoles && m.rolesErr != nil && !m.rolesFetching) ||
+		(m.staleGroups && m.groupsErr != nil && !m.groupsFetching) ||
+		(m.staleLighthouse && m.lighthouseErr != nil && !m.lighthouseFetching)
+}
+
+// expireSnapshotItems recomputes statuses since activations may have ended
+// after the snapshot was written
+func expireSnapshotItems(roles []azure.Role, groups []azure.Group, subs []azure.LighthouseSubscription) {
+	for i := range roles {
+		roles[i].Status = snapshotStatus(roles[i].Status, roles[i].ExpiresAt)
+	}
+	for i := range groups {
+		groups[i].Status = snapshotStatus(groups[i].Status, groups[i].ExpiresAt)
+	}
+	for i := range subs {
+		for j := range subs[i].EligibleRoles {
+			role := &subs[i].EligibleRoles[j]
+			role.Status = snapshotStatus(role.Status, role.ExpiresAt)
+		}
+	}
+}
+
+func snapshotStatus(status azure.ActivationStatus, expiresAt *time.Time) azure.ActivationStatus {
+	if !status.IsActive() || expiresAt == nil {
+		return status
+	}
+	if time.Now().After(*expiresAt) {
+		return azure.StatusInactive
+	}
+	return azure.StatusFromExpiry(expiresAt)
+}
@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// statsRecent and statsWindow are the periods activations are counted over
+	statsRecent = 30 * 24 * time.Hour
+	statsWindow = 90 * 24 * time.Hour
+)
+
+// usageStat summarises how one role, group or Azure role was used
+type usageStat struct {
+	key       string // see timelineKey
+	itemType  string // "role", "group" or "azure-role"
+	name      string
+	recent    int           // Activations in the last 30 days
+	total     int           // Activations in the last 90 days
+	elevated  time.Duration // Time spent elevated in the last 90 days
+	requested time.Duration // Duration requested by those activations
+	lastUsed  time.Time     // Latest activation, extension or renewal; zero if none
+	eligible  bool          // Still in the current eligibility inventory
+	standing  bool
+}
+
+// unused reports whether the item is eligible but was not activated in the
+// whole window, making it a candidate for removal
+func (s usageStat) unused() bool {
+	return s.eligible && !s.standing && s.total == 0
+}
+
+// averageElevated is how long an activation lasted on average
+func (s usageStat) averageElevated() time.Duration {
+	if s.total == 0 {
+		return 0
+	}
+	return s.elevated / time.Duration(s.total)
+}
+
+// averageRequested is the duration an activation asked for on average
+func (s usageStat) averageRequested() time.Duration {
+	if s.total == 0 {
+		return 0
+	}
+	return s.requested / time.Duration(s.total)
+}
+
+// buildUsageStats combines successful history entries with the eligibility
+// inventory into one stat per item. Items in use come first, most activated
+// first, followed by unused eligibilities by name. Elevated time follows the
+// same rules as the timeline: extensions continue an activation and
+// deactivations end it early.
+func buildUsageStats(entries []ActivationHistoryEntry, inventory []dashboardEntry, now time.Time) []usageStat {
+	stats := make(map[string]*usageStat)
+	stat := func(itemType, name string) *usageStat {
+		k := timelineKey(itemType, name)
+		if stats[k] == nil {
+			stats[k] = &usageStat{key: k, itemType: itemType, name: name}
+		}
+		return stats[k]
+	}
+
+	for _, e := range inventory {
+		itemType, _ := pendingItemDetails(e.item)
+		s := stat(itemType, pendingItemName(e.item))
+		s.name = pendingItemName(e.item)
+		s.eligible = true
+		s.standing = s.standing || e.standing
+	}
+
+	from := now.Add(-statsWindow)
+	for _, e := range entries {
+		if !e.Success || e.Time.Before(from) || e.Time.After(now) {
+			continue
+		}
+		s := stat(e.Type, e.Name)
+		if e.Time.After(s.lastUsed) && e.Kind != HistoryDeactivate {
+			s.lastUsed = e.Time
+		}
+		if e.Kind != HistoryActivate {
+			continue
+		}
+		s.total++
+		if !e.Time.Before(now.Add(-statsRecent)) {
+			s.recent++
+		}
+		s.requested += e.Duration
+	}
+
+	var current []dashboardEntry
+	for _, e := range inventory {
+		if e.status.IsActive() && !e.standing {
+			current = append(current, e)
+		}
+	}
+	for _, row := range buildTimeline(entries, current, nil, now, from, now) {
+		s := stats[row.key]
+		if s == nil {
+			continue
+		}
+		for _, span := range row.spans {
+			if span.kind == spanEnded || span.kind == spanActive {
+				start, end := maxTime(span.start, from), minTime(span.end, now)
+				if end.After(start) {
+					s.elevated += end.Sub(start)
+				}
+			}
+		}
+	}
+
+	result := make([]usageStat, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.unused() != b.unused() {
+			return !a.unused()
+		}
+		if a.total != b.total {
+			return a.total > b.total
+		}
+		if a.elevated != b.elevated {
+			return a.elevated > b.elevated
+		}
+		return a.key < b.key
+	})
+	return result
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// usageStats builds the statistics as of now
+func (m Model) usageStats(now time.Time) []usageStat {
+	return buildUsageStats(m.mergedHistory(), m.inventory(), now)
+}
+
+// historySince returns when the oldest history entry was recorded, zero when
+// there is none
+func (m Model) historySince() time.Time {
+	var oldest time.Time
+	for _, e := range m.mergedHistory() {
+		if oldest.IsZero() || e.Time.Before(oldest) {
+			oldest = e.Time
+		}
+	}
+	return oldest
+}
+
+// openStats shows the usage statistics, loading the Azure request history once
+// so activations made elsewhere are counted too
+func (m *Model) openStats() tea.Cmd {
+	m.statsCursor = 0
+	m.state = StateStats
+	if m.remoteHistoryFetched.IsZero() {
+		return m.fetchRemoteHistory()
+	}
+	return nil
+}
+
+func (m Model) handleStatsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := m.keys.Stats
+	switch {
+	case key.Matches(msg, keys.Up):
+		m.statsCursor = clampCursor(m.statsCursor, -1, len(m.usageStats(time.Now())))
+	case key.Matches(msg, keys.Down):
+		m.statsCursor = clampCursor(m.statsCursor, 1, len(m.usageStats(time.Now())))
+	case key.Matches(msg, keys.Reload):
+		return m, m.fetchRemoteHistory()
+	case key.Matches(msg, keys.Close):
+		m.state = StateNormal
+	}
+	return m, nil
+}
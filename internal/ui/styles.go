@@ -27,6 +27,11 @@ var (
 	iconInactive = "○"
 	iconPending  = "◌"
 	iconWarning  = "⚠"
+	iconStanding = "∞"
+
+	iconEligibilityEnding = "⌛"
+	iconAutoExtend        = "⟳"
+	iconFavorite          = "★"
 
 	// Base styles
 	titleStyle = lipgloss.NewStyle().
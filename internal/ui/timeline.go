@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// timelinePast and timelineFuture bound the timeline window around now
+	timelinePast   = 24 * time.Hour
+	timelineFuture = 8 * time.Hour
+)
+
+// spanKind describes what a stretch of a timeline row shows
+type spanKind int
+
+const (
+	spanEnded     spanKind = iota // Elevation that has ended
+	spanActive                    // Current elevation, up to its expiry
+	spanScheduled                 // Planned auto-extension past the current expiry
+	spanStanding                  // Standing assignment, active for the whole window
+)
+
+// timelineSpan is one stretch of time an item was, is or will be elevated
+type timelineSpan struct {
+	start, end time.Time
+	kind       spanKind
+}
+
+// timelineRow is the elevations of one role, group or Azure role
+type timelineRow struct {
+	key      string // history type and lower-cased name
+	itemType string // "role", "group" or "azure-role"
+	name     string
+	spans    []timelineSpan
+}
+
+// timelineKey identifies an item in both history entries and current data.
+// Scopes are left out: the audit trail reports them differently than the APIs,
+// and names Azure roles "Reader @ Prod" where pim-tui uses "Reader on Prod".
+func timelineKey(itemType, name string) string {
+	return itemType + "|" + strings.ToLower(strings.Replace(name, " @ ", " on ", 1))
+}
+
+// buildTimeline turns successful history entries and the current elevations
+// into one row per item with spans overlapping [from, to], earliest first.
+// Activations and extensions run until their recorded expiry, a later
+// deactivation cuts them short, and current data overrides both for
+// elevations that are active now. Renewals change the eligibility rather than
+// an elevation and draw nothing.
+func buildTimeline(entries []ActivationHistoryEntry, current []dashboardEntry, autoExtend map[string]time.Time, now, from, to time.Time) []timelineRow {
+	rows := make(map[string]*timelineRow)
+	row := func(itemType, name string) *timelineRow {
+		k := timelineKey(itemType, name)
+		if rows[k] == nil {
+			rows[k] = &timelineRow{key: k, itemType: itemType, name: name}
+		}
+		return rows[k]
+	}
+
+	sorted := append([]ActivationHistoryEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+	for _, e := range sorted {
+		if !e.Success {
+			continue
+		}
+		r := row(e.Type, e.Name)
+		var last *timelineSpan
+		if n := len(r.spans); n > 0 && r.spans[n-1].end.After(e.Time) {
+			last = &r.spans[n-1]
+		}
+		switch e.Kind {
+		case HistoryActivate, HistoryExtend:
+			end := e.Time.Add(e.Duration)
+			if e.ExpiresAt != nil {
+				end = *e.ExpiresAt
+			}
+			if !end.After(e.Time) {
+				continue
+			}
+			if last != nil {
+				// Extensions and repeated activations continue the running span
+				last.end = end
+				continue
+			}
+			r.spans = append(r.spans, timelineSpan{start: e.Time, end: end, kind: spanEnded})
+		case HistoryDeactivate:
+			if last != nil {
+				last.end = e.Time
+			}
+		}
+	}
+
+	for _, c := range current {
+		itemType, _ := pendingItemDetails(c.item)
+		r := row(itemType, pendingItemName(c.item))
+		r.name = pendingItemName(c.item) // Prefer current names over audit trail ones
+		if c.standing {
+			r.spans = []timelineSpan{{start: from, end: to, kind: spanStanding}}
+			continue
+		}
+		if c.expiresAt == nil || !c.status.IsActive() {
+			continue
+		}
+		start := now
+		if n := len(r.spans); n > 0 && !r.spans[n-1].end.Before(now.Add(-time.Minute)) {
+			// The recorded span is the one running now; Azure knows its real end
+			start = r.spans[n-1].start
+			r.spans = r.spans[:n-1]
+		}
+		r.spans = append(r.spans, timelineSpan{start: start, end: *c.expiresAt, kind: spanActive})
+		if until, ok := autoExtend[itemKey(c.item)]; ok && until.After(*c.expiresAt) {
+			r.spans = append(r.spans, timelineSpan{start: *c.expiresAt, end: until, kind: spanScheduled})
+		}
+	}
+
+	// Spans from history that have not ended yet but Azure no longer lists as
+	// active were cut short outside this machine; end them now
+	for _, r := range rows {
+		for i := range r.spans {
+			if r.spans[i].kind == spanEnded && r.spans[i].end.After(now) {
+				r.spans[i].end = now
+			}
+		}
+	}
+
+	var result []timelineRow
+	for _, r := range rows {
+		var visible []timelineSpan
+		for _, s := range r.spans {
+			if s.end.After(from) && s.start.Before(to) {
+				visible = append(visible, s)
+			}
+		}
+		if len(visible) > 0 {
+			r.spans = visible
+			result = append(result, *r)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].spans[0].start, result[j].spans[0].start
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return result[i].key < result[j].key
+	})
+	return result
+}
+
+// timelineConcurrency counts, for each of width equal slices of [from, to],
+// how many PIM elevations overlap it; standing assignments are not counted
+func timelineConcurrency(rows []timelineRow, from, to time.Time, width int) []int {
+	counts := make([]int, width)
+	step := to.Sub(from) / time.Duration(width)
+	for _, r := range rows {
+		for col := range counts {
+			start := from.Add(time.Duration(col) * step)
+			for _, s := range r.spans {
+				if (s.kind == spanEnded || s.kind == spanActive) && s.start.Before(start.Add(step)) && s.end.After(start) {
+					counts[col]++
+					break
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// timelineRows builds the timeline for the window around now
+func (m Model) timelineRows(now time.Time) []timelineRow {
+	autoExtend := make(map[string]time.Time, len(m.autoExtendRules))
+	for k, rule := range m.autoExtendRules {
+		autoExtend[k] = rule.until
+	}
+	return buildTimeline(m.mergedHistory(), m.collectDashboard(nil), autoExtend, now, now.Add(-timelinePast), now.Add(timelineFuture))
+}
+
+// openTimeline shows the timeline, loading the Azure request history once so
+// elevations made elsewhere show up too
+func (m *Model) openTimeline() tea.Cmd {
+	m.timelineCursor = 0
+	m.state = StateTimeline
+	if m.remoteHistoryFetched.IsZero() {
+		return m.fetchRemoteHistory()
+	}
+	return nil
+}
+
+func (m Model) handleTimelineKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := m.keys.Timeline
+	switch {
+	case key.Matches(msg, keys.Up):
+		m.timelineCursor = clampCursor(m.timelineCursor, -1, len(m.timelineRows(time.Now())))
+	case key.Matches(msg, keys.Down):
+		m.timelineCursor = clampCursor(m.timelineCursor, 1, len(m.timelineRows(time.Now())))
+	case key.Matches(msg, keys.Reload):
+		return m, m.fetchRemoteHistory()
+	case key.Matches(msg, keys.Close):
+		m.state = StateNormal
+	}
+	return m, nil
+}
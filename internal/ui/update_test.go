@@ -1,14 +1,23 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/seb07-cloud/pim-tui/internal/azure"
 	"github.com/seb07-cloud/pim-tui/internal/config"
+	"github.com/seb07-cloud/pim-tui/internal/notify"
 )
 
 // testModel creates a Model with default config and specified initial state
@@ -22,6 +31,46 @@ func testModel(initialState State) Model {
 	return m
 }
 
+// testModelWithItems creates a StateNormal Model with a client and copies of
+// the given roles, groups and subscriptions loaded
+func testModelWithItems(roles []azure.Role, groups []azure.Group, subs []azure.LighthouseSubscription) Model {
+	m := testModel(StateNormal)
+	m.client = &azure.Client{}
+	m.roles = slices.Clone(roles)
+	m.groups = slices.Clone(groups)
+	m.lighthouse = slices.Clone(subs)
+	return m
+}
+
+// update passes msg to m.Update and returns the resulting Model, whether
+// Update returned it by value or by pointer
+func update(t *testing.T, m Model, msg tea.Msg) (Model, tea.Cmd) {
+	t.Helper()
+	newModel, cmd := m.Update(msg)
+	switch v := newModel.(type) {
+	case Model:
+		return v, cmd
+	case *Model:
+		return *v, cmd
+	}
+	t.Fatalf("Update() returned %T", newModel)
+	return m, nil
+}
+
+// keyRunes is the key message for typing s
+func keyRunes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// typeText types s into m one key at a time
+func typeText(t *testing.T, m Model, s string) Model {
+	t.Helper()
+	for _, r := range s {
+		m, _ = update(t, m, keyRunes(string(r)))
+	}
+	return m
+}
+
 // TestUpdateStateTransitions verifies key state transitions via messages
 func TestUpdateStateTransitions(t *testing.T) {
 	tests := []struct {
@@ -134,11 +183,19 @@ func TestUpdateKeyHandling(t *testing.T) {
 			wantTab: TabSubscriptions,
 		},
 		{
-			name: "tab key cycles from subscriptions to roles",
+			name: "tab key cycles from subscriptions to active now",
 			setup: func(m *Model) {
 				m.activeTab = TabSubscriptions
 			},
 			key:     tea.KeyMsg{Type: tea.KeyTab},
+			wantTab: TabActive,
+		},
+		{
+			name: "tab key cycles from active now to roles",
+			setup: func(m *Model) {
+				m.activeTab = TabActive
+			},
+			key:     tea.KeyMsg{Type: tea.KeyTab},
 			wantTab: TabRoles,
 		},
 		{
@@ -166,12 +223,20 @@ func TestUpdateKeyHandling(t *testing.T) {
 			wantTab: TabRoles,
 		},
 		{
-			name: "right arrow at subscriptions stays at subscriptions",
+			name: "right arrow moves from subscriptions to active now",
 			setup: func(m *Model) {
 				m.activeTab = TabSubscriptions
 			},
 			key:     tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}},
-			wantTab: TabSubscriptions,
+			wantTab: TabActive,
+		},
+		{
+			name: "right arrow at active now stays at active now",
+			setup: func(m *Model) {
+				m.activeTab = TabActive
+			},
+			key:     tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}},
+			wantTab: TabActive,
 		},
 	}
 
@@ -416,7 +481,7 @@ func TestUpdateAsyncMessages(t *testing.T) {
 		}
 	})
 
-	t.Run("stays loading until all data loaded", func(t *testing.T) {
+	t.Run("shows main view after first loader", func(t *testing.T) {
 		m := testModel(StateLoading)
 		m.tenant = &azure.Tenant{DisplayName: "Test Tenant"}
 		// Only roles loaded, groups and lighthouse not yet
@@ -425,9 +490,46 @@ func TestUpdateAsyncMessages(t *testing.T) {
 		newModel, _ := m.Update(rolesLoadedMsg{roles: roles})
 		got := newModel.(Model)
 
-		// Should stay loading because groups and lighthouse not loaded yet
+		if got.state != StateNormal {
+			t.Errorf("state = %v, want StateNormal (roles tab ready)", got.state)
+		}
+		if !got.loading {
+			t.Error("loading = false, want true (groups and lighthouse not loaded)")
+		}
+		if loading, _ := got.tabLoadState(TabRoles); loading {
+			t.Error("roles tab loading = true, want false")
+		}
+		if loading, _ := got.tabLoadState(TabGroups); !loading {
+			t.Error("groups tab loading = false, want true")
+		}
+	})
+
+	t.Run("stays loading until tenant known", func(t *testing.T) {
+		m := testModel(StateLoading)
+
+		newModel, _ := m.Update(rolesLoadedMsg{roles: []azure.Role{{DisplayName: "Role1"}}})
+		got := newModel.(Model)
+
 		if got.state != StateLoading {
-			t.Errorf("state = %v, want StateLoading (groups and lighthouse not loaded)", got.state)
+			t.Errorf("state = %v, want StateLoading (tenant not loaded)", got.state)
+		}
+	})
+
+	t.Run("load error sets tab badge until next success", func(t *testing.T) {
+		m := testModel(StateNormal)
+		m.tenant = &azure.Tenant{DisplayName: "Test Tenant"}
+		m.lighthouseFetching = true
+
+		newModel, _ := m.Update(errMsg{err: fmt.Errorf("ARM timeout"), source: "lighthouse"})
+		got := newModel.(Model)
+		if loading, err := got.tabLoadState(TabSubscriptions); loading || err == nil {
+			t.Errorf("tabLoadState = %v, %v, want false, error", loading, err)
+		}
+
+		newModel, _ = got.Update(lighthouseLoadedMsg{subs: nil})
+		got = newModel.(Model)
+		if _, err := got.tabLoadState(TabSubscriptions); err != nil {
+			t.Errorf("tabLoadState error = %v, want nil after successful load", err)
 		}
 	})
 
@@ -584,8 +686,8 @@ func TestUpdateSelectionToggle(t *testing.T) {
 		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
 		got := newModel.(Model)
 
-		if !got.selectedRoles[0] {
-			t.Error("selectedRoles[0] = false, want true")
+		if !got.selectedRoles[itemKey(got.roles[0])] {
+			t.Error("role not selected, want selected")
 		}
 	})
 
@@ -594,13 +696,13 @@ func TestUpdateSelectionToggle(t *testing.T) {
 		m.activeTab = TabRoles
 		m.roles = []azure.Role{{DisplayName: "Role1"}}
 		m.rolesCursor = 0
-		m.selectedRoles[0] = true
+		m.selectedRoles[itemKey(m.roles[0])] = true
 
 		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
 		got := newModel.(Model)
 
-		if got.selectedRoles[0] {
-			t.Error("selectedRoles[0] = true, want false")
+		if len(got.selectedRoles) != 0 {
+			t.Errorf("selectedRoles = %v, want none", got.selectedRoles)
 		}
 	})
 
@@ -613,8 +715,8 @@ func TestUpdateSelectionToggle(t *testing.T) {
 		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
 		got := newModel.(Model)
 
-		if !got.selectedGroups[0] {
-			t.Error("selectedGroups[0] = false, want true")
+		if !got.selectedGroups[itemKey(got.groups[0])] {
+			t.Error("group not selected, want selected")
 		}
 	})
 }
@@ -747,7 +849,7 @@ func TestUpdateLoadingStateHandling(t *testing.T) {
 func TestUpdateActivationDone(t *testing.T) {
 	t.Run("successful activation returns to normal", func(t *testing.T) {
 		m := testModel(StateActivating)
-		m.selectedRoles[0] = true
+		m.selectedRoles["role|/|r1"] = true
 
 		newModel, _ := m.Update(activationDoneMsg{err: nil})
 		got := newModel.(Model)
@@ -782,7 +884,7 @@ func TestUpdateActivationDone(t *testing.T) {
 func TestUpdateDeactivationDone(t *testing.T) {
 	t.Run("successful deactivation returns to normal", func(t *testing.T) {
 		m := testModel(StateDeactivating)
-		m.selectedRoles[0] = true
+		m.selectedRoles["role|/|r1"] = true
 
 		newModel, _ := m.Update(deactivationDoneMsg{err: nil})
 		got := newModel.(Model)
@@ -858,3 +960,1935 @@ func TestUpdateLogLevelCycle(t *testing.T) {
 		t.Errorf("logLevel = %v, want LogDebug", got.logLevel)
 	}
 }
+
+// TestUpdateStepUp tests the additional authentication flow for claims challenges
+func TestUpdateStepUp(t *testing.T) {
+	t.Run("claims challenge enters step-up state and retries", func(t *testing.T) {
+		m := testModel(StateActivating)
+		remaining := []interface{}{azure.Role{DisplayName: "Global Administrator"}}
+
+		newModel, cmd := m.Update(stepUpRequiredMsg{claims: `{"access_token":{}}`, remaining: remaining})
+		got := newModel.(Model)
+
+		if got.state != StateStepUp {
+			t.Errorf("state = %v, want StateStepUp", got.state)
+		}
+		if len(got.pendingActivations) != 1 {
+			t.Errorf("pendingActivations length = %d, want 1", len(got.pendingActivations))
+		}
+		if cmd == nil {
+			t.Error("cmd = nil, want retry command")
+		}
+	})
+
+	t.Run("esc cancels step-up", func(t *testing.T) {
+		m := testModel(StateStepUp)
+		cancelled := false
+		m.stepUpCancelFunc = func() { cancelled = true }
+		m.pendingActivations = []interface{}{azure.Role{}}
+
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		got := newModel.(Model)
+
+		if got.state != StateNormal {
+			t.Errorf("state = %v, want StateNormal", got.state)
+		}
+		if !cancelled {
+			t.Error("step-up context was not cancelled")
+		}
+		if got.pendingActivations != nil {
+			t.Error("pendingActivations not cleared")
+		}
+	})
+
+	t.Run("cancelled retry is not reported as failed", func(t *testing.T) {
+		m := testModel(StateActivating)
+		reader := azure.Role{DisplayName: "Reader", RoleDefinitionID: "r1"}
+		admin := azure.Role{DisplayName: "Global Administrator", RoleDefinitionID: "r2"}
+		newModel, _ := m.Update(stepUpRequiredMsg{claims: `{}`, remaining: []interface{}{admin}, done: []bulkResult{{item: reader}}})
+		newModel, _ = newModel.(Model).Update(tea.KeyMsg{Type: tea.KeyEsc})
+		newModel, _ = newModel.(Model).Update(activationDoneMsg{results: []bulkResult{
+			{item: admin, err: fmt.Errorf("failed to get PIM token: %w", context.Canceled)},
+		}})
+		got := newModel.(Model)
+
+		if got.state != StateNormal || got.bulkResults != nil {
+			t.Errorf("state = %v, bulkResults = %v; want StateNormal without a results dialog", got.state, got.bulkResults)
+		}
+		if len(got.activationHistory) != 1 || !got.activationHistory[0].Success || got.activationHistory[0].Name != "Reader" {
+			t.Errorf("activationHistory = %+v, want only the activation made before the sign-in", got.activationHistory)
+		}
+		if got.stepUpCancelled {
+			t.Error("stepUpCancelled still set")
+		}
+	})
+}
+
+// TestUpdateLoadProgress tests fan-out progress reporting on the loading screen
+func TestUpdateLoadProgress(t *testing.T) {
+	m := testModel(StateLoading)
+
+	newModel, cmd := m.Update(loadProgressMsg{stage: "group names", done: 12, total: 400})
+	got := newModel.(Model)
+
+	if cmd == nil {
+		t.Error("cmd = nil, want command listening for further progress")
+	}
+	if suffix := got.progressSuffix("group names"); suffix != " (group names 12/400)" {
+		t.Errorf("progressSuffix = %q, want %q", suffix, " (group names 12/400)")
+	}
+
+	newModel, _ = got.Update(loadProgressMsg{stage: "group names", done: 400, total: 400})
+	got = newModel.(Model)
+	if suffix := got.progressSuffix("group names"); suffix != "" {
+		t.Errorf("progressSuffix after completion = %q, want empty", suffix)
+	}
+}
+
+// TestUpdateSnapshot tests that cached data is shown while loading and replaced per loader
+func TestUpdateSnapshot(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(2 * time.Hour)
+	snap := &snapshot{
+		SavedAt: time.Now().Add(-10 * time.Minute),
+		Tenant:  &azure.Tenant{ID: "t1", DisplayName: "Cached Tenant"},
+		Roles: []azure.Role{
+			{DisplayName: "Expired", Status: azure.StatusActive, ExpiresAt: &past},
+			{DisplayName: "Still active", Status: azure.StatusActive, ExpiresAt: &future},
+		},
+		Groups: []azure.Group{{DisplayName: "Cached Group"}},
+	}
+
+	t.Run("snapshot renders immediately as stale", func(t *testing.T) {
+		m := testModel(StateLoading)
+		newModel, _ := m.Update(snapshotLoadedMsg{snap: snap})
+		got := newModel.(Model)
+
+		if got.state != StateNormal {
+			t.Errorf("state = %v, want StateNormal", got.state)
+		}
+		if !got.isStale() {
+			t.Error("isStale() = false, want true")
+		}
+		if got.roles[0].Status != azure.StatusInactive {
+			t.Errorf("expired role status = %v, want Inactive", got.roles[0].Status)
+		}
+		if got.roles[1].Status != azure.StatusActive {
+			t.Errorf("active role status = %v, want Active", got.roles[1].Status)
+		}
+	})
+
+	t.Run("snapshot ignored once real data arrived", func(t *testing.T) {
+		m := testModel(StateLoading)
+		m.rolesLoaded = true
+		newModel, _ := m.Update(snapshotLoadedMsg{snap: snap})
+		got := newModel.(Model)
+
+		if got.state != StateLoading || got.isStale() {
+			t.Errorf("state = %v, stale = %v, want StateLoading and not stale", got.state, got.isStale())
+		}
+	})
+
+	t.Run("loaders swap in fresh data one by one", func(t *testing.T) {
+		m := testModel(StateLoading)
+		newModel, _ := m.Update(snapshotLoadedMsg{snap: snap})
+		newModel, _ = newModel.(Model).Update(tenantLoadedMsg{tenant: &azure.Tenant{ID: "t1", DisplayName: "Real"}})
+		newModel, cmd := newModel.(Model).Update(rolesLoadedMsg{roles: []azure.Role{{DisplayName: "Fresh"}}})
+		got := newModel.(Model)
+
+		if got.staleRoles || !got.staleGroups {
+			t.Errorf("staleRoles = %v, staleGroups = %v, want false, true", got.staleRoles, got.staleGroups)
+		}
+		if cmd != nil {
+			t.Error("cmd != nil, want no snapshot save while data is stale")
+		}
+
+		newModel, _ = got.Update(groupsLoadedMsg{groups: nil})
+		newModel, cmd = newModel.(Model).Update(lighthouseLoadedMsg{subs: nil})
+		got = newModel.(Model)
+		if got.isStale() {
+			t.Error("isStale() = true after all loaders finished, want false")
+		}
+		if cmd == nil {
+			t.Error("cmd = nil, want snapshot save once all data is fresh")
+		}
+	})
+
+	t.Run("failed refresh keeps cached data and says so", func(t *testing.T) {
+		m := testModel(StateLoading)
+		m.pendingCursors[TabRoles] = "role|/|gone"
+		newModel, _ := m.Update(snapshotLoadedMsg{snap: snap})
+		newModel, _ = newModel.(Model).Update(tenantLoadedMsg{tenant: &azure.Tenant{ID: "t1", DisplayName: "Real"}})
+		got := newModel.(Model)
+		if header := got.renderHeader(); !strings.Contains(header, "refreshing") {
+			t.Errorf("header while loading = %q, want refreshing", header)
+		}
+
+		newModel, _ = got.Update(errMsg{source: "roles", err: fmt.Errorf("API error 503")})
+		got = newModel.(Model)
+		if !got.staleRoles || len(got.roles) != 2 {
+			t.Errorf("staleRoles = %v, roles = %d; want the cached roles kept", got.staleRoles, len(got.roles))
+		}
+		header := got.renderHeader()
+		if !strings.Contains(header, "refresh failed") || strings.Contains(header, "refreshing") {
+			t.Errorf("header = %q, want refresh failed", header)
+		}
+		if _, pending := got.pendingCursors[TabRoles]; pending {
+			t.Error("roles cursor still waiting after the load failed")
+		}
+
+		// Retrying shows the refresh in progress again
+		got.rolesFetching = true
+		if header := got.renderHeader(); !strings.Contains(header, "refreshing") {
+			t.Errorf("header while retrying = %q, want refreshing", header)
+		}
+	})
+
+	t.Run("different tenant discards cached data", func(t *testing.T) {
+		m := testModel(StateLoading)
+		newModel, _ := m.Update(snapshotLoadedMsg{snap: snap})
+		newModel, _ = newModel.(Model).Update(tenantLoadedMsg{tenant: &azure.Tenant{ID: "t2", DisplayName: "Other"}})
+		got := newModel.(Model)
+
+		if got.isStale() || len(got.roles) != 0 {
+			t.Errorf("stale = %v, roles = %d, want cached data discarded", got.isStale(), len(got.roles))
+		}
+		if got.state != StateLoading {
+			t.Errorf("state = %v, want StateLoading", got.state)
+		}
+	})
+}
+
+// TestUpdateBulkResults tests the per-item results dialog and retrying failed items
+func TestUpdateBulkResults(t *testing.T) {
+	ok := azure.Role{DisplayName: "Reader"}
+	bad := azure.Role{DisplayName: "Global Administrator"}
+	results := []bulkResult{
+		{item: ok},
+		{item: bad, err: fmt.Errorf("RoleAssignmentRequestPolicyValidationFailed")},
+	}
+
+	t.Run("partial failure shows results dialog", func(t *testing.T) {
+		m := testModel(StateActivating)
+		m.selectedRoles["role|/|r1"] = true
+
+		newModel, cmd := m.Update(activationDoneMsg{results: results})
+		got := newModel.(Model)
+
+		if got.state != StateResults {
+			t.Errorf("state = %v, want StateResults", got.state)
+		}
+		if len(got.bulkResults) != 2 || got.bulkOperation != "activation" {
+			t.Errorf("bulkResults = %d (%s), want 2 activation results", len(got.bulkResults), got.bulkOperation)
+		}
+		if cmd == nil {
+			t.Error("cmd = nil, want refresh for succeeded items")
+		}
+		if !got.selectedRoles["role|/|r1"] {
+			t.Error("selections cleared, want kept until failures resolved")
+		}
+	})
+
+	t.Run("retry runs only failed items", func(t *testing.T) {
+		m := testModel(StateResults)
+		m.bulkResults = results
+		m.bulkOperation = "activation"
+
+		newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+		got := newModel.(*Model)
+
+		if got.state != StateActivating {
+			t.Errorf("state = %v, want StateActivating", got.state)
+		}
+		if len(got.pendingActivations) != 1 || pendingItemName(got.pendingActivations[0]) != "Global Administrator" {
+			t.Errorf("pendingActivations = %v, want only the failed role", got.pendingActivations)
+		}
+		if cmd == nil {
+			t.Error("cmd = nil, want activation command")
+		}
+	})
+
+	t.Run("esc closes results dialog", func(t *testing.T) {
+		m := testModel(StateResults)
+		m.bulkResults = results
+
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		got := newModel.(Model)
+
+		if got.state != StateNormal || got.bulkResults != nil {
+			t.Errorf("state = %v, bulkResults = %v, want StateNormal and cleared", got.state, got.bulkResults)
+		}
+	})
+
+	t.Run("step-up results are merged into final outcome", func(t *testing.T) {
+		m := testModel(StateActivating)
+
+		newModel, _ := m.Update(stepUpRequiredMsg{claims: `{}`, remaining: []interface{}{bad}, done: []bulkResult{{item: ok}}})
+		newModel, _ = newModel.(Model).Update(activationDoneMsg{results: []bulkResult{{item: bad}}})
+		got := newModel.(Model)
+
+		if got.state != StateNormal {
+			t.Errorf("state = %v, want StateNormal (all succeeded)", got.state)
+		}
+		if got.stepUpResults != nil {
+			t.Error("stepUpResults not cleared")
+		}
+	})
+}
+
+// TestUpdateActivationHistory tests that history records real per-item outcomes
+func TestUpdateActivationHistory(t *testing.T) {
+	expiry := time.Now().Add(4 * time.Hour)
+	m := testModel(StateActivating)
+	m.justificationInput.SetValue("incident 42")
+
+	newModel, _ := m.Update(activationDoneMsg{results: []bulkResult{
+		{item: azure.Role{DisplayName: "Reader", DirectoryScopeID: "/"}, result: azure.RequestResult{RequestID: "req-1", ExpiresAt: &expiry}},
+		{item: azure.Group{ID: "g1", DisplayName: "Admins"}, err: fmt.Errorf("policy violation")},
+	}})
+	got := newModel.(Model)
+
+	if len(got.activationHistory) != 2 {
+		t.Fatalf("activationHistory length = %d, want 2", len(got.activationHistory))
+	}
+	ok, failed := got.activationHistory[0], got.activationHistory[1]
+	if !ok.Success || ok.RequestID != "req-1" || ok.Kind != HistoryActivate || ok.Scope != "/" || ok.ExpiresAt == nil {
+		t.Errorf("success entry = %+v, want activate with request ID, scope and expiry", ok)
+	}
+	if ok.Justification != "incident 42" {
+		t.Errorf("Justification = %q, want %q", ok.Justification, "incident 42")
+	}
+	if failed.Success || failed.Error != "policy violation" || failed.Scope != "g1" {
+		t.Errorf("failed entry = %+v, want failure with error text", failed)
+	}
+
+	t.Run("deactivations are recorded", func(t *testing.T) {
+		m := testModel(StateDeactivating)
+		newModel, _ := m.Update(deactivationDoneMsg{results: []bulkResult{{item: azure.Role{DisplayName: "Reader"}}}})
+		got := newModel.(Model)
+
+		if len(got.activationHistory) != 1 || got.activationHistory[0].Kind != HistoryDeactivate {
+			t.Errorf("activationHistory = %+v, want one deactivate entry", got.activationHistory)
+		}
+	})
+
+	t.Run("H opens and esc closes history view", func(t *testing.T) {
+		m := testModel(StateNormal)
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'H'}})
+		got := newModel.(Model)
+		if got.state != StateHistory {
+			t.Fatalf("state = %v, want StateHistory", got.state)
+		}
+		newModel, _ = got.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if state := newModel.(Model).state; state != StateNormal {
+			t.Errorf("state = %v, want StateNormal", state)
+		}
+	})
+}
+
+// TestUpdateHistoryPersistence tests loading persisted history and filtering it in the view
+func TestUpdateHistoryPersistence(t *testing.T) {
+	m := testModel(StateNormal)
+	m.appendHistory(ActivationHistoryEntry{Time: time.Now(), Kind: HistoryActivate, Name: "Session", Success: true})
+
+	got, _ := update(t, m, historyLoadedMsg{entries: []ActivationHistoryEntry{
+		{Time: time.Now().AddDate(0, 0, -3), Kind: HistoryActivate, Name: "Reader", Success: true},
+		{Time: time.Now().AddDate(0, 0, -2), Kind: HistoryDeactivate, Name: "Owner", Success: false},
+	}})
+
+	if len(got.activationHistory) != 3 || got.activationHistory[2].Name != "Session" {
+		t.Fatalf("activationHistory = %+v, want loaded entries before session entry", got.activationHistory)
+	}
+
+	got.state = StateHistory
+	tests := []struct {
+		name      string
+		keys      []string
+		wantNames []string
+	}{
+		{"no filter lists newest first", nil, []string{"Session", "Owner", "Reader"}},
+		{"t filters by kind", []string{"t"}, []string{"Session", "Reader"}},
+		{"o filters by outcome", []string{"o", "o"}, []string{"Owner"}},
+		{"d filters by date", []string{"d"}, []string{"Session"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := got
+			for _, k := range tt.keys {
+				m, _ = update(t, m, keyRunes(k))
+			}
+			entries := m.filteredHistory()
+			if len(entries) != len(tt.wantNames) {
+				t.Fatalf("filteredHistory() = %d entries, want %d", len(entries), len(tt.wantNames))
+			}
+			for i, name := range tt.wantNames {
+				if entries[i].Name != name {
+					t.Errorf("entries[%d].Name = %q, want %q", i, entries[i].Name, name)
+				}
+			}
+		})
+	}
+
+	t.Run("name filter via text input", func(t *testing.T) {
+		m, _ := update(t, got, keyRunes("/"))
+		if !m.historyFilterEditing {
+			t.Fatal("historyFilterEditing = false, want true after /")
+		}
+		m, _ = update(t, m, keyRunes("own"))
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		if entries := m.filteredHistory(); len(entries) != 1 || entries[0].Name != "Owner" {
+			t.Errorf("filteredHistory() = %+v, want only Owner", entries)
+		}
+		if m, _ = update(t, m, keyRunes("c")); len(m.filteredHistory()) != 3 {
+			t.Error("c did not clear filters")
+		}
+	})
+}
+
+// TestUpdateExportDialog tests opening, editing and running the export dialog
+func TestUpdateExportDialog(t *testing.T) {
+	dir := t.TempDir()
+	m := testModel(StateHistory)
+	m.historyRange = 2
+	m.activationHistory = []ActivationHistoryEntry{
+		{Time: time.Now().AddDate(0, 0, -20), Kind: HistoryActivate, Name: "Old", Success: true},
+		{Time: time.Now(), Kind: HistoryActivate, Name: "Recent", Success: true},
+	}
+	m.mergeHistory()
+	key := func(m Model, msg tea.KeyMsg) Model {
+		newModel, _ := m.Update(msg)
+		return newModel.(Model)
+	}
+
+	m = key(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if m.state != StateExport {
+		t.Fatalf("state = %v, want StateExport", m.state)
+	}
+	if m.exportRange != 2 {
+		t.Errorf("exportRange = %d, want history view range 2", m.exportRange)
+	}
+	if !strings.HasSuffix(m.exportPathInput.Value(), ".csv") {
+		t.Errorf("default path = %q, want .csv suffix", m.exportPathInput.Value())
+	}
+
+	// Changing the format updates the suggested file name
+	m = key(m, tea.KeyMsg{Type: tea.KeyDown})
+	m = key(m, tea.KeyMsg{Type: tea.KeyRight})
+	if !strings.HasSuffix(m.exportPathInput.Value(), ".json") {
+		t.Errorf("path after format change = %q, want .json suffix", m.exportPathInput.Value())
+	}
+
+	// Typing on the path field replaces the suggestion
+	m = key(m, tea.KeyMsg{Type: tea.KeyDown})
+	m = key(m, tea.KeyMsg{Type: tea.KeyDown})
+	if m.exportField != exportFieldPath {
+		t.Fatalf("exportField = %d, want path field", m.exportField)
+	}
+	path := filepath.Join(dir, "history.json")
+	m.exportPathInput.SetValue("")
+	m = key(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(path)})
+	if !m.exportPathEdited || m.exportPathInput.Value() != path {
+		t.Fatalf("path = %q (edited %v), want %q", m.exportPathInput.Value(), m.exportPathEdited, path)
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.state != StateHistory {
+		t.Errorf("state = %v, want StateHistory after export", m.state)
+	}
+	if cmd == nil {
+		t.Fatal("enter should return an export command")
+	}
+	done, ok := cmd().(exportDoneMsg)
+	if !ok || done.err != nil {
+		t.Fatalf("export result = %+v, want success", done)
+	}
+	if done.count != 1 {
+		t.Errorf("exported %d entries, want 1 within the last 7 days", done.count)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), "Recent") || strings.Contains(string(data), "Old") {
+		t.Errorf("export file = %q, %v; want only Recent", data, err)
+	}
+
+	t.Run("esc cancels", func(t *testing.T) {
+		m := testModel(StateNormal)
+		m = key(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+		newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if got := newModel.(Model); got.state != StateNormal || cmd != nil {
+			t.Errorf("state = %v, cmd = %v; want StateNormal and no command", got.state, cmd)
+		}
+	})
+}
+
+// TestUpdateStandingAssignments tests the standing access toggle and that standing items cannot be selected
+func TestUpdateStandingAssignments(t *testing.T) {
+	m := testModel(StateNormal)
+	m.activeTab = TabRoles
+	m.roles = []azure.Role{
+		{DisplayName: "Global Reader", RoleDefinitionID: "r1", Status: azure.StatusInactive},
+		{DisplayName: "Global Administrator", RoleDefinitionID: "r2", Status: azure.StatusActive, Standing: true},
+	}
+
+	m.rolesCursor = 1
+	m, _ = update(t, m, keyRunes(" "))
+	if m.selectedRoles[itemKey(m.roles[1])] {
+		t.Errorf("selectedRoles = %v, standing role should not be selectable", m.selectedRoles)
+	}
+	m.rolesCursor = 0
+	if m, _ = update(t, m, keyRunes(" ")); !m.selectedRoles[itemKey(m.roles[0])] {
+		t.Error("eligible role should still be selectable")
+	}
+
+	if roles, _ := m.countActiveItems(); roles != 0 {
+		t.Errorf("countActiveItems() roles = %d, want 0 (standing not counted)", roles)
+	}
+	if got := m.countStandingItems(); got != 1 {
+		t.Errorf("countStandingItems() = %d, want 1", got)
+	}
+
+	if m, _ = update(t, m, keyRunes("S")); !m.config.ShowStanding {
+		t.Error("S should enable ShowStanding")
+	}
+	if m, _ = update(t, m, keyRunes("S")); m.config.ShowStanding {
+		t.Error("second S should disable ShowStanding")
+	}
+}
+
+// TestUpdateRenewal tests opening, cancelling and submitting the renewal dialog
+func TestUpdateRenewal(t *testing.T) {
+	soon := time.Now().Add(3 * 24 * time.Hour)
+	roles := []azure.Role{
+		{DisplayName: "Global Reader", RoleDefinitionID: "r1", EligibleUntil: &soon},
+		{DisplayName: "Security Reader", RoleDefinitionID: "r2"}, // Permanent eligibility
+	}
+
+	t.Run("permanent eligibility is not renewable", func(t *testing.T) {
+		m := testModelWithItems(roles, nil, nil)
+		m.rolesCursor = 1
+		if m, _ = update(t, m, keyRunes("n")); m.state != StateNormal {
+			t.Errorf("state = %v, want StateNormal", m.state)
+		}
+	})
+
+	t.Run("selected items without end date are skipped", func(t *testing.T) {
+		m := testModelWithItems(roles, nil, nil)
+		m.selectedRoles[itemKey(m.roles[0])] = true
+		m.selectedRoles[itemKey(m.roles[1])] = true
+		m, _ = update(t, m, keyRunes("n"))
+		if m.state != StateRenew || len(m.pendingRenewals) != 1 {
+			t.Fatalf("state = %v, pending = %d; want StateRenew with 1 item", m.state, len(m.pendingRenewals))
+		}
+		if m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEsc}); m.state != StateNormal || m.pendingRenewals != nil {
+			t.Errorf("esc: state = %v, pending = %v; want StateNormal and none", m.state, m.pendingRenewals)
+		}
+	})
+
+	t.Run("submit requires justification", func(t *testing.T) {
+		m := testModelWithItems(roles, nil, nil)
+		m, _ = update(t, m, keyRunes("n"))
+		if m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEnter}); m.state != StateRenew {
+			t.Fatalf("state = %v, want StateRenew without justification", m.state)
+		}
+		m, _ = update(t, m, keyRunes("keep reader access"))
+		m, cmd := update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.state != StateRenewing || cmd == nil {
+			t.Errorf("state = %v, cmd = %v; want StateRenewing with a command", m.state, cmd)
+		}
+	})
+
+	t.Run("results are recorded as renewals", func(t *testing.T) {
+		m := testModelWithItems(roles, nil, nil)
+		m.state = StateRenewing
+		m.pendingRenewals = []interface{}{m.roles[0]}
+		m, _ = update(t, m, renewalDoneMsg{results: []bulkResult{{item: m.roles[0]}}})
+		if m.state != StateNormal {
+			t.Errorf("state = %v, want StateNormal", m.state)
+		}
+		last := m.activationHistory[len(m.activationHistory)-1]
+		if last.Kind != HistoryRenew || last.Duration != m.renewalDuration() {
+			t.Errorf("history entry = %+v, want renew lasting %v", last, m.renewalDuration())
+		}
+	})
+}
+
+// recordingSink captures notifications instead of delivering them
+type recordingSink struct{ events []notify.Event }
+
+func (r *recordingSink) Notify(ctx context.Context, e notify.Event) error {
+	r.events = append(r.events, e)
+	return nil
+}
+
+// runNotifyCmds executes cmd, following batches, and returns how many
+// notifications were delivered
+func runNotifyCmds(cmd tea.Cmd) int {
+	if cmd == nil {
+		return 0
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		n := 0
+		for _, c := range msg {
+			n += runNotifyCmds(c)
+		}
+		return n
+	case notifyDoneMsg:
+		return 1
+	}
+	return 0
+}
+
+func TestUpdateNotifications(t *testing.T) {
+	newNotifyModel := func() (Model, *recordingSink) {
+		m := testModel(StateNormal)
+		sink := &recordingSink{}
+		m.notifier = notify.NewWithSinks(sink)
+		m.expiryTracker = notify.NewExpiryTracker([]int{15, 5})
+		return m, sink
+	}
+
+	t.Run("expiry thresholds fire once each", func(t *testing.T) {
+		m, sink := newNotifyModel()
+		now := time.Now()
+		expires := now.Add(12 * time.Minute)
+		m.roles = []azure.Role{
+			{DisplayName: "Global Reader", RoleDefinitionID: "r1", Status: StatusActive, ExpiresAt: &expires},
+			{DisplayName: "Standing Admin", RoleDefinitionID: "r2", Status: StatusActive, Standing: true, ExpiresAt: &expires},
+			{DisplayName: "Inactive", RoleDefinitionID: "r3", Status: StatusInactive},
+		}
+
+		runNotifyCmds(m.checkExpiryNotifications(now))
+		// Throttled: a check within the interval does nothing
+		runNotifyCmds(m.checkExpiryNotifications(now.Add(100 * time.Millisecond)))
+		runNotifyCmds(m.checkExpiryNotifications(now.Add(2 * time.Minute)))
+		if len(sink.events) != 1 || sink.events[0].Kind != notify.KindExpiring {
+			t.Fatalf("events = %+v, want one expiry notification", sink.events)
+		}
+		if !strings.Contains(sink.events[0].Message, "Global Reader") {
+			t.Errorf("message = %q, want role name", sink.events[0].Message)
+		}
+
+		runNotifyCmds(m.checkExpiryNotifications(now.Add(8 * time.Minute)))
+		if len(sink.events) != 2 {
+			t.Errorf("events = %d after crossing 5 minutes, want 2", len(sink.events))
+		}
+	})
+
+	t.Run("failed activations notify", func(t *testing.T) {
+		m, sink := newNotifyModel()
+		role := azure.Role{DisplayName: "Global Reader"}
+		runNotifyCmds(m.trackBulkNotifications("activation", []bulkResult{{item: role, err: fmt.Errorf("denied")}}))
+		if len(sink.events) != 1 || sink.events[0].Kind != notify.KindFailed {
+			t.Fatalf("events = %+v, want one failure notification", sink.events)
+		}
+
+		m.config.Notifications.OnFailure = false
+		runNotifyCmds(m.trackBulkNotifications("activation", []bulkResult{{item: role, err: fmt.Errorf("denied")}}))
+		runNotifyCmds(m.trackBulkNotifications("deactivation", []bulkResult{{item: role, err: fmt.Errorf("denied")}}))
+		if len(sink.events) != 1 {
+			t.Errorf("events = %d, want failures of disabled or other operations ignored", len(sink.events))
+		}
+	})
+
+	t.Run("approved requests notify once active", func(t *testing.T) {
+		m, sink := newNotifyModel()
+		role := azure.Role{DisplayName: "Global Admin", RoleDefinitionID: "r1", DirectoryScopeID: "/"}
+		runNotifyCmds(m.trackBulkNotifications("activation", []bulkResult{{item: role, result: azure.RequestResult{Status: "PendingApproval"}}}))
+		if len(m.awaitingApproval) != 1 || len(sink.events) != 0 {
+			t.Fatalf("awaiting = %d, events = %d; want 1 awaiting, no events", len(m.awaitingApproval), len(sink.events))
+		}
+
+		// Still pending after a refresh
+		newModel, cmd := m.Update(rolesLoadedMsg{roles: []azure.Role{role}})
+		m = newModel.(Model)
+		runNotifyCmds(cmd)
+		if len(sink.events) != 0 {
+			t.Fatalf("events = %d before approval, want 0", len(sink.events))
+		}
+
+		expires := time.Now().Add(time.Hour)
+		role.Status = StatusActive
+		role.ExpiresAt = &expires
+		newModel, cmd = m.Update(rolesLoadedMsg{roles: []azure.Role{role}})
+		m = newModel.(Model)
+		runNotifyCmds(cmd)
+		if len(sink.events) != 1 || sink.events[0].Kind != notify.KindApproved {
+			t.Fatalf("events = %+v, want one approval notification", sink.events)
+		}
+		if len(m.awaitingApproval) != 0 {
+			t.Errorf("awaiting = %d after approval, want 0", len(m.awaitingApproval))
+		}
+	})
+}
+
+func TestPlanAutoExtend(t *testing.T) {
+	now := time.Date(2026, 3, 1, 14, 0, 0, 0, time.Local)
+	until := time.Date(2026, 3, 1, 20, 0, 0, 0, time.Local)
+	lead := 10 * time.Minute
+
+	tests := []struct {
+		name         string
+		rule         autoExtendRule
+		expiresAt    time.Time
+		policyMax    time.Duration
+		maxCap       time.Duration
+		wantStep     autoExtendStep
+		wantDuration time.Duration
+	}{
+		{
+			name:      "far from expiry waits",
+			rule:      autoExtendRule{until: until},
+			expiresAt: now.Add(time.Hour),
+			wantStep:  autoExtendWait,
+		},
+		{
+			name:         "inside lead extends to target",
+			rule:         autoExtendRule{until: until},
+			expiresAt:    now.Add(5 * time.Minute),
+			wantStep:     autoExtendSubmit,
+			wantDuration: 6 * time.Hour,
+		},
+		{
+			name:         "bounded by policy maximum",
+			rule:         autoExtendRule{until: until},
+			expiresAt:    now.Add(5 * time.Minute),
+			policyMax:    4 * time.Hour,
+			wantStep:     autoExtendSubmit,
+			wantDuration: 4 * time.Hour,
+		},
+		{
+			name:         "bounded by global cap",
+			rule:         autoExtendRule{until: until},
+			expiresAt:    now.Add(5 * time.Minute),
+			policyMax:    8 * time.Hour,
+			maxCap:       2 * time.Hour,
+			wantStep:     autoExtendSubmit,
+			wantDuration: 2 * time.Hour,
+		},
+		{
+			name:      "request in flight waits",
+			rule:      autoExtendRule{until: until, inFlight: true},
+			expiresAt: now.Add(5 * time.Minute),
+			wantStep:  autoExtendWait,
+		},
+		{
+			name:      "failed request waits for retry",
+			rule:      autoExtendRule{until: until, retryAt: now.Add(time.Minute)},
+			expiresAt: now.Add(5 * time.Minute),
+			wantStep:  autoExtendWait,
+		},
+		{
+			name:      "already lasts until target",
+			rule:      autoExtendRule{until: until},
+			expiresAt: until,
+			wantStep:  autoExtendDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, duration := planAutoExtend(tt.rule, tt.expiresAt, now, lead, tt.policyMax, tt.maxCap)
+			if step != tt.wantStep || duration != tt.wantDuration {
+				t.Errorf("planAutoExtend() = (%v, %v), want (%v, %v)", step, duration, tt.wantStep, tt.wantDuration)
+			}
+		})
+	}
+}
+
+func TestParseAutoExtendUntil(t *testing.T) {
+	now := time.Date(2026, 3, 1, 14, 30, 0, 0, time.Local)
+
+	until, err := parseAutoExtendUntil("18:45", now)
+	if err != nil || !until.Equal(time.Date(2026, 3, 1, 18, 45, 0, 0, time.Local)) {
+		t.Errorf("parseAutoExtendUntil(18:45) = %v, %v; want today 18:45", until, err)
+	}
+	// Times already passed today mean tomorrow
+	until, err = parseAutoExtendUntil("02:00", now)
+	if err != nil || !until.Equal(time.Date(2026, 3, 2, 2, 0, 0, 0, time.Local)) {
+		t.Errorf("parseAutoExtendUntil(02:00) = %v, %v; want tomorrow 02:00", until, err)
+	}
+	if _, err := parseAutoExtendUntil("6pm", now); err == nil {
+		t.Error("parseAutoExtendUntil(6pm) error = nil, want error")
+	}
+}
+
+func TestUpdateAutoExtend(t *testing.T) {
+	expires := time.Now().Add(time.Hour)
+	roles := []azure.Role{
+		{DisplayName: "Global Reader", RoleDefinitionID: "r1", Status: StatusActive, ExpiresAt: &expires},
+		{DisplayName: "Security Reader", RoleDefinitionID: "r2", Status: StatusInactive},
+	}
+
+	t.Run("inactive items cannot be auto-extended", func(t *testing.T) {
+		m := testModelWithItems(roles, nil, nil)
+		m.rolesCursor = 1
+		if m, _ = update(t, m, keyRunes("A")); m.state != StateNormal {
+			t.Errorf("state = %v, want StateNormal", m.state)
+		}
+	})
+
+	t.Run("set and clear a rule", func(t *testing.T) {
+		m := testModelWithItems(roles, nil, nil)
+		m, _ = update(t, m, keyRunes("A"))
+		if m.state != StateAutoExtend || len(m.pendingAutoExtend) != 1 {
+			t.Fatalf("state = %v, pending = %d; want StateAutoExtend with 1 item", m.state, len(m.pendingAutoExtend))
+		}
+		m, _ = update(t, m, keyRunes("bad"))
+		if m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEnter}); m.state != StateAutoExtend {
+			t.Fatalf("state = %v after invalid time, want StateAutoExtend", m.state)
+		}
+
+		m.autoExtendInput.SetValue("23:59")
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		until := m.autoExtendUntil(m.roles[0])
+		if m.state != StateNormal || until == nil {
+			t.Fatalf("state = %v, until = %v; want StateNormal with a rule", m.state, until)
+		}
+		if limit := time.Now().Add(m.autoExtendCap()); until.After(limit) {
+			t.Errorf("until = %v, want capped at %v", until, limit)
+		}
+
+		m, _ = update(t, m, keyRunes("A"))
+		if m.autoExtendInput.Value() != until.Format("15:04") {
+			t.Errorf("dialog value = %q, want current target %s", m.autoExtendInput.Value(), until.Format("15:04"))
+		}
+		m.autoExtendInput.SetValue("")
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.autoExtendUntil(m.roles[0]) != nil {
+			t.Error("rule still set after clearing the time")
+		}
+	})
+
+	t.Run("extends near expiry and records the outcome", func(t *testing.T) {
+		m := testModelWithItems(roles, nil, nil)
+		now := time.Now()
+		soon := now.Add(5 * time.Minute)
+		m.roles[0].ExpiresAt = &soon
+		key := itemKey(m.roles[0])
+		m.autoExtendRules[key] = &autoExtendRule{name: "Global Reader", until: now.Add(3 * time.Hour), activeSince: now}
+
+		if cmd := m.checkAutoExtend(now); cmd == nil || !m.autoExtendRules[key].inFlight {
+			t.Fatalf("checkAutoExtend() cmd = %v, inFlight = %v; want a request in flight", cmd, m.autoExtendRules[key].inFlight)
+		}
+		if cmd := m.checkAutoExtend(now.Add(2 * time.Second)); cmd != nil {
+			t.Error("checkAutoExtend() submitted a second request while one is in flight")
+		}
+
+		m, _ = update(t, m, autoExtendDoneMsg{key: key, item: m.roles[0], duration: 3 * time.Hour, err: fmt.Errorf("RoleAssignmentExists")})
+		rule := m.autoExtendRules[key]
+		if rule.inFlight || !rule.retryAt.After(now) {
+			t.Errorf("rule after failure = %+v, want a scheduled retry", rule)
+		}
+		last := m.activationHistory[len(m.activationHistory)-1]
+		if last.Kind != HistoryExtend || last.Success || last.Duration != 3*time.Hour {
+			t.Errorf("history entry = %+v, want failed extend lasting 3h", last)
+		}
+	})
+
+	t.Run("granted extension is not requested again before the refresh", func(t *testing.T) {
+		m := testModelWithItems(roles, nil, nil)
+		now := time.Now()
+		soon := now.Add(5 * time.Minute)
+		m.roles[0].ExpiresAt = &soon
+		key := itemKey(m.roles[0])
+		m.autoExtendRules[key] = &autoExtendRule{name: "Global Reader", until: now.Add(3 * time.Hour), activeSince: now}
+
+		m.checkAutoExtend(now)
+		granted := now.Add(3 * time.Hour)
+		m, _ = update(t, m, autoExtendDoneMsg{key: key, item: m.roles[0], duration: 3 * time.Hour,
+			result: azure.RequestResult{Status: "PendingApproval", ExpiresAt: &granted}})
+		if rule := m.autoExtendRules[key]; rule == nil || rule.inFlight {
+			t.Fatalf("rule after success = %+v, want kept and idle", rule)
+		}
+		if last := m.activationHistory[len(m.activationHistory)-1]; !last.Success || last.Status != "PendingApproval" {
+			t.Errorf("history entry = %+v, want successful extend", last)
+		}
+		// The list still shows the old expiry until the refresh lands
+		if cmd := m.checkAutoExtend(now.Add(10 * time.Second)); cmd != nil || m.autoExtendRules[key].inFlight {
+			t.Error("checkAutoExtend() requested the same extension again")
+		}
+
+		// Once the refresh shows the new expiry the rule is complete
+		m.roles[0].ExpiresAt = &granted
+		m.checkAutoExtend(now.Add(20 * time.Second))
+		if _, ok := m.autoExtendRules[key]; ok {
+			t.Error("rule kept after the elevation reached its target")
+		}
+	})
+
+	t.Run("rule stops when re-activation would need the user", func(t *testing.T) {
+		m := testModelWithItems(roles, nil, nil)
+		key := itemKey(m.roles[0])
+		m.autoExtendRules[key] = &autoExtendRule{name: "Global Reader", until: time.Now().Add(3 * time.Hour), inFlight: true}
+		m, _ = update(t, m, autoExtendDoneMsg{key: key, item: m.roles[0], duration: time.Hour, stop: true,
+			err: fmt.Errorf("extension refused (already active), not re-activating because it needs approval")})
+		if _, ok := m.autoExtendRules[key]; ok {
+			t.Error("rule kept after re-activation was ruled out")
+		}
+		if last := m.logs[len(m.logs)-1]; last.Level != LogError || !strings.Contains(last.Message, "Auto-extend stopped for Global Reader") {
+			t.Errorf("last log = %+v, want the stop reported", last)
+		}
+	})
+
+	t.Run("rule is dropped once the elevation ends", func(t *testing.T) {
+		m := testModelWithItems(roles, nil, nil)
+		key := itemKey(m.roles[1])
+		m.autoExtendRules[key] = &autoExtendRule{name: "Security Reader", until: time.Now().Add(time.Hour)}
+		m.checkAutoExtend(time.Now())
+		if _, ok := m.autoExtendRules[key]; ok {
+			t.Error("rule kept for an inactive elevation")
+		}
+	})
+}
+
+func TestLoadKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]map[string][]string
+		wantErr   string
+		check     func(t *testing.T, k Keys)
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, k Keys) {
+				if got := k.Normal.Deactivate.Keys(); strings.Join(got, ",") != "x,delete" {
+					t.Errorf("deactivate keys = %v, want [x delete]", got)
+				}
+			},
+		},
+		{
+			name:      "override replaces keys and keeps the description",
+			overrides: map[string]map[string][]string{"normal": {"renew": {"N"}}},
+			check: func(t *testing.T, k Keys) {
+				if got := k.Normal.Renew.Keys(); strings.Join(got, ",") != "N" {
+					t.Errorf("renew keys = %v, want [N]", got)
+				}
+				if k.Normal.Renew.Help().Desc != DefaultKeys().Normal.Renew.Help().Desc {
+					t.Errorf("renew desc = %q", k.Normal.Renew.Help().Desc)
+				}
+			},
+		},
+		{
+			name:      "empty list disables the action",
+			overrides: map[string]map[string][]string{"history": {"export": {}}},
+			check: func(t *testing.T, k Keys) {
+				if k.History.Export.Enabled() {
+					t.Error("history export still enabled")
+				}
+			},
+		},
+		{
+			name:      "backspace can deactivate once inline search deletion is moved",
+			overrides: map[string]map[string][]string{"normal": {"deactivate": {"x", "delete", "backspace"}, "search_delete": {}}},
+			check: func(t *testing.T, k Keys) {
+				if !key.Matches(tea.KeyMsg{Type: tea.KeyBackspace}, k.Normal.Deactivate) {
+					t.Error("backspace does not deactivate")
+				}
+			},
+		},
+		{
+			name:      "unknown key map and action are reported",
+			overrides: map[string]map[string][]string{"main": {"quit": {"Q"}}, "normal": {"explode": {"X"}, "quit": {"Q"}}},
+			wantErr:   `unknown key map "main"`,
+			check: func(t *testing.T, k Keys) {
+				if got := k.Normal.Quit.Keys(); strings.Join(got, ",") != "Q" {
+					t.Errorf("quit keys = %v, want [Q]; valid overrides still apply", got)
+				}
+			},
+		},
+		{
+			name:      "empty keys are rejected",
+			overrides: map[string]map[string][]string{"normal": {"renew": {"N", ""}}},
+			wantErr:   "empty key for renew in normal",
+			check: func(t *testing.T, k Keys) {
+				if got := k.Normal.Renew.Keys(); strings.Join(got, ",") != "n" {
+					t.Errorf("renew keys = %v, want defaults", got)
+				}
+			},
+		},
+		{
+			name:      "chord with the plus key",
+			overrides: map[string]map[string][]string{"normal": {"renew": {"ctrl++"}}},
+			check: func(t *testing.T, k Keys) {
+				if got := k.Normal.Renew.Help().Key; got != "Ctrl++" {
+					t.Errorf("renew help key = %q, want Ctrl++", got)
+				}
+			},
+		},
+		{
+			name:      "conflict restores the key map defaults",
+			overrides: map[string]map[string][]string{"normal": {"deactivate": {"x", "backspace"}, "renew": {"N"}}},
+			wantErr:   `"backspace" is bound to both`,
+			check: func(t *testing.T, k Keys) {
+				if got := k.Normal.Renew.Keys(); strings.Join(got, ",") != "n" {
+					t.Errorf("renew keys = %v, want defaults", got)
+				}
+			},
+		},
+		{
+			name:      "conflict with a global key",
+			overrides: map[string]map[string][]string{"history": {"close": {"ctrl+c"}}},
+			wantErr:   "global force_quit",
+			check: func(t *testing.T, k Keys) {
+				if got := k.History.Close.Keys(); strings.Join(got, ",") != "esc,H,q" {
+					t.Errorf("history close keys = %v, want defaults", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := LoadKeys(tt.overrides)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("LoadKeys() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("LoadKeys() error = %v, want %q", err, tt.wantErr)
+			}
+			tt.check(t, k)
+		})
+	}
+}
+
+func TestUpdateKeyBindings(t *testing.T) {
+
+	t.Run("backspace no longer deactivates by default", func(t *testing.T) {
+		m := testModel(StateNormal)
+		m.client = &azure.Client{}
+		m.roles = []azure.Role{{DisplayName: "Global Reader", Status: StatusActive}}
+		m.selectedRoles[itemKey(m.roles[0])] = true
+		if m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyBackspace}); m.state != StateNormal {
+			t.Errorf("state = %v, want StateNormal", m.state)
+		}
+		if m, _ = update(t, m, keyRunes("x")); m.state != StateConfirmDeactivate {
+			t.Errorf("state = %v after x, want StateConfirmDeactivate", m.state)
+		}
+	})
+
+	t.Run("overridden keys drive the handlers and help", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.KeyBindings = map[string]map[string][]string{
+			"normal": {"help": {"f1"}, "history": {"h"}, "left": {"left"}},
+			"help":   {"close": {"f1", "esc"}},
+		}
+		m := NewModel(cfg, "test")
+		m.state = StateNormal
+
+		if m, _ = update(t, m, keyRunes("?")); m.state != StateNormal {
+			t.Errorf("state = %v after ?, want StateNormal", m.state)
+		}
+		if m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyF1}); m.state != StateHelp {
+			t.Fatalf("state = %v after F1, want StateHelp", m.state)
+		}
+		if help := m.renderHelp(); !strings.Contains(help, "F1") {
+			t.Error("help screen does not list the F1 binding")
+		}
+		if m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyF1}); m.state != StateNormal {
+			t.Errorf("state = %v after closing help, want StateNormal", m.state)
+		}
+		m.remoteHistoryFetched = time.Now()
+		if m, _ = update(t, m, keyRunes("h")); m.state != StateHistory {
+			t.Errorf("state = %v after h, want StateHistory", m.state)
+		}
+	})
+
+	t.Run("inline hints follow rebound keys", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.KeyBindings = map[string]map[string][]string{"normal": {"refresh": {"f5"}, "renew": {"R"}}}
+		m := NewModel(cfg, "test")
+		m.rolesLoaded, m.rolesErr = true, fmt.Errorf("boom")
+		if got := m.renderTabPlaceholder(TabRoles, "roles"); !strings.Contains(got, "F5 to retry") {
+			t.Errorf("placeholder = %q, want the F5 retry hint", got)
+		}
+		soon := time.Now().Add(24 * time.Hour)
+		if got := m.renderEligibilityEnd(&soon); !strings.Contains(got, "R to request renewal") {
+			t.Errorf("eligibility end = %q, want the R renewal hint", got)
+		}
+	})
+
+	t.Run("esc clears an applied search", func(t *testing.T) {
+		m := testModel(StateNormal)
+		m.searchActive = true
+		m.searchQuery = "reader"
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+		if m.searchActive || m.searchQuery != "" {
+			t.Errorf("search = %q (active %v), want cleared", m.searchQuery, m.searchActive)
+		}
+	})
+}
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query, target string
+		wantMatch     bool
+	}{
+		{"read", "Global Reader", true},
+		{"glrd", "Global Reader", true},
+		{"GLOBAL", "global reader", true},
+		{"", "anything", true},
+		{"redaer", "Global Reader", false},
+		{"readers", "Global Reader", false},
+	}
+	for _, tt := range tests {
+		if _, ok := fuzzyScore(tt.query, tt.target); ok != tt.wantMatch {
+			t.Errorf("fuzzyScore(%q, %q) matched = %v, want %v", tt.query, tt.target, ok, tt.wantMatch)
+		}
+	}
+
+	// Substrings and word starts beat scattered matches
+	better := [][3]string{
+		{"read", "Reader", "Refresh and re-resolve cached names"},
+		{"read", "Global Reader", "Refresh and re-resolve cached names"},
+		{"gr", "Global Reader", "Global Administrator"},
+		{"exp", "Export history", "Request renewal of expiring eligibilities"},
+	}
+	for _, b := range better {
+		hi, _ := fuzzyScore(b[0], b[1])
+		lo, _ := fuzzyScore(b[0], b[2])
+		if hi <= lo {
+			t.Errorf("fuzzyScore(%q): %q = %d, want above %q = %d", b[0], b[1], hi, b[2], lo)
+		}
+	}
+}
+
+func TestUpdatePalette(t *testing.T) {
+	roles := []azure.Role{
+		{DisplayName: "Security Reader", RoleDefinitionID: "r1"},
+		{DisplayName: "Global Administrator", RoleDefinitionID: "r2"},
+	}
+	groups := []azure.Group{{DisplayName: "sg-ops", ID: "g1", RoleDefinitionID: "member"}}
+	subs := []azure.LighthouseSubscription{
+		{ID: "s1", DisplayName: "Dev", EligibleRoles: []azure.EligibleAzureRole{{RoleDefinitionName: "Reader", RoleDefinitionID: "d1", Scope: "/subscriptions/s1"}}},
+		{ID: "s2", DisplayName: "Prod", TenantName: "Contoso", EligibleRoles: []azure.EligibleAzureRole{
+			{RoleDefinitionName: "Reader", RoleDefinitionID: "d1", Scope: "/subscriptions/s2"},
+			{RoleDefinitionName: "Contributor", RoleDefinitionID: "d2", Scope: "/subscriptions/s2"},
+		}},
+	}
+
+	t.Run("opens with every action and item", func(t *testing.T) {
+		m, _ := update(t, testModelWithItems(roles, groups, subs), tea.KeyMsg{Type: tea.KeyCtrlP})
+		if m.state != StatePalette {
+			t.Fatalf("state = %v, want StatePalette", m.state)
+		}
+		want := len(m.paletteActions()) + 2 + 1 + 3
+		if len(m.paletteResults) != want {
+			t.Errorf("results = %d, want %d", len(m.paletteResults), want)
+		}
+		if m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEsc}); m.state != StateNormal {
+			t.Errorf("state = %v after Esc, want StateNormal", m.state)
+		}
+	})
+
+	t.Run("jumps to a subscription role matched through its detail", func(t *testing.T) {
+		m, _ := update(t, testModelWithItems(roles, groups, subs), tea.KeyMsg{Type: tea.KeyCtrlP})
+		m.searchActive, m.searchQuery = true, "dev"
+		m = typeText(t, m, "contrib prod")
+		if len(m.paletteResults) == 0 || m.paletteResults[0].title != "Contributor" {
+			t.Fatalf("top result = %+v, want Contributor", m.paletteResults)
+		}
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.state != StateNormal || m.activeTab != TabSubscriptions || m.lightCursor != 1 || !m.subRoleFocus || m.subRoleCursor != 1 {
+			t.Errorf("state = %v, tab = %v, sub = %d, focus = %v, role = %d; want Prod/Contributor focused",
+				m.state, m.activeTab, m.lightCursor, m.subRoleFocus, m.subRoleCursor)
+		}
+		if m.searchActive {
+			t.Error("search still active after jumping")
+		}
+	})
+
+	t.Run("jumps to a role on another tab", func(t *testing.T) {
+		m, _ := update(t, testModelWithItems(roles, groups, subs), tea.KeyMsg{Type: tea.KeyCtrlP})
+		m.activeTab = TabGroups
+		m = typeText(t, m, "global admin")
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.activeTab != TabRoles || m.rolesCursor != 1 {
+			t.Errorf("tab = %v, cursor = %d; want roles tab on Global Administrator", m.activeTab, m.rolesCursor)
+		}
+	})
+
+	t.Run("runs actions", func(t *testing.T) {
+		m, _ := update(t, testModelWithItems(roles, groups, subs), tea.KeyMsg{Type: tea.KeyCtrlP})
+		m = typeText(t, m, "set duration to 1h")
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.durationIndex != 0 || m.state != StateNormal {
+			t.Errorf("durationIndex = %d, state = %v; want preset 0 in StateNormal", m.durationIndex, m.state)
+		}
+
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlP})
+		m = typeText(t, m, "activation history")
+		m.remoteHistoryFetched = time.Now()
+		if m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEnter}); m.state != StateHistory {
+			t.Errorf("state = %v, want StateHistory", m.state)
+		}
+	})
+
+	t.Run("cursor stays within results", func(t *testing.T) {
+		m, _ := update(t, testModelWithItems(roles, groups, subs), tea.KeyMsg{Type: tea.KeyCtrlP})
+		m = typeText(t, m, "zzzz")
+		if len(m.paletteResults) != 0 {
+			t.Fatalf("results = %d, want none", len(m.paletteResults))
+		}
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+		if m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEnter}); m.state != StateNormal {
+			t.Errorf("state = %v, want StateNormal", m.state)
+		}
+	})
+}
+
+func TestParseSearch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  searchQuery
+	}{
+		{"", nil},
+		{"global reader", searchQuery{{value: "global"}, {value: "reader"}}},
+		{"Tenant:contoso sub:prod", searchQuery{{field: "tenant", value: "contoso"}, {field: "sub", value: "prod"}}},
+		{"subscription:prod kind:group", searchQuery{{field: "sub", value: "prod"}, {field: "type", value: "group"}}},
+		{"status: reader", searchQuery{{value: "reader"}}},
+		{"foo:bar", searchQuery{{value: "foo:bar"}}},
+	}
+	for _, tt := range tests {
+		if got := parseSearch(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSearch(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchQueryMatch(t *testing.T) {
+	sub := azure.LighthouseSubscription{ID: "s1", DisplayName: "Prod", TenantName: "Contoso"}
+	owner := subRoleSearchDoc(sub, azure.EligibleAzureRole{RoleDefinitionName: "Owner", Scope: "/subscriptions/s1", Status: azure.StatusExpiringSoon})
+	reader := subRoleSearchDoc(sub, azure.EligibleAzureRole{RoleDefinitionName: "Reader", Scope: "/subscriptions/s1"})
+	group := groupSearchDoc(azure.Group{DisplayName: "sg-owners", RoleDefinitionID: "owner", Standing: true})
+
+	tests := []struct {
+		query string
+		doc   searchDoc
+		want  bool
+	}{
+		{"tenant:contoso role:owner status:active", owner, true},
+		{"tenant:contoso role:owner status:active", reader, false},
+		{"status:expiring", owner, true},
+		{"status:inactive", reader, true},
+		{"status:standing", group, true},
+		{"type:azure", owner, true},
+		{"type:group owner", group, true},
+		{"type:role", group, false},
+		{"contoso", reader, true},
+		{"tenant:prod", reader, false},
+		{"scope:s1", reader, true},
+	}
+	for _, tt := range tests {
+		if got := parseSearch(tt.query).matches(tt.doc); got != tt.want {
+			t.Errorf("%q matches %s %q = %v, want %v", tt.query, tt.doc.kind, tt.doc.name, got, tt.want)
+		}
+	}
+
+	// A match on the name ranks above one found only in another field
+	byName, _ := parseSearch("owner").match(owner)
+	byRole, _ := parseSearch("owner").match(group)
+	if byName <= byRole {
+		t.Errorf("name match score = %d, want above role match %d", byName, byRole)
+	}
+}
+
+func TestHighlightSearchMatch(t *testing.T) {
+	plain := func(s string) string {
+		return regexp.MustCompile(`\x1b\[[0-9;]*m`).ReplaceAllString(s, "")
+	}
+
+	if got := highlightSearchMatch("Global Reader", "tenant:contoso"); got != "Global Reader" {
+		t.Errorf("field-only query highlighted %q", got)
+	}
+	if got := highlightSearchMatch("Global Reader", "zz"); got != "Global Reader" {
+		t.Errorf("non-matching query highlighted %q", got)
+	}
+	if got := highlightSearchMatch("Global Reader", "glrd status:active"); plain(got) != "Global Reader" {
+		t.Errorf("highlighting changed the text: %q", plain(got))
+	}
+}
+
+func TestUpdateSearch(t *testing.T) {
+	roles := []azure.Role{
+		{DisplayName: "Security Reader", RoleDefinitionID: "r1"},
+		{DisplayName: "Global Administrator", RoleDefinitionID: "r2", Status: azure.StatusActive},
+		{DisplayName: "Global Reader", RoleDefinitionID: "r3"},
+	}
+	groups := []azure.Group{
+		{DisplayName: "sg-readers", ID: "g1", RoleDefinitionID: "member"},
+		{DisplayName: "sg-ops", ID: "g2", RoleDefinitionID: "owner"},
+	}
+	subs := []azure.LighthouseSubscription{
+		{ID: "s1", DisplayName: "Dev", EligibleRoles: []azure.EligibleAzureRole{{RoleDefinitionName: "Contributor", Scope: "/subscriptions/s1"}}},
+		{ID: "s2", DisplayName: "Prod", TenantName: "Contoso", EligibleRoles: []azure.EligibleAzureRole{
+			{RoleDefinitionName: "Reader", Scope: "/subscriptions/s2"},
+			{RoleDefinitionName: "Owner", Scope: "/subscriptions/s2", Status: azure.StatusActive},
+		}},
+	}
+
+	t.Run("filters every tab while typing", func(t *testing.T) {
+		m, _ := update(t, testModelWithItems(roles, groups, subs), keyRunes("/"))
+		m = typeText(t, m, "reader")
+		if !reflect.DeepEqual(m.visibleRoleIndices(), []int{0, 2}) {
+			t.Errorf("visible roles = %v, want [0 2]", m.visibleRoleIndices())
+		}
+		if !reflect.DeepEqual(m.visibleGroupIndices(), []int{0}) {
+			t.Errorf("visible groups = %v, want [0]", m.visibleGroupIndices())
+		}
+		if !reflect.DeepEqual(m.getVisibleSubscriptionIndices(), []int{1}) {
+			t.Errorf("visible subscriptions = %v, want [1]", m.getVisibleSubscriptionIndices())
+		}
+		// Combined results: two roles, one group and one subscription role
+		if len(m.searchResults) != 4 {
+			t.Errorf("results = %d, want 4", len(m.searchResults))
+		}
+	})
+
+	t.Run("field prefixes narrow results", func(t *testing.T) {
+		m, _ := update(t, testModelWithItems(roles, groups, subs), keyRunes("/"))
+		m = typeText(t, m, "tenant:contoso role:owner status:active")
+		if len(m.searchResults) != 1 || m.searchResults[0].title != "Owner" {
+			t.Fatalf("results = %+v, want only Owner on Prod", m.searchResults)
+		}
+		if n := len(m.visibleRoleIndices()); n != 0 {
+			t.Errorf("visible roles = %d, want 0", n)
+		}
+	})
+
+	t.Run("enter jumps to the selected result", func(t *testing.T) {
+		m, _ := update(t, testModelWithItems(roles, groups, subs), keyRunes("/"))
+		m.activeTab = TabSubscriptions
+		m = typeText(t, m, "global")
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.state != StateNormal || m.activeTab != TabRoles {
+			t.Fatalf("state = %v, tab = %v; want roles tab in StateNormal", m.state, m.activeTab)
+		}
+		if m.rolesCursor != 2 {
+			t.Errorf("rolesCursor = %d, want 2 (Global Reader)", m.rolesCursor)
+		}
+		if !m.searchActive || m.searchQuery != "global" {
+			t.Errorf("search = %v %q, want the filter kept", m.searchActive, m.searchQuery)
+		}
+	})
+
+	t.Run("cursor moves within the filtered list", func(t *testing.T) {
+		m, _ := update(t, testModelWithItems(roles, groups, subs), keyRunes("/"))
+		m = typeText(t, m, "reader")
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+		if m.rolesCursor != 0 {
+			t.Fatalf("rolesCursor = %d, want 0", m.rolesCursor)
+		}
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+		if m.rolesCursor != 2 {
+			t.Errorf("rolesCursor = %d after down, want 2 (skipping the hidden role)", m.rolesCursor)
+		}
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+		if m.rolesCursor != 2 {
+			t.Errorf("rolesCursor = %d at the end of the list, want 2", m.rolesCursor)
+		}
+	})
+
+	t.Run("hidden cursors move onto visible items", func(t *testing.T) {
+		m, _ := update(t, testModelWithItems(roles, groups, subs), keyRunes("/"))
+		m.groupsCursor = 1
+		m.lightCursor = 0
+		m = typeText(t, m, "read")
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+		if m.groupsCursor != 0 || m.lightCursor != 1 {
+			t.Errorf("groupsCursor = %d, lightCursor = %d; want 0 and 1", m.groupsCursor, m.lightCursor)
+		}
+	})
+}
+
+func TestUpdateActiveDashboard(t *testing.T) {
+	in := func(d time.Duration) *time.Time {
+		at := time.Now().Add(d)
+		return &at
+	}
+	roles := []azure.Role{
+		{DisplayName: "Global Reader", RoleDefinitionID: "r1", Status: azure.StatusActive, ExpiresAt: in(3 * time.Hour)},
+		{DisplayName: "Security Reader", RoleDefinitionID: "r2"},
+		{DisplayName: "Global Administrator", RoleDefinitionID: "r3", Status: azure.StatusExpiringSoon, ExpiresAt: in(10 * time.Minute)},
+		{DisplayName: "Exchange Administrator", RoleDefinitionID: "r4", Status: azure.StatusPending},
+	}
+	groups := []azure.Group{{DisplayName: "sg-break-glass", ID: "g1", RoleDefinitionID: "member", Status: azure.StatusActive, Standing: true}}
+	subs := []azure.LighthouseSubscription{{ID: "s1", DisplayName: "Prod", EligibleRoles: []azure.EligibleAzureRole{
+		{RoleDefinitionName: "Contributor", RoleDefinitionID: "d1", Scope: "/subscriptions/s1", Status: azure.StatusActive, ExpiresAt: in(time.Hour)},
+		{RoleDefinitionName: "Reader", RoleDefinitionID: "d2", Scope: "/subscriptions/s1"},
+	}}}
+	names := func(entries []dashboardEntry) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, pendingItemName(e.item))
+		}
+		return out
+	}
+
+	t.Run("lists active and pending items across tabs by expiry", func(t *testing.T) {
+		m := testModelWithItems(roles, groups, subs)
+		m.activeTab = TabActive
+		want := []string{"Global Administrator", "Contributor on Prod", "Global Reader", "Exchange Administrator", "sg-break-glass"}
+		if got := names(m.dashboardEntries()); !reflect.DeepEqual(got, want) {
+			t.Errorf("entries = %v, want %v", got, want)
+		}
+
+		m.searchActive, m.searchQuery = true, "global"
+		want = []string{"Global Administrator", "Global Reader"}
+		if got := names(m.dashboardEntries()); !reflect.DeepEqual(got, want) {
+			t.Errorf("filtered entries = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("deactivates everything active when nothing is selected", func(t *testing.T) {
+		m := testModelWithItems(roles, groups, subs)
+		m.activeTab = TabActive
+		m, _ = update(t, m, keyRunes("x"))
+		// Pending requests and standing assignments cannot be deactivated
+		if m.state != StateConfirmDeactivate || len(m.pendingDeactivations) != 3 {
+			t.Errorf("state = %v, pending = %d; want StateConfirmDeactivate with 3 items", m.state, len(m.pendingDeactivations))
+		}
+	})
+
+	t.Run("selection narrows bulk actions", func(t *testing.T) {
+		m := testModelWithItems(roles, groups, subs)
+		m.activeTab = TabActive
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+		m, _ = update(t, m, keyRunes(" "))
+		m.activeCursor = 4
+		m, _ = update(t, m, keyRunes(" ")) // Standing assignments cannot be selected
+		if m.selectedActiveCount() != 1 {
+			t.Fatalf("selected = %d, want 1", m.selectedActiveCount())
+		}
+		m, _ = update(t, m, keyRunes("+"))
+		if m.state != StateExtend || len(m.pendingExtensions) != 1 || pendingItemName(m.pendingExtensions[0]) != "Contributor on Prod" {
+			t.Errorf("state = %v, pending = %v; want StateExtend for Contributor", m.state, m.pendingExtensions)
+		}
+	})
+
+	t.Run("extension needs a justification and records history", func(t *testing.T) {
+		m := testModelWithItems(roles, groups, subs)
+		m.activeTab = TabActive
+		m, _ = update(t, m, keyRunes("+"))
+		if m.state != StateExtend || len(m.pendingExtensions) != 3 {
+			t.Fatalf("state = %v, pending = %d; want StateExtend with 3 items", m.state, len(m.pendingExtensions))
+		}
+		m, _ = update(t, m, keyRunes("1"))
+		if m.durationIndex != 0 {
+			t.Errorf("durationIndex = %d, want 0", m.durationIndex)
+		}
+		if m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEnter}); m.state != StateExtend {
+			t.Fatalf("state = %v, want StateExtend without justification", m.state)
+		}
+		m, _ = update(t, m, keyRunes("incident still open"))
+		m, cmd := update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.state != StateExtending || cmd == nil {
+			t.Fatalf("state = %v, cmd = %v; want StateExtending with a command", m.state, cmd)
+		}
+
+		m, _ = update(t, m, extensionDoneMsg{results: []bulkResult{{item: m.roles[0]}}})
+		last := m.activationHistory[len(m.activationHistory)-1]
+		if m.state != StateNormal || last.Kind != HistoryExtend || last.Duration != time.Hour {
+			t.Errorf("state = %v, history = %+v; want StateNormal and a 1h extension", m.state, last)
+		}
+	})
+
+	t.Run("tab cycles through the dashboard", func(t *testing.T) {
+		m := testModelWithItems(roles, groups, subs)
+		m.activeTab = TabActive
+		m.activeTab = TabSubscriptions
+		m.lighthouse = nil
+		if m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyTab}); m.activeTab != TabActive {
+			t.Errorf("activeTab = %v, want TabActive", m.activeTab)
+		}
+	})
+}
+
+func TestBuildTimeline(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	from, to := now.Add(-timelinePast), now.Add(timelineFuture)
+	at := func(d time.Duration) time.Time { return now.Add(d) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	entries := []ActivationHistoryEntry{
+		// Extended once, then deactivated early
+		{Time: at(-10 * time.Hour), Kind: HistoryActivate, Type: "role", Name: "Global Reader", Duration: 2 * time.Hour, Success: true},
+		{Time: at(-9 * time.Hour), Kind: HistoryExtend, Type: "role", Name: "Global Reader", ExpiresAt: ptr(at(-5 * time.Hour)), Success: true},
+		{Time: at(-6 * time.Hour), Kind: HistoryDeactivate, Type: "role", Name: "Global Reader", Success: true},
+		// Still running, Azure reports the real expiry
+		{Time: at(-time.Hour), Kind: HistoryActivate, Type: "azure-role", Name: "Contributor @ Prod", Duration: 8 * time.Hour, Success: true},
+		// Ended before the window
+		{Time: at(-30 * time.Hour), Kind: HistoryActivate, Type: "group", Name: "sg-old", Duration: time.Hour, Success: true},
+		// Failed requests are ignored
+		{Time: at(-3 * time.Hour), Kind: HistoryActivate, Type: "role", Name: "Security Reader", Duration: time.Hour},
+		// No longer active according to Azure
+		{Time: at(-2 * time.Hour), Kind: HistoryActivate, Type: "role", Name: "Exchange Administrator", Duration: 4 * time.Hour, Success: true},
+		// Renewals move the eligibility end, not an elevation
+		{Time: at(-90 * time.Minute), Kind: HistoryRenew, Type: "role", Name: "Exchange Administrator", ExpiresAt: ptr(at(180 * 24 * time.Hour)), Success: true},
+		{Time: at(-5 * time.Hour), Kind: HistoryRenew, Type: "role", Name: "Billing Administrator", ExpiresAt: ptr(at(180 * 24 * time.Hour)), Success: true},
+	}
+	contributor := SubscriptionRoleActivation{SubscriptionID: "s1", SubscriptionName: "Prod", Role: azure.EligibleAzureRole{RoleDefinitionName: "Contributor", RoleDefinitionID: "d1", Scope: "/subscriptions/s1"}}
+	breakGlass := azure.Group{DisplayName: "sg-break-glass", ID: "g1", RoleDefinitionID: "member"}
+	current := []dashboardEntry{
+		{item: contributor, status: azure.StatusActive, expiresAt: ptr(at(2 * time.Hour))},
+		{item: breakGlass, status: azure.StatusActive, standing: true},
+	}
+	autoExtend := map[string]time.Time{itemKey(contributor): at(5 * time.Hour)}
+
+	rows := buildTimeline(entries, current, autoExtend, now, from, to)
+	got := make(map[string][]timelineSpan)
+	var order []string
+	for _, r := range rows {
+		got[r.name] = r.spans
+		order = append(order, r.name)
+	}
+
+	want := map[string][]timelineSpan{
+		"Global Reader":          {{start: at(-10 * time.Hour), end: at(-6 * time.Hour), kind: spanEnded}},
+		"Exchange Administrator": {{start: at(-2 * time.Hour), end: now, kind: spanEnded}},
+		"Contributor on Prod": {
+			{start: at(-time.Hour), end: at(2 * time.Hour), kind: spanActive},
+			{start: at(2 * time.Hour), end: at(5 * time.Hour), kind: spanScheduled},
+		},
+		"sg-break-glass": {{start: from, end: to, kind: spanStanding}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("spans = %+v, want %+v", got, want)
+	}
+	wantOrder := []string{"sg-break-glass", "Global Reader", "Exchange Administrator", "Contributor on Prod"}
+	if !reflect.DeepEqual(order, wantOrder) {
+		t.Errorf("rows = %v, want %v", order, wantOrder)
+	}
+}
+
+func TestTimelineConcurrency(t *testing.T) {
+	from := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return from.Add(time.Duration(h) * time.Hour) }
+	rows := []timelineRow{
+		{spans: []timelineSpan{{start: at(0), end: at(2), kind: spanEnded}, {start: at(3), end: at(4), kind: spanActive}}},
+		{spans: []timelineSpan{{start: at(1), end: at(4), kind: spanActive}, {start: at(4), end: at(5), kind: spanScheduled}}},
+		{spans: []timelineSpan{{start: at(0), end: at(6), kind: spanStanding}}},
+	}
+	want := []int{1, 2, 1, 2, 0, 0}
+	if got := timelineConcurrency(rows, at(0), at(6), 6); !reflect.DeepEqual(got, want) {
+		t.Errorf("concurrency = %v, want %v", got, want)
+	}
+}
+
+func TestUpdateTimeline(t *testing.T) {
+	m := testModel(StateNormal)
+	m.remoteHistoryFetched = time.Now()
+	m.activationHistory = []ActivationHistoryEntry{
+		{Time: time.Now().Add(-3 * time.Hour), Kind: HistoryActivate, Type: "role", Name: "Global Reader", Duration: time.Hour, Success: true},
+		{Time: time.Now().Add(-2 * time.Hour), Kind: HistoryActivate, Type: "group", Name: "sg-admins", Duration: time.Hour, Success: true},
+	}
+	m.mergeHistory()
+
+	m, _ = update(t, m, keyRunes("T"))
+	if m.state != StateTimeline {
+		t.Fatalf("state = %v, want StateTimeline", m.state)
+	}
+	m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	if m.timelineCursor != 1 {
+		t.Errorf("timelineCursor = %d, want 1", m.timelineCursor)
+	}
+	if view := m.View(); !strings.Contains(view, "sg-admins") || !strings.Contains(view, "Concurrent") {
+		t.Errorf("timeline view is missing rows:\n%s", view)
+	}
+	m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.state != StateNormal {
+		t.Errorf("state = %v, want StateNormal", m.state)
+	}
+}
+
+func TestBuildUsageStats(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	at := func(d time.Duration) time.Time { return now.Add(d) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	entries := []ActivationHistoryEntry{
+		// Two activations, one cut short by a deactivation
+		{Time: at(-60 * day), Kind: HistoryActivate, Type: "role", Name: "Global Reader", Duration: 4 * time.Hour, Success: true},
+		{Time: at(-5 * day), Kind: HistoryActivate, Type: "role", Name: "Global Reader", Duration: 4 * time.Hour, Success: true},
+		{Time: at(-5*day + time.Hour), Kind: HistoryDeactivate, Type: "role", Name: "Global Reader", Success: true},
+		// Extended past the requested duration
+		{Time: at(-10 * day), Kind: HistoryActivate, Type: "azure-role", Name: "Contributor @ Prod", Duration: time.Hour, Success: true},
+		{Time: at(-10*day + 30*time.Minute), Kind: HistoryExtend, Type: "azure-role", Name: "Contributor @ Prod", ExpiresAt: ptr(at(-10*day + 2*time.Hour)), Success: true},
+		// Outside the window, failed, or no longer eligible
+		{Time: at(-100 * day), Kind: HistoryActivate, Type: "role", Name: "Billing Administrator", Duration: time.Hour, Success: true},
+		// A renewal is not time spent elevated
+		{Time: at(-3 * day), Kind: HistoryRenew, Type: "role", Name: "Billing Administrator", ExpiresAt: ptr(at(180 * day)), Success: true},
+		{Time: at(-day), Kind: HistoryActivate, Type: "role", Name: "Billing Administrator", Duration: time.Hour},
+		{Time: at(-2 * day), Kind: HistoryActivate, Type: "group", Name: "sg-old", Duration: time.Hour, Success: true},
+	}
+	inventory := []dashboardEntry{
+		{item: azure.Role{DisplayName: "Global Reader", RoleDefinitionID: "r1"}},
+		{item: azure.Role{DisplayName: "Billing Administrator", RoleDefinitionID: "r2"}},
+		{item: azure.Group{DisplayName: "sg-break-glass", ID: "g1", RoleDefinitionID: "member"}, status: azure.StatusActive, standing: true},
+		{item: SubscriptionRoleActivation{SubscriptionID: "s1", SubscriptionName: "Prod", Role: azure.EligibleAzureRole{RoleDefinitionName: "Contributor", RoleDefinitionID: "d1"}}},
+	}
+
+	stats := buildUsageStats(entries, inventory, now)
+	type summary struct {
+		name                string
+		recent, total       int
+		elevated, requested time.Duration
+		eligible, unused    bool
+	}
+	var got []summary
+	for _, s := range stats {
+		got = append(got, summary{s.name, s.recent, s.total, s.elevated, s.requested, s.eligible, s.unused()})
+	}
+	want := []summary{
+		{"Global Reader", 1, 2, 5 * time.Hour, 8 * time.Hour, true, false},
+		{"Contributor on Prod", 1, 1, 2 * time.Hour, time.Hour, true, false},
+		{"sg-old", 1, 1, time.Hour, time.Hour, false, false},
+		{"sg-break-glass", 0, 0, 0, 0, true, false},
+		{"Billing Administrator", 0, 0, 0, 0, true, true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stats = %+v\nwant %+v", got, want)
+	}
+	if avg := stats[0].averageElevated(); avg != 150*time.Minute {
+		t.Errorf("averageElevated = %v, want 2h30m", avg)
+	}
+	if !stats[0].lastUsed.Equal(at(-5 * day)) {
+		t.Errorf("lastUsed = %v, want %v", stats[0].lastUsed, at(-5*day))
+	}
+}
+
+func TestUpdateStats(t *testing.T) {
+	m := testModel(StateNormal)
+	m.remoteHistoryFetched = time.Now()
+	m.roles = []azure.Role{{DisplayName: "Global Reader", RoleDefinitionID: "r1"}, {DisplayName: "Billing Administrator", RoleDefinitionID: "r2"}}
+	m.activationHistory = []ActivationHistoryEntry{
+		{Time: time.Now().Add(-3 * time.Hour), Kind: HistoryActivate, Type: "role", Name: "Global Reader", Duration: time.Hour, Success: true},
+	}
+	m.mergeHistory()
+
+	m, _ = update(t, m, keyRunes("U"))
+	if m.state != StateStats {
+		t.Fatalf("state = %v, want StateStats", m.state)
+	}
+	m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	if m.statsCursor != 1 {
+		t.Errorf("statsCursor = %d, want 1", m.statsCursor)
+	}
+	if view := m.View(); !strings.Contains(view, "candidate for removal") {
+		t.Errorf("stats view does not flag the unused role:\n%s", view)
+	}
+	m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.state != StateNormal {
+		t.Errorf("state = %v, want StateNormal", m.state)
+	}
+}
+
+func TestListViewArrange(t *testing.T) {
+	in := func(d time.Duration) *time.Time {
+		at := time.Now().Add(d)
+		return &at
+	}
+	items := []listItem{
+		{index: 0, doc: searchDoc{name: "Reader", tenant: "Fabrikam"}, status: azure.StatusInactive, lastUsed: time.Now().Add(-time.Hour)},
+		{index: 1, doc: searchDoc{name: "contributor", tenant: "Contoso"}, status: azure.StatusActive, expiresAt: in(2 * time.Hour)},
+		{index: 2, doc: searchDoc{name: "Owner", tenant: "Contoso"}, status: azure.StatusExpiringSoon, expiresAt: in(10 * time.Minute), lastUsed: time.Now()},
+		{index: 3, doc: searchDoc{name: "Billing", tenant: "Fabrikam"}, status: azure.StatusPending},
+	}
+
+	tests := []struct {
+		view listView
+		want []int
+	}{
+		{listView{}, []int{0, 1, 2, 3}},
+		{listView{sort: sortName}, []int{3, 1, 2, 0}},
+		{listView{sort: sortStatus}, []int{2, 1, 3, 0}},
+		{listView{sort: sortExpiry}, []int{2, 1, 0, 3}},
+		{listView{sort: sortRecent}, []int{2, 0, 1, 3}},
+		{listView{sort: sortTenant}, []int{1, 2, 3, 0}},
+		{listView{filter: filterActive}, []int{1, 2}},
+		{listView{filter: filterInactive}, []int{0}},
+		{listView{filter: filterExpiring}, []int{2}},
+		{listView{filter: filterPending}, []int{3}},
+		{listView{sort: sortName, filter: filterActive}, []int{1, 2}},
+	}
+	for _, tt := range tests {
+		if got := tt.view.arrange(items); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v: arrange = %v, want %v", tt.view, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateSortAndFilter(t *testing.T) {
+	expires := time.Now().Add(time.Hour)
+	m := testModel(StateNormal)
+	m.width, m.height = 140, 50
+	m.roles = []azure.Role{
+		{DisplayName: "Security Reader", RoleDefinitionID: "r1"},
+		{DisplayName: "Global Administrator", RoleDefinitionID: "r2", Status: azure.StatusActive, ExpiresAt: &expires},
+		{DisplayName: "Billing Administrator", RoleDefinitionID: "r3"},
+	}
+	m.lighthouse = []azure.LighthouseSubscription{
+		{ID: "s1", DisplayName: "Dev", EligibleRoles: []azure.EligibleAzureRole{{RoleDefinitionName: "Reader", RoleDefinitionID: "d1"}}},
+		{ID: "s2", DisplayName: "Prod", EligibleRoles: []azure.EligibleAzureRole{
+			{RoleDefinitionName: "Reader", RoleDefinitionID: "d1"},
+			{RoleDefinitionName: "Owner", RoleDefinitionID: "d2", Status: azure.StatusActive, ExpiresAt: &expires},
+		}},
+	}
+
+	m, _ = update(t, m, keyRunes("o")) // name
+	if got, want := m.visibleRoleIndices(), []int{2, 1, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("roles sorted by name = %v, want %v", got, want)
+	}
+	if !strings.Contains(m.View(), "[sort: name]") {
+		t.Error("panel title does not show the sort mode")
+	}
+
+	m, _ = update(t, m, keyRunes("f")) // active
+	if got, want := m.visibleRoleIndices(), []int{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("active roles = %v, want %v", got, want)
+	}
+	if m.rolesCursor != 1 {
+		t.Errorf("rolesCursor = %d, want 1 (moved off the hidden role)", m.rolesCursor)
+	}
+
+	// Each tab keeps its own view; a subscription is active when one of its roles is
+	m.activeTab = TabSubscriptions
+	if got, want := m.getVisibleSubscriptionIndices(), []int{0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("subscriptions = %v, want %v", got, want)
+	}
+	m, _ = update(t, m, keyRunes("f"))
+	if got, want := m.getVisibleSubscriptionIndices(), []int{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("active subscriptions = %v, want %v", got, want)
+	}
+	if m.listViews[TabRoles] != (listView{sort: sortName, filter: filterActive}) {
+		t.Errorf("roles view = %+v, changed by the subscriptions tab", m.listViews[TabRoles])
+	}
+}
+
+func TestUpdateFavorites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "favorites.json")
+	roles := []azure.Role{
+		{DisplayName: "Security Reader", RoleDefinitionID: "r1", DirectoryScopeID: "/"},
+		{DisplayName: "Global Reader", RoleDefinitionID: "r2", DirectoryScopeID: "/"},
+		{DisplayName: "Billing Administrator", RoleDefinitionID: "r3", DirectoryScopeID: "/"},
+	}
+	subs := []azure.LighthouseSubscription{
+		{ID: "s1", DisplayName: "Dev", EligibleRoles: []azure.EligibleAzureRole{{RoleDefinitionName: "Reader", RoleDefinitionID: "d1", Scope: "/subscriptions/s1"}}},
+		{ID: "s2", DisplayName: "Prod", EligibleRoles: []azure.EligibleAzureRole{{RoleDefinitionName: "Owner", RoleDefinitionID: "d2", Scope: "/subscriptions/s2"}}},
+	}
+	newFavoritesModel := func() Model {
+		m := testModelWithItems(roles, nil, subs)
+		m.favoritesPath = path
+		return m
+	}
+	star := keyRunes("*")
+
+	m := newFavoritesModel()
+	m.favoritesLoaded = true
+	m.rolesCursor = 2
+	m, cmd := update(t, m, star)
+	if got, want := m.visibleRoleIndices(), []int{2, 0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("roles = %v, want the favorite pinned first %v", got, want)
+	}
+	if cmd == nil {
+		t.Fatal("toggling a favorite did not save")
+	}
+	if msg, ok := cmd().(favoritesSavedMsg); !ok || msg.err != nil {
+		t.Fatalf("save = %#v, want favoritesSavedMsg without error", msg)
+	}
+
+	// Favoriting a role inside a subscription pins the subscription
+	m.activeTab = TabSubscriptions
+	m.lightCursor, m.subRoleFocus = 1, true
+	m, cmd = update(t, m, star)
+	cmd()
+	if got, want := m.getVisibleSubscriptionIndices(), []int{1, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("subscriptions = %v, want %v", got, want)
+	}
+
+	// A new session restores the favorites by stable ID, whatever the list order
+	restored := newFavoritesModel()
+	restored.roles[0], restored.roles[2] = restored.roles[2], restored.roles[0]
+	restored, _ = update(t, restored, loadFavoritesCmd(path)())
+	if got, want := restored.visibleRoleIndices(), []int{0, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("restored roles = %v, want Billing Administrator at index 0 first %v", got, want)
+	}
+	if !restored.favorites["azure-role|/subscriptions/s2|d2"] || len(restored.favorites) != 2 {
+		t.Errorf("restored favorites = %v", restored.favorites)
+	}
+
+	// Favorites only filter, and unpinning
+	restored.listViews[TabRoles].filter = filterFavorites
+	if got, want := restored.visibleRoleIndices(), []int{0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("favorite roles = %v, want %v", got, want)
+	}
+	restored.rolesCursor = 0
+	restored, _ = update(t, restored, star)
+	if len(restored.visibleRoleIndices()) != 0 || restored.favorites["role|/|r3"] {
+		t.Errorf("unpinned role still listed as favorite: %v", restored.favorites)
+	}
+
+	// Nothing is saved before the file is read, or after it failed to read
+	early := newFavoritesModel()
+	if _, cmd := update(t, early, star); cmd != nil {
+		t.Error("toggling before the favorites loaded should not save")
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	broken, _ := update(t, newFavoritesModel(), loadFavoritesCmd(path)())
+	if _, cmd := update(t, broken, star); cmd != nil {
+		t.Error("toggling after a failed load should not save")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "{not json" {
+		t.Errorf("favorites file overwritten with %q", data)
+	}
+}
+
+func TestUpdateSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	roles := []azure.Role{
+		{DisplayName: "Security Reader", RoleDefinitionID: "r1", DirectoryScopeID: "/"},
+		{DisplayName: "Global Reader", RoleDefinitionID: "r2", DirectoryScopeID: "/"},
+		{DisplayName: "Billing Administrator", RoleDefinitionID: "r3", DirectoryScopeID: "/"},
+	}
+	subs := []azure.LighthouseSubscription{
+		{ID: "s1", DisplayName: "Dev", EligibleRoles: []azure.EligibleAzureRole{{RoleDefinitionName: "Reader", RoleDefinitionID: "d1", Scope: "/subscriptions/s1"}}},
+		{ID: "s2", DisplayName: "Prod", EligibleRoles: []azure.EligibleAzureRole{
+			{RoleDefinitionName: "Reader", RoleDefinitionID: "d1", Scope: "/subscriptions/s2"},
+			{RoleDefinitionName: "Owner", RoleDefinitionID: "d2", Scope: "/subscriptions/s2"},
+		}},
+	}
+
+	// Quitting before the saved session was read must not overwrite it
+	m := testModel(StateNormal)
+	m.sessionPath = path
+	if cmd := m.quit(); cmd == nil {
+		t.Fatal("quit returned no command")
+	} else if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("quit before the session loaded should exit without saving")
+	}
+
+	m, _ = update(t, m, loadSessionCmd(path)())
+	if !m.sessionLoaded {
+		t.Fatal("a missing session file should still count as loaded")
+	}
+	m.roles, m.lighthouse = roles, subs
+	m.rolesLoaded, m.lighthouseLoaded = true, true
+	m.rolesCursor = 2
+	m.lightCursor, m.subRoleCursor, m.subRoleFocus = 1, 1, true
+	m.activeTab = TabSubscriptions
+	m.searchQuery, m.searchActive = "reader", true
+	m.durationIndex, m.duration = 2, 4*time.Hour
+	m.logLevel = LogDebug
+	m.listViews[TabRoles] = listView{sort: sortName, filter: filterActive}
+	if err := saveSession(path, m.sessionState()); err != nil {
+		t.Fatalf("saveSession: %v", err)
+	}
+
+	// The next launch restores the state and finds the items again by ID
+	restored := testModel(StateNormal)
+	restored, _ = update(t, restored, loadSessionCmd(path)())
+	if restored.activeTab != TabSubscriptions || restored.searchQuery != "reader" || !restored.searchActive {
+		t.Errorf("tab = %v, search = %q (active %v)", restored.activeTab, restored.searchQuery, restored.searchActive)
+	}
+	if restored.duration != 4*time.Hour || restored.durationIndex != 2 || restored.logLevel != LogDebug {
+		t.Errorf("duration = %v (index %d), log level = %v", restored.duration, restored.durationIndex, restored.logLevel)
+	}
+	if got, want := restored.listViews[TabRoles], (listView{sort: sortName, filter: filterActive}); got != want {
+		t.Errorf("roles view = %+v, want %+v", got, want)
+	}
+
+	reordered := []azure.Role{roles[2], roles[0], roles[1]}
+	restored, _ = update(t, restored, rolesLoadedMsg{roles: reordered})
+	if restored.rolesCursor != 0 {
+		t.Errorf("rolesCursor = %d, want 0 (Billing Administrator after reordering)", restored.rolesCursor)
+	}
+	restored, _ = update(t, restored, lighthouseLoadedMsg{subs: []azure.LighthouseSubscription{subs[1], subs[0]}})
+	if sub := restored.getCurrentSubscription(); sub == nil || sub.ID != "s2" || !restored.subRoleFocus || restored.subRoleCursor != 1 {
+		t.Errorf("lightCursor = %d, subRoleCursor = %d (focus %v), want Prod / Owner",
+			restored.lightCursor, restored.subRoleCursor, restored.subRoleFocus)
+	}
+	if _, pending := restored.pendingCursors[TabRoles]; pending {
+		t.Error("restored roles cursor still pending")
+	}
+}
+
+func TestUpdateSelectionsAcrossRefresh(t *testing.T) {
+	r1 := azure.Role{DisplayName: "Security Reader", RoleDefinitionID: "r1", DirectoryScopeID: "/"}
+	r2 := azure.Role{DisplayName: "Global Reader", RoleDefinitionID: "r2", DirectoryScopeID: "/"}
+	r3 := azure.Role{DisplayName: "Billing Administrator", RoleDefinitionID: "r3", DirectoryScopeID: "/"}
+	reader := azure.EligibleAzureRole{RoleDefinitionName: "Reader", RoleDefinitionID: "d1", Scope: "/subscriptions/s1"}
+	owner := azure.EligibleAzureRole{RoleDefinitionName: "Owner", RoleDefinitionID: "d2", Scope: "/subscriptions/s1"}
+
+	m := testModel(StateNormal)
+	m.roles = []azure.Role{r1, r2, r3}
+	m.lighthouse = []azure.LighthouseSubscription{{ID: "s1", DisplayName: "Dev", EligibleRoles: []azure.EligibleAzureRole{reader, owner}}}
+	m.selectedRoles[itemKey(r2)] = true
+	m.selectedRoles[itemKey(r3)] = true
+	m.rolesCursor = 2
+	m.lightCursor, m.subRoleCursor, m.subRoleFocus = 0, 1, true
+	m.selectedSubRoles["s1"] = map[string]bool{subRoleKey(m.lighthouse[0], owner): true}
+
+	// A refresh reorders the roles and drops Global Reader
+	m, _ = update(t, m, rolesLoadedMsg{roles: []azure.Role{r3, r1}})
+	if got, want := m.selectedRoles, map[string]bool{itemKey(r3): true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("selectedRoles = %v, want %v", got, want)
+	}
+	if m.rolesCursor != 0 {
+		t.Errorf("rolesCursor = %d, want 0 (still on Billing Administrator)", m.rolesCursor)
+	}
+	if !strings.Contains(m.logs[len(m.logs)-1].Message, "Deselected 1 item") {
+		t.Errorf("last log = %q, want the dropped selection reported", m.logs[len(m.logs)-1].Message)
+	}
+	m.activeTab = TabRoles
+	m.initiateActivation()
+	if len(m.pendingActivations) != 1 || m.pendingActivations[0].(azure.Role).RoleDefinitionID != "r3" {
+		t.Errorf("pendingActivations = %v, want only Billing Administrator", m.pendingActivations)
+	}
+
+	// The selected subscription role moves up once Reader is gone
+	m, _ = update(t, m, lighthouseLoadedMsg{subs: []azure.LighthouseSubscription{{ID: "s1", DisplayName: "Dev", EligibleRoles: []azure.EligibleAzureRole{owner}}}})
+	if !m.selectedSubRoles["s1"][subRoleKey(m.lighthouse[0], owner)] || m.subRoleCursor != 0 || !m.subRoleFocus {
+		t.Errorf("selectedSubRoles = %v, subRoleCursor = %d; want Owner kept under the cursor", m.selectedSubRoles, m.subRoleCursor)
+	}
+
+	// A subscription that disappears takes its selections with it
+	m, _ = update(t, m, lighthouseLoadedMsg{})
+	if len(m.selectedSubRoles) != 0 || m.subRoleFocus {
+		t.Errorf("selectedSubRoles = %v (focus %v), want none", m.selectedSubRoles, m.subRoleFocus)
+	}
+}
+
+func TestUpdateStandingAndEligibleSameRole(t *testing.T) {
+	eligible := azure.Role{DisplayName: "Global Reader", RoleDefinitionID: "r1", DirectoryScopeID: "/"}
+	standing := azure.Role{DisplayName: "Global Reader", RoleDefinitionID: "r1", DirectoryScopeID: "/", Status: azure.StatusActive, Standing: true}
+	if itemKey(eligible) == itemKey(standing) {
+		t.Fatalf("eligible and standing assignment share the key %q", itemKey(eligible))
+	}
+	reader := azure.EligibleAzureRole{RoleDefinitionName: "Reader", RoleDefinitionID: "d1", Scope: "/subscriptions/s1"}
+	standingReader := reader
+	standingReader.Standing = true
+	sub := azure.LighthouseSubscription{ID: "s1", DisplayName: "Dev", EligibleRoles: []azure.EligibleAzureRole{reader, standingReader}}
+
+	m := testModelWithItems([]azure.Role{eligible, standing}, nil, []azure.LighthouseSubscription{sub})
+	m, _ = update(t, m, keyRunes(" "))
+	// A standing key left over from an older selection is never activated either
+	m.selectedRoles[itemKey(standing)] = true
+	m.initiateActivation()
+	if len(m.pendingActivations) != 1 || m.pendingActivations[0].(azure.Role).Standing {
+		t.Errorf("pendingActivations = %+v, want only the eligible role", m.pendingActivations)
+	}
+
+	m.activeTab = TabSubscriptions
+	m.selectedSubRoles["s1"] = map[string]bool{subRoleKey(sub, reader): true, subRoleKey(sub, standingReader): true}
+	m.initiateActivation()
+	if len(m.pendingActivations) != 1 || m.pendingActivations[0].(SubscriptionRoleActivation).Role.Standing {
+		t.Errorf("pendingActivations = %+v, want only the eligible Azure role", m.pendingActivations)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/seb07-cloud/pim-tui/internal/azure"
+	"github.com/seb07-cloud/pim-tui/internal/export"
 )
 
 const asciiLogo = ` ██████╗ ██╗███╗   ███╗    ████████╗██╗   ██╗██╗
@@ -54,10 +56,34 @@ func (m Model) View() string {
 		sections = append(sections, m.renderJustification())
 	case StateActivating:
 		sections = append(sections, m.renderActivating())
+	case StateStepUp:
+		sections = append(sections, m.renderStepUp())
 	case StateDeactivating:
 		sections = append(sections, m.renderDeactivating())
+	case StateRenew:
+		sections = append(sections, m.renderRenew())
+	case StateRenewing:
+		sections = append(sections, m.renderRenewing())
+	case StateAutoExtend:
+		sections = append(sections, m.renderAutoExtend())
+	case StateExtend:
+		sections = append(sections, m.renderExtend())
+	case StateExtending:
+		sections = append(sections, m.renderExtending())
+	case StateResults:
+		sections = append(sections, m.renderResults())
+	case StateHistory:
+		sections = append(sections, m.renderHistory())
+	case StateTimeline:
+		sections = append(sections, m.renderTimeline())
+	case StateStats:
+		sections = append(sections, m.renderStats())
+	case StateExport:
+		sections = append(sections, m.renderExport())
 	case StateSearch:
 		sections = append(sections, m.renderSearch())
+	case StatePalette:
+		sections = append(sections, m.renderPalette())
 	default:
 		sections = append(sections, m.renderMainView())
 	}
@@ -88,8 +114,8 @@ func (m Model) renderLoading() string {
 		{"Authenticating with Graph API...", authDone},
 		{"Loading Tenant Information", tenantDone},
 		{"Loading PIM roles", tenantDone && m.rolesLoaded},
-		{"Loading PIM groups", tenantDone && m.groupsLoaded},
-		{"Loading Subscriptions", tenantDone && m.lighthouseLoaded},
+		{"Loading PIM groups" + m.progressSuffix("group names"), tenantDone && m.groupsLoaded},
+		{"Loading Subscriptions" + m.progressSuffix("subscriptions", "tenant names"), tenantDone && m.lighthouseLoaded},
 	}
 
 	// Determine active step: first incomplete step gets the spinner
@@ -155,6 +181,16 @@ func (m Model) renderLoading() string {
 		lipgloss.JoinVertical(lipgloss.Center, contentParts...))
 }
 
+// progressSuffix describes the first unfinished fan-out stage, e.g. " (group names 12/400)"
+func (m Model) progressSuffix(stages ...string) string {
+	for _, stage := range stages {
+		if p, ok := m.loadProgress[stage]; ok && p.total > 0 && p.done < p.total {
+			return fmt.Sprintf(" (%s %d/%d)", stage, p.done, p.total)
+		}
+	}
+	return ""
+}
+
 func (m Model) renderError() string {
 	// Build troubleshooting tips based on error type
 	var tips string
@@ -187,7 +223,7 @@ func (m Model) renderError() string {
 		"",
 		tips,
 		"",
-		activeStyle.Render(" [R] Retry ")+"  "+dimStyle.Render(" [Q] Quit "),
+		activeStyle.Render(" "+keyButton(m.keys.Session.Retry, "Retry")+" ")+"  "+dimStyle.Render(" "+keyButton(m.keys.Session.Quit, "Quit")+" "),
 	)
 
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
@@ -211,7 +247,7 @@ func (m Model) renderUnauthenticated() string {
 			"",
 			dimStyle.Render("Complete sign-in in your browser window."),
 			"",
-			dimStyle.Render("[Esc] Cancel")+"    "+dimStyle.Render("[Q] Quit"),
+			dimStyle.Render(keyButton(m.keys.Session.Cancel, "Cancel"))+"    "+dimStyle.Render(keyButton(m.keys.Session.Quit, "Quit")),
 		)
 	} else {
 		contentParts = append(contentParts,
@@ -219,7 +255,7 @@ func (m Model) renderUnauthenticated() string {
 			"",
 			dimStyle.Render("No Azure CLI session found."),
 			"",
-			activeStyle.Render("[L] Login with Browser")+"    "+dimStyle.Render("[Q] Quit"),
+			activeStyle.Render(keyButton(m.keys.Session.Login, "Login with Browser"))+"    "+dimStyle.Render(keyButton(m.keys.Session.Quit, "Quit")),
 		)
 	}
 
@@ -310,7 +346,13 @@ func (m Model) renderHeader() string {
 
 	// Refresh state
 	var refreshStr string
-	if m.autoRefresh {
+	if m.isStale() && !m.snapshotTime.IsZero() && m.refreshFailed() {
+		refreshStr = lipgloss.NewStyle().Foreground(colorError).Render(
+			fmt.Sprintf("⚠ Cached (%s ago) - refresh failed", formatDuration(time.Since(m.snapshotTime))))
+	} else if m.isStale() && !m.snapshotTime.IsZero() {
+		refreshStr = lipgloss.NewStyle().Foreground(colorExpiring).Render(
+			fmt.Sprintf("⟳ Cached (%s ago) - refreshing...", formatDuration(time.Since(m.snapshotTime))))
+	} else if m.autoRefresh {
 		if secs, ok := m.refreshCountdown(); ok {
 			refreshStr = activeBoldStyle.Render(fmt.Sprintf("↻ Auto (%ds)", secs))
 		} else {
@@ -357,6 +399,50 @@ func (m Model) renderHeader() string {
 	return infoBox
 }
 
+// tabBadge returns a spinner while a tab is loading or a warning badge if its last load failed
+func (m Model) tabBadge(tab Tab) string {
+	loading, err := m.tabLoadState(tab)
+	if loading {
+		return " " + spinner(colorPending)
+	}
+	if err != nil {
+		return " " + errorBoldStyle.Render("⚠")
+	}
+	return ""
+}
+
+// renderTabPlaceholder replaces an empty list while its tab is loading or failed to load
+func (m Model) renderTabPlaceholder(tab Tab, itemType string, stages ...string) string {
+	loading, err := m.tabLoadState(tab)
+	if loading {
+		return lipgloss.JoinVertical(lipgloss.Center,
+			"",
+			spinner(colorActive)+detailValueStyle.Render(fmt.Sprintf(" Loading %s...", itemType)),
+			dimStyle.Render(strings.TrimPrefix(m.progressSuffix(stages...), " ")),
+		)
+	}
+	if err != nil {
+		return lipgloss.JoinVertical(lipgloss.Center,
+			"",
+			errorBoldStyle.Render(fmt.Sprintf("⚠ Failed to load %s", itemType)),
+			dimStyle.Render(truncate(err.Error(), max(m.listPanelWidth()-4, 10))),
+			dimStyle.Render(keyHint("to retry", m.keys.Normal.Refresh)),
+		)
+	}
+	return ""
+}
+
+// renderFilteredEmpty replaces a list whose status filter hides every item
+func (m Model) renderFilteredEmpty(itemType string) string {
+	return lipgloss.JoinVertical(lipgloss.Center,
+		"",
+		dimStyle.Render("🔍"),
+		// "No favorite roles" rather than "No favorites roles"
+		dimStyle.Render(fmt.Sprintf("No %s %s", strings.TrimSuffix(m.listViews[m.activeTab].filter.String(), "s"), itemType)),
+		dimStyle.Render(keyHint("to change the filter", m.keys.Normal.Filter)),
+	)
+}
+
 func (m Model) renderMainView() string {
 	tabBar := m.renderTabBar()
 
@@ -372,10 +458,20 @@ func (m Model) renderMainView() string {
 	case TabRoles:
 		title = "🔐 PIM Roles"
 		listContent = m.renderRolesList(panelHeight - 2)
+		if len(m.roles) == 0 {
+			if placeholder := m.renderTabPlaceholder(TabRoles, "roles"); placeholder != "" {
+				listContent = placeholder
+			}
+		}
 		detailContent = m.renderRoleDetail()
 	case TabGroups:
 		title = "👥 PIM Groups"
 		listContent = m.renderGroupsList(panelHeight - 2)
+		if len(m.groups) == 0 {
+			if placeholder := m.renderTabPlaceholder(TabGroups, "groups", "group names"); placeholder != "" {
+				listContent = placeholder
+			}
+		}
 		detailContent = m.renderGroupDetail()
 	case TabSubscriptions:
 		title = "📑 Subscriptions"
@@ -384,8 +480,17 @@ func (m Model) renderMainView() string {
 			title = fmt.Sprintf("📑 Subscriptions [🔍 %s]", m.searchQuery)
 		}
 		listContent = m.renderSubscriptionsList(max(panelHeight-2, 1))
+		if len(m.lighthouse) == 0 {
+			if placeholder := m.renderTabPlaceholder(TabSubscriptions, "subscriptions", "subscriptions", "tenant names"); placeholder != "" {
+				listContent = placeholder
+			}
+		}
 		detailContent = m.renderSubscriptionDetail()
+	case TabActive:
+		title = "⚡ Active now"
+		listContent = m.renderActiveList(totalWidth, panelHeight-2)
 	}
+	title += m.listViews[m.activeTab].label()
 
 	// Prominent panel title with background
 	prominentTitle := lipgloss.NewStyle().
@@ -395,6 +500,12 @@ func (m Model) renderMainView() string {
 		Padding(0, 2).
 		Render(title)
 
+	// The dashboard has no detail panel; its rows carry everything
+	if m.activeTab == TabActive {
+		panel := activePanelStyle.Width(totalWidth + 2).Height(panelHeight).Render(prominentTitle + "\n" + listContent)
+		return lipgloss.JoinVertical(lipgloss.Left, tabBar, panel)
+	}
+
 	listPanel := activePanelStyle.Width(listPanelWidth).Height(panelHeight).Render(
 		prominentTitle + "\n" + listContent,
 	)
@@ -451,10 +562,13 @@ func (m Model) renderTabBar() string {
 		subsLabel = fmt.Sprintf("📑 Subs (%d) %s", len(m.lighthouse), activeStyle.Render(fmt.Sprintf("●%d", activeSubs)))
 	}
 
+	activeLabel := fmt.Sprintf("⚡ Active now (%d)", len(m.collectDashboard(nil)))
+
 	tabs := lipgloss.JoinHorizontal(lipgloss.Bottom,
-		tabStyle(m.activeTab == TabRoles).Render(rolesLabel), " ",
-		tabStyle(m.activeTab == TabGroups).Render(groupsLabel), " ",
-		tabStyle(m.activeTab == TabSubscriptions).Render(subsLabel),
+		tabStyle(m.activeTab == TabRoles).Render(rolesLabel+m.tabBadge(TabRoles)), " ",
+		tabStyle(m.activeTab == TabGroups).Render(groupsLabel+m.tabBadge(TabGroups)), " ",
+		tabStyle(m.activeTab == TabSubscriptions).Render(subsLabel+m.tabBadge(TabSubscriptions)), " ",
+		tabStyle(m.activeTab == TabActive).Render(activeLabel+m.tabBadge(TabActive)),
 	)
 
 	// Add full-width underline indicator for active tab
@@ -487,6 +601,14 @@ func (m Model) renderRoleDetail() string {
 	lines = append(lines, detailTitleStyle.Render("━━━ 🔐 Role Details ━━━"), "")
 	lines = append(lines, detailLabelStyle.Render("Name: ")+detailValueStyle.Render(role.DisplayName))
 	lines = append(lines, detailLabelStyle.Render("Status: ")+statusIcon(role.Status)+" "+role.Status.String())
+	if role.Standing {
+		lines = append(lines, standingNotice()...)
+	} else {
+		lines = append(lines, m.renderEligibilityEnd(role.EligibleUntil))
+	}
+	if until := m.autoExtendUntil(role); until != nil {
+		lines = append(lines, renderAutoExtendTarget(*until))
+	}
 
 	// Enhanced expiry display with progress bar
 	if role.ExpiresAt != nil {
@@ -520,6 +642,22 @@ func (m Model) renderRoleDetail() string {
 	return strings.Join(lines, "\n")
 }
 
+// renderAutoExtendTarget shows an auto-extend rule in the detail panels
+func renderAutoExtendTarget(until time.Time) string {
+	return detailLabelStyle.Render("Auto-extend: ") +
+		lipgloss.NewStyle().Foreground(colorPending).Render(iconAutoExtend+" until "+until.Format("15:04")) +
+		detailDimStyle.Render(" (A to change)")
+}
+
+// standingNotice explains a standing assignment in the detail panels
+func standingNotice() []string {
+	return []string{
+		detailLabelStyle.Render("Access: ") + lipgloss.NewStyle().Foreground(colorWarning).Render(iconStanding+" Standing (permanent)"),
+		detailDimStyle.Render("  Not managed by PIM activation - consider"),
+		detailDimStyle.Render("  converting it to an eligible assignment"),
+	}
+}
+
 func (m Model) renderGroupDetail() string {
 	if len(m.groups) == 0 || m.groupsCursor >= len(m.groups) {
 		return lipgloss.JoinVertical(lipgloss.Center,
@@ -546,6 +684,14 @@ func (m Model) renderGroupDetail() string {
 	}
 
 	lines = append(lines, detailLabelStyle.Render("Status: ")+statusIcon(group.Status)+" "+group.Status.String())
+	if group.Standing {
+		lines = append(lines, standingNotice()...)
+	} else {
+		lines = append(lines, m.renderEligibilityEnd(group.EligibleUntil))
+	}
+	if until := m.autoExtendUntil(group); until != nil {
+		lines = append(lines, renderAutoExtendTarget(*until))
+	}
 
 	// Enhanced expiry display with progress bar
 	if group.ExpiresAt != nil {
@@ -588,9 +734,19 @@ func (m Model) renderGroupDetail() string {
 }
 
 func (m Model) renderRolesList(height int) string {
-	return m.renderItemListWithExpiry(height, "roles", len(m.roles), m.rolesScrollOffset, func(i int) (string, azure.ActivationStatus, bool, bool, *time.Time) {
+	return m.renderItemListWithExpiry(height, "roles", len(m.roles), m.visibleRoleIndices(), m.rolesScrollOffset, func(i int) listEntry {
 		role := m.roles[i]
-		return role.DisplayName, role.Status, m.selectedRoles[i], i == m.rolesCursor && m.activeTab == TabRoles, role.ExpiresAt
+		return listEntry{
+			name:          role.DisplayName,
+			status:        role.Status,
+			selected:      m.selectedRoles[itemKey(role)],
+			isCursor:      i == m.rolesCursor && m.activeTab == TabRoles,
+			expiresAt:     role.ExpiresAt,
+			standing:      role.Standing,
+			eligibleUntil: role.EligibleUntil,
+			autoExtend:    m.autoExtendUntil(role),
+			favorite:      m.favorites[itemKey(role)],
+		}
 	})
 }
 
@@ -628,9 +784,19 @@ func (m Model) renderListItem(idx int, name string, status azure.ActivationStatu
 }
 
 func (m Model) renderGroupsList(height int) string {
-	return m.renderItemListWithExpiry(height, "groups", len(m.groups), m.groupsScrollOffset, func(i int) (string, azure.ActivationStatus, bool, bool, *time.Time) {
+	return m.renderItemListWithExpiry(height, "groups", len(m.groups), m.visibleGroupIndices(), m.groupsScrollOffset, func(i int) listEntry {
 		group := m.groups[i]
-		return group.DisplayName, group.Status, m.selectedGroups[i], i == m.groupsCursor && m.activeTab == TabGroups, group.ExpiresAt
+		return listEntry{
+			name:          group.DisplayName,
+			status:        group.Status,
+			selected:      m.selectedGroups[itemKey(group)],
+			isCursor:      i == m.groupsCursor && m.activeTab == TabGroups,
+			expiresAt:     group.ExpiresAt,
+			standing:      group.Standing,
+			eligibleUntil: group.EligibleUntil,
+			autoExtend:    m.autoExtendUntil(group),
+			favorite:      m.favorites[itemKey(group)],
+		}
 	})
 }
 
@@ -645,27 +811,7 @@ func (m Model) renderSubscriptionsList(height int) string {
 	}
 
 	// Filter subscriptions based on search query
-	visibleIndices := make([]int, 0, len(m.lighthouse))
-	for i, sub := range m.lighthouse {
-		if m.searchActive && m.searchQuery != "" {
-			// Search in subscription name, tenant name, and role names
-			query := strings.ToLower(m.searchQuery)
-			match := strings.Contains(strings.ToLower(sub.DisplayName), query) ||
-				strings.Contains(strings.ToLower(sub.TenantName), query)
-			if !match {
-				for _, role := range sub.EligibleRoles {
-					if strings.Contains(strings.ToLower(role.RoleDefinitionName), query) {
-						match = true
-						break
-					}
-				}
-			}
-			if !match {
-				continue
-			}
-		}
-		visibleIndices = append(visibleIndices, i)
-	}
+	visibleIndices := m.getVisibleSubscriptionIndices()
 
 	if len(visibleIndices) == 0 && m.searchActive {
 		return lipgloss.JoinVertical(lipgloss.Center,
@@ -675,6 +821,9 @@ func (m Model) renderSubscriptionsList(height int) string {
 			dimStyle.Render("Try a different search term"),
 		)
 	}
+	if len(visibleIndices) == 0 {
+		return m.renderFilteredEmpty("subscriptions")
+	}
 
 	// Find cursor position in visible list (for position indicator)
 	cursorVisibleIdx := 0
@@ -805,7 +954,7 @@ func (m Model) renderSubscriptionItem(idx int, sub azure.LighthouseSubscription)
 		indicator = dimStyle.Render(fmt.Sprintf(" [%d]", totalRoles))
 	}
 
-	line := fmt.Sprintf("%s %s%s", statusIcon(subStatus), truncate(sub.DisplayName, 26), indicator)
+	line := fmt.Sprintf("%s %s%s%s", statusIcon(subStatus), favoriteMark(m.subscriptionFavorite(sub)), truncate(sub.DisplayName, 26), indicator)
 
 	if idx == m.lightCursor {
 		// Highlighted cursor style matching the color scheme
@@ -863,14 +1012,11 @@ func (m Model) renderSubscriptionDetail() string {
 	} else {
 		// Get selected roles for this subscription
 		selectedRoles := m.selectedSubRoles[sub.ID]
-		if selectedRoles == nil {
-			selectedRoles = make(map[int]bool)
-		}
 
 		for i, role := range sub.EligibleRoles {
 			// Checkbox for selection
 			checkbox := dimStyle.Render(checkboxUnchecked)
-			if selectedRoles[i] {
+			if selectedRoles[subRoleKey(*sub, role)] {
 				checkbox = highlightBoldStyle.Render(checkboxChecked)
 			}
 
@@ -890,7 +1036,16 @@ func (m Model) renderSubscriptionDetail() string {
 			}
 
 			// Build the line
-			line := fmt.Sprintf("%s%s %s %s", cursorPrefix, checkbox, roleStatus, roleName)
+			favorite := m.favorites[itemKey(SubscriptionRoleActivation{SubscriptionID: sub.ID, Role: role})]
+			line := fmt.Sprintf("%s%s %s %s%s", cursorPrefix, checkbox, roleStatus, favoriteMark(favorite), roleName)
+			if role.Standing {
+				line += " " + lipgloss.NewStyle().Foreground(colorWarning).Render(iconStanding+" standing")
+			} else if azure.EligibilityExpiringWithin(role.EligibleUntil, m.eligibilityWarnWindow()) {
+				line += " " + lipgloss.NewStyle().Foreground(colorCritical).Render(iconEligibilityEnding+" eligible "+formatEligibilityLeft(*role.EligibleUntil))
+			}
+			if until := m.autoExtendUntil(SubscriptionRoleActivation{SubscriptionID: sub.ID, Role: role}); until != nil {
+				line += " " + lipgloss.NewStyle().Foreground(colorPending).Render(iconAutoExtend+until.Format("15:04"))
+			}
 
 			// Apply cursor style if focused
 			if m.subRoleFocus && i == m.subRoleCursor {
@@ -921,6 +1076,113 @@ func (m Model) renderSubscriptionDetail() string {
 	return strings.Join(lines, "\n")
 }
 
+// renderActiveList renders the Active now tab: one row per active or pending
+// elevation with the time left and a progress bar, and the bulk action hints
+func (m Model) renderActiveList(width, height int) string {
+	entries := m.dashboardEntries()
+	if len(entries) == 0 {
+		if m.searchActive {
+			return lipgloss.JoinVertical(lipgloss.Center,
+				"",
+				dimStyle.Render("🔍"),
+				dimStyle.Render(fmt.Sprintf("No active items match \"%s\"", m.searchQuery)),
+				dimStyle.Render("Try a different search term"),
+			)
+		}
+		if placeholder := m.renderTabPlaceholder(TabActive, "elevations", "roles", "groups", "subscriptions"); placeholder != "" {
+			return placeholder
+		}
+		if m.listViews[TabActive].filter != filterAll && len(m.collectDashboard(nil)) > 0 {
+			return m.renderFilteredEmpty("items")
+		}
+		return lipgloss.JoinVertical(lipgloss.Center,
+			"",
+			dimStyle.Render("⚡"),
+			dimStyle.Render("Nothing is active right now"),
+			dimStyle.Render("Activate roles, groups or subscription roles from the other tabs"),
+		)
+	}
+
+	// Bulk actions apply to the selection, or to everything listed
+	keys := m.keys.Normal
+	target := "all"
+	if m.selectedActiveCount() > 0 {
+		target = "selected"
+	}
+	actions := dimStyle.Render(keyHints(
+		keyHint(fmt.Sprintf("extend %s by %s", target, m.durationStr()), keys.Extend),
+		keyHint("deactivate "+target, keys.Deactivate),
+		keyHint("auto-extend "+target, keys.AutoExtend),
+	))
+
+	displayHeight := max(height-2, 1) // Reserve lines for the actions and scroll indicator
+	start := 0
+	if len(entries) > displayHeight {
+		start = min(max(m.activeScrollOffset, 0), len(entries)-displayHeight)
+	}
+	end := min(start+displayHeight, len(entries))
+
+	lines := []string{actions}
+	for i := start; i < end; i++ {
+		e := entries[i]
+		lines = append(lines, m.renderActiveRow(e, width, m.selectedActive[e.key()], i == m.activeCursor))
+	}
+	if len(entries) > displayHeight {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("  ↕ %d/%d", m.activeCursor+1, len(entries))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderActiveRow renders one dashboard row as checkbox, status, kind, name,
+// time left and progress bar, then the auto-extend target if any
+func (m Model) renderActiveRow(e dashboardEntry, width int, selected, isCursor bool) string {
+	const kindWidth, leftWidth, barWidth = 10, 10, 16
+
+	var left, bar string
+	switch {
+	case e.standing:
+		left = lipgloss.NewStyle().Foreground(colorWarning).Render(iconStanding + " standing")
+	case e.status == azure.StatusPending:
+		left = lipgloss.NewStyle().Foreground(colorPending).Render("pending")
+		bar = renderProgressBar(0, 1, barWidth)
+	case e.expiresAt == nil:
+		left = dimStyle.Render("no end")
+	default:
+		remaining := time.Until(*e.expiresAt)
+		left = expiryStyle(remaining).Render(formatCompactDuration(max(remaining, 0)))
+		bar = renderProgressBar(remaining.Seconds(), m.policyMaxDuration(e.item).Seconds(), barWidth)
+	}
+	left = strings.Repeat(" ", max(leftWidth-lipgloss.Width(left), 0)) + left
+	bar += strings.Repeat(" ", barWidth-lipgloss.Width(bar))
+
+	var extend string
+	if until := m.autoExtendUntil(e.item); until != nil {
+		extend = " " + lipgloss.NewStyle().Foreground(colorPending).Render(iconAutoExtend+until.Format("15:04"))
+	}
+
+	// checkbox, icon, kind, left and bar columns with their separating spaces
+	nameWidth := max(width-(3+1+1+1+kindWidth+1+1+leftWidth+1+barWidth+7), 10)
+	favorite := m.favorites[e.key()]
+	if favorite {
+		nameWidth -= 2
+	}
+	name := truncate(pendingItemName(e.item), nameWidth)
+	padding := strings.Repeat(" ", nameWidth-lipgloss.Width(name))
+	if m.searchActive && m.searchQuery != "" {
+		name = highlightSearchMatch(name, m.searchQuery)
+	}
+	name = favoriteMark(favorite) + name
+
+	line := fmt.Sprintf("%s %s %s %s%s %s %s%s",
+		renderCheckbox(selected), statusIcon(e.status),
+		dimStyle.Render(fmt.Sprintf("%-*s", kindWidth, e.kind)),
+		name, padding, left, bar, extend)
+	if isCursor {
+		return cursorStyle.Render(line)
+	}
+	return line
+}
+
 func (m Model) renderLogs() string {
 	logHeight := 8
 	// Match the width of two side-by-side panels in header/main view
@@ -1044,6 +1306,8 @@ func (m Model) renderStatusBar() string {
 		for _, roleSelections := range m.selectedSubRoles {
 			selected += len(roleSelections)
 		}
+	case TabActive:
+		selected = m.selectedActiveCount()
 	}
 	var selectStr string
 	if selected > 0 {
@@ -1052,6 +1316,11 @@ func (m Model) renderStatusBar() string {
 		selectStr = dimStyle.Render("✓ 0 selected")
 	}
 
+	// Standing access indicator
+	if m.config.ShowStanding {
+		activeStr += "  " + lipgloss.NewStyle().Foreground(colorWarning).Render(fmt.Sprintf("%s %d standing", iconStanding, m.countStandingItems()))
+	}
+
 	// Search indicator
 	var searchStr string
 	if m.searchActive {
@@ -1063,52 +1332,72 @@ func (m Model) renderStatusBar() string {
 		durationDisplay, autoStr, activeStr, selectStr, searchStr)
 
 	// Context-aware help hints
-	helpHints := dimStyle.Render("←→ tabs │ ↑↓ navigate │ Tab switch │ Space select │ Enter activate │ / search │ ? help")
+	keys := m.keys.Normal
+	action := keyHint("activate", keys.Activate)
+	if m.activeTab == TabActive {
+		action = keyHints(keyHint("extend", keys.Extend), keyHint("deactivate", keys.Deactivate))
+	}
+	helpHints := dimStyle.Render(keyHints(
+		keyHint("tabs", keys.Left, keys.Right),
+		keyHint("navigate", keys.Up, keys.Down),
+		keyHint("switch", keys.NextTab),
+		keyHint("select", keys.Select),
+		action,
+		keyHint("search", keys.Search),
+		keyHint("help", keys.Help),
+	))
 
 	return helpStyle.Width(m.width - 2).Render(statusLine + "\n" + helpHints)
 }
 
 func (m Model) renderHelp() string {
-	// Build dynamic duration help based on config
-	durationHelp := ""
-	for i, preset := range m.config.DurationPresets {
-		if i < 4 {
-			durationHelp += fmt.Sprintf("    %d              Set duration to %d hour(s)\n", i+1, preset)
+	groups := m.keys.Normal.FullHelp()
+
+	// Duration presets come from config, so describe them with the configured hours
+	durations := make([]key.Binding, 0, len(groups[3]))
+	for i, b := range groups[3] {
+		if i < len(m.keys.Normal.Durations) {
+			if i >= len(m.config.DurationPresets) {
+				continue
+			}
+			b.SetHelp(b.Help().Key, fmt.Sprintf("set duration to %d hour(s)", m.config.DurationPresets[i]))
 		}
+		durations = append(durations, b)
 	}
+	groups[3] = durations
+	// Ctrl+C quits from anywhere
+	groups[4] = append(groups[4], m.keys.Global.ForceQuit)
 
-	// Build help with styled sections
-	navSection := detailLabelStyle.Render("━━━ Navigation ━━━") + "\n" +
-		dimStyle.Render("  ↑/k ↓/j") + detailValueStyle.Render("       Move cursor up/down\n") +
-		dimStyle.Render("  ←/h →/l") + detailValueStyle.Render("       Switch tabs (Roles/Groups/Subs)\n") +
-		dimStyle.Render("  Tab") + detailValueStyle.Render("           Cycle through tabs\n")
-
-	selectSection := detailLabelStyle.Render("━━━ Selection & Search ━━━") + "\n" +
-		dimStyle.Render("  Space") + detailValueStyle.Render("         Select/deselect item\n") +
-		dimStyle.Render("  /") + detailValueStyle.Render("             Search/filter\n") +
-		dimStyle.Render("  Esc") + detailValueStyle.Render("           Clear search filter\n")
-
-	actionSection := detailLabelStyle.Render("━━━ Actions ━━━") + "\n" +
-		dimStyle.Render("  Enter") + detailValueStyle.Render("         Activate selected items\n") +
-		dimStyle.Render("  x/Del/BS") + detailValueStyle.Render("      Deactivate active items\n") +
-		dimStyle.Render("  r/F5") + detailValueStyle.Render("          Refresh data from Azure\n")
-
-	durationSection := detailLabelStyle.Render(fmt.Sprintf("━━━ Duration (Current: %dh) ━━━", int(m.duration.Hours()))) + "\n" + durationHelp
+	// Pad descriptions to one width so the centred dialog keeps the columns aligned
+	descWidth := 0
+	for _, group := range groups {
+		for _, b := range group {
+			descWidth = max(descWidth, lipgloss.Width(b.Help().Desc))
+		}
+	}
 
-	settingsSection := detailLabelStyle.Render("━━━ Display & Settings ━━━") + "\n" +
-		dimStyle.Render("  v") + detailValueStyle.Render("             Cycle log level\n") +
-		dimStyle.Render("  c") + detailValueStyle.Render("             Copy logs to clipboard\n") +
-		dimStyle.Render("  e") + detailValueStyle.Render("             Export activation history\n") +
-		dimStyle.Render("  a") + detailValueStyle.Render("             Toggle auto-refresh\n") +
-		dimStyle.Render("  ?") + detailValueStyle.Render("             Show/hide this help\n") +
-		dimStyle.Render("  q/Ctrl+C") + detailValueStyle.Render("      Quit application\n")
+	var sections []string
+	for i, group := range groups {
+		title := normalHelpTitles[i]
+		if i == 3 {
+			title = fmt.Sprintf("%s (Current: %dh)", title, int(m.duration.Hours()))
+		}
+		section := detailLabelStyle.Render(fmt.Sprintf("━━━ %s ━━━", title)) + "\n"
+		for _, b := range group {
+			if !b.Enabled() {
+				continue
+			}
+			section += dimStyle.Render(fmt.Sprintf("  %-14s", b.Help().Key)) +
+				detailValueStyle.Render(fmt.Sprintf("%-*s", descWidth, capitalize(b.Help().Desc))) + "\n"
+		}
+		sections = append(sections, section)
+	}
 
 	iconSection := detailLabelStyle.Render("━━━ Status Icons ━━━") + "\n" +
 		activeStyle.Render("  ● Active") + "       " + lipgloss.NewStyle().Foreground(colorExpiring).Render("◐ Expiring soon\n") +
 		dimStyle.Render("  ○ Inactive") + "     " + lipgloss.NewStyle().Foreground(colorPending).Render("◌ Pending approval\n")
 
-	helpContent := "\n" + navSection + "\n" + selectSection + "\n" + actionSection + "\n" +
-		durationSection + "\n" + settingsSection + "\n" + iconSection
+	helpContent := "\n" + strings.Join(append(sections, iconSection), "\n")
 
 	return confirmStyle.Width(m.dialogWidth()).Render(
 		titleStyle.Foreground(colorHighlight).Render("━━━ Help ━━━") + helpContent,
@@ -1158,8 +1447,8 @@ func (m Model) renderConfirm() string {
 			fmt.Sprintf("Activate %s item(s):\n", countStr) +
 			itemList + "\n" +
 			detailLabelStyle.Render("Duration: ") + durationOptions + "\n" +
-			dimStyle.Render("(Press 1-4 or Tab to change)\n\n") +
-			activeStyle.Render(" [Y] Yes ") + "  " + errorBoldStyle.Render(" [N] No "),
+			dimStyle.Render(fmt.Sprintf("(Press %s to change)\n\n", durationKeys(m.keys.Confirm.Durations, m.keys.Confirm.CycleDuration))) +
+			activeStyle.Render(" "+keyButton(m.keys.Confirm.Confirm, "Yes")+" ") + "  " + errorBoldStyle.Render(" "+keyButton(m.keys.Confirm.Cancel, "No")+" "),
 	)
 }
 
@@ -1179,10 +1468,10 @@ func (m Model) renderJustification() string {
 	return confirmStyle.Width(m.dialogWidth()).Render(
 		titleStyle.Foreground(colorHighlight).Render("━━━ Justification Required ━━━") + "\n\n" +
 			detailLabelStyle.Render("Duration: ") + durationOptions + "\n" +
-			dimStyle.Render("(Press 1-4 or Tab to change)\n\n") +
+			dimStyle.Render(fmt.Sprintf("(Press %s to change)\n\n", durationKeys(m.keys.Justification.Durations, m.keys.Justification.CycleDuration))) +
 			detailLabelStyle.Render("Reason for activation:") + "\n" +
 			m.justificationInput.View() + "\n\n" +
-			activeStyle.Render(" [Enter] Confirm ") + "  " + dimStyle.Render(" [Esc] Cancel "),
+			activeStyle.Render(" "+keyButton(m.keys.Justification.Submit, "Confirm")+" ") + "  " + dimStyle.Render(" "+keyButton(m.keys.Justification.Cancel, "Cancel")+" "),
 	)
 }
 
@@ -1199,6 +1488,20 @@ func (m Model) renderActivating() string {
 	)
 }
 
+func (m Model) renderStepUp() string {
+	count := len(m.pendingActivations)
+	spin := spinner(colorPending)
+
+	return confirmStyle.Width(m.dialogWidth()).Render(
+		titleStyle.Foreground(colorPending).Render("━━━ 🔒 Additional Authentication Required ━━━") + "\n\n" +
+			fmt.Sprintf("%d item(s) require MFA or an authentication context.\n\n", count) +
+			detailValueStyle.Render(spin+" Waiting for browser sign-in...") + "\n\n" +
+			dimStyle.Render("Complete sign-in in your browser window.\n") +
+			dimStyle.Render("Activation will be retried automatically.\n\n") +
+			dimStyle.Render(" "+keyButton(m.keys.Input.Cancel, "Cancel")+" "),
+	)
+}
+
 func (m Model) renderConfirmDeactivate() string {
 	count := len(m.pendingDeactivations)
 	countStr := errorBoldStyle.Render(fmt.Sprintf("%d", count))
@@ -1229,7 +1532,7 @@ func (m Model) renderConfirmDeactivate() string {
 		titleStyle.Foreground(colorError).Render("━━━ Confirm Deactivation ━━━") + "\n\n" +
 			fmt.Sprintf("Deactivate %s active item(s):\n", countStr) +
 			itemList + "\n" +
-			errorBoldStyle.Render(" [Y] Yes ") + "  " + dimStyle.Render(" [N] No "),
+			errorBoldStyle.Render(" "+keyButton(m.keys.Deactivate.Confirm, "Yes")+" ") + "  " + dimStyle.Render(" "+keyButton(m.keys.Deactivate.Cancel, "No")+" "),
 	)
 }
 
@@ -1246,55 +1549,761 @@ func (m Model) renderDeactivating() string {
 	)
 }
 
-func (m Model) renderSearch() string {
-	// Count matches for current search input
-	query := m.searchInput.Value()
-	var matchInfo string
-	if query != "" {
-		roleMatches, groupMatches, subMatches := 0, 0, 0
-		lowerQuery := strings.ToLower(query)
-		for _, r := range m.roles {
-			if strings.Contains(strings.ToLower(r.DisplayName), lowerQuery) {
-				roleMatches++
-			}
+func (m Model) renderRenew() string {
+	count := len(m.pendingRenewals)
+	newEnd := time.Now().Add(m.renewalDuration()).Format("2006-01-02")
+
+	var itemList string
+	maxShow := 5
+	for i, item := range m.pendingRenewals {
+		if i >= maxShow {
+			itemList += dimStyle.Render(fmt.Sprintf("  ... and %d more\n", count-maxShow))
+			break
 		}
-		for _, g := range m.groups {
-			if strings.Contains(strings.ToLower(g.DisplayName), lowerQuery) {
-				groupMatches++
-			}
+		ends := eligibleUntil(item)
+		itemList += fmt.Sprintf("  %s %s\n", iconEligibilityEnding, truncate(pendingItemName(item), 45))
+		itemList += dimStyle.Render(fmt.Sprintf("     ends %s (%s)\n", ends.Local().Format("2006-01-02"), formatEligibilityLeft(*ends)))
+	}
+
+	return confirmStyle.Width(m.dialogWidth()).Render(
+		titleStyle.Foreground(colorHighlight).Render("━━━ Request Eligibility Renewal ━━━") + "\n\n" +
+			fmt.Sprintf("Renew %s eligibilit(ies) until %s:\n", highlightBoldStyle.Render(fmt.Sprintf("%d", count)), highlightBoldStyle.Render(newEnd)) +
+			itemList + "\n" +
+			detailLabelStyle.Render("Justification:") + "\n" +
+			m.justificationInput.View() + "\n\n" +
+			dimStyle.Render("Renewals usually need administrator approval.") + "\n" +
+			dimStyle.Render(" "+keyHints(keyHint("submit", m.keys.Input.Submit), keyHint("cancel", m.keys.Input.Cancel))+" "),
+	)
+}
+
+func (m Model) renderRenewing() string {
+	return confirmStyle.Width(m.dialogWidth()).Render(
+		titleStyle.Foreground(colorHighlight).Render("━━━ Requesting Renewal ━━━") + "\n\n" +
+			fmt.Sprintf("%s Submitting %d renewal request(s)...", spinnerDots(colorHighlight), len(m.pendingRenewals)),
+	)
+}
+
+func (m Model) renderExtend() string {
+	count := len(m.pendingExtensions)
+	newEnd := time.Now().Add(m.duration).Format("15:04")
+
+	var itemList string
+	maxShow := 5
+	for i, item := range m.pendingExtensions {
+		if i >= maxShow {
+			itemList += dimStyle.Render(fmt.Sprintf("  ... and %d more\n", count-maxShow))
+			break
 		}
-		for _, s := range m.lighthouse {
-			match := strings.Contains(strings.ToLower(s.DisplayName), lowerQuery)
-			if !match {
-				for _, role := range s.EligibleRoles {
-					if strings.Contains(strings.ToLower(role.RoleDefinitionName), lowerQuery) {
-						match = true
-						break
-					}
-				}
+		itemList += fmt.Sprintf("  %s %s\n", statusIcon(StatusActive), truncate(pendingItemName(item), 45))
+	}
+
+	// Duration selector visual
+	var durationOptions string
+	for i, preset := range m.config.DurationPresets {
+		if i < 4 {
+			if i == m.durationIndex {
+				durationOptions += highlightBoldStyle.Render(fmt.Sprintf(" [%dh] ", preset))
+			} else {
+				durationOptions += dimStyle.Render(fmt.Sprintf("  %dh  ", preset))
 			}
-			if match {
-				subMatches++
+		}
+	}
+
+	keys := m.keys.Justification
+	return confirmStyle.Width(m.dialogWidth()).Render(
+		titleStyle.Foreground(colorHighlight).Render("━━━ Extend Activation ━━━") + "\n\n" +
+			fmt.Sprintf("Keep %s item(s) active until %s:\n", highlightBoldStyle.Render(fmt.Sprintf("%d", count)), highlightBoldStyle.Render(newEnd)) +
+			itemList + "\n" +
+			detailLabelStyle.Render("Duration: ") + durationOptions + "\n" +
+			dimStyle.Render(fmt.Sprintf("(Press %s to change)\n\n", durationKeys(keys.Durations, keys.CycleDuration))) +
+			detailLabelStyle.Render("Justification:") + "\n" +
+			m.justificationInput.View() + "\n\n" +
+			activeStyle.Render(" "+keyButton(keys.Submit, "Extend")+" ") + "  " + dimStyle.Render(" "+keyButton(keys.Cancel, "Cancel")+" "),
+	)
+}
+
+func (m Model) renderExtending() string {
+	return confirmStyle.Width(m.dialogWidth()).Render(
+		titleStyle.Foreground(colorHighlight).Render("━━━ Extending ━━━") + "\n\n" +
+			fmt.Sprintf("%s Submitting %d extension request(s)...", spinnerDots(colorHighlight), len(m.pendingExtensions)),
+	)
+}
+
+func (m Model) renderAutoExtend() string {
+	count := len(m.pendingAutoExtend)
+
+	var itemList string
+	maxShow := 5
+	for i, item := range m.pendingAutoExtend {
+		if i >= maxShow {
+			itemList += dimStyle.Render(fmt.Sprintf("  ... and %d more\n", count-maxShow))
+			break
+		}
+		itemList += fmt.Sprintf("  %s %s\n", iconAutoExtend, truncate(pendingItemName(item), 45))
+	}
+
+	limits := fmt.Sprintf("Extends %d min before expiry, within policy limits and at most %dh from now.",
+		m.config.AutoExtendLeadMinutes, m.config.AutoExtendMaxHours)
+
+	return confirmStyle.Width(m.dialogWidth()).Render(
+		titleStyle.Foreground(colorPending).Render("━━━ Auto-extend ━━━") + "\n\n" +
+			fmt.Sprintf("Keep %s item(s) active until:\n", highlightBoldStyle.Render(fmt.Sprintf("%d", count))) +
+			itemList + "\n" +
+			m.autoExtendInput.View() + "\n\n" +
+			dimStyle.Render(limits) + "\n" +
+			dimStyle.Render("Runs only while pim-tui is open.") + "\n" +
+			dimStyle.Render(" "+keyHints(keyHint("apply", m.keys.Input.Submit), keyHint("cancel", m.keys.Input.Cancel))+" "),
+	)
+}
+
+func (m Model) renderResults() string {
+	succeeded, failed := splitResults(m.bulkResults)
+	titleColor := colorError
+	if len(succeeded) > 0 {
+		titleColor = colorExpiring
+	}
+
+	// Failures first since they need attention
+	ordered := make([]bulkResult, 0, len(m.bulkResults))
+	for _, r := range m.bulkResults {
+		if r.err != nil {
+			ordered = append(ordered, r)
+		}
+	}
+	for _, r := range m.bulkResults {
+		if r.err == nil {
+			ordered = append(ordered, r)
+		}
+	}
+
+	reasonWidth := max(m.dialogWidth()-12, 20)
+	var itemList string
+	maxShow := 10
+	for i, r := range ordered {
+		if i >= maxShow {
+			itemList += dimStyle.Render(fmt.Sprintf("  ... and %d more\n", len(ordered)-maxShow))
+			break
+		}
+		if r.err != nil {
+			itemList += errorBoldStyle.Render("  ✗ ") + pendingItemName(r.item) + "\n"
+			itemList += dimStyle.Render(fmt.Sprintf("     %s\n", truncate(bulkErrorReason(r.err), reasonWidth)))
+		} else {
+			itemList += activeStyle.Render("  ✓ ") + pendingItemName(r.item) + "\n"
+		}
+	}
+
+	summary := activeStyle.Render(fmt.Sprintf("✓ %d succeeded", len(succeeded))) + "   " +
+		errorBoldStyle.Render(fmt.Sprintf("✗ %d failed", len(failed)))
+
+	return confirmStyle.Width(m.dialogWidth()).Render(
+		titleStyle.Foreground(titleColor).Render(fmt.Sprintf("━━━ %s Results ━━━", capitalize(m.bulkOperation))) + "\n\n" +
+			summary + "\n\n" +
+			itemList + "\n" +
+			highlightBoldStyle.Render(" "+keyButton(m.keys.Results.Retry, "Retry failed")+" ") + "  " + dimStyle.Render(" "+keyButton(m.keys.Results.Close, "Close")+" "),
+	)
+}
+
+// historyKindLabel returns the icon and label for a history entry kind
+func historyKindLabel(kind string) string {
+	switch kind {
+	case HistoryDeactivate:
+		return "▼ deactivate"
+	case HistoryExtend:
+		return "↻ extend    "
+	case HistoryRenew:
+		return "⟲ renew     "
+	default:
+		return "▲ activate  "
+	}
+}
+
+// renderHistoryFilters shows the active history filters
+func (m Model) renderHistoryFilters() string {
+	kind := m.historyFilter.Kind
+	if kind == "" {
+		kind = "all"
+	}
+	outcome := m.historyFilter.Outcome
+	if outcome == "" {
+		outcome = "all"
+	}
+	name := m.historyFilterInput.View()
+	if !m.historyFilterEditing {
+		name = detailValueStyle.Render(m.historyFilter.Name)
+		if m.historyFilter.Name == "" {
+			name = dimStyle.Render("-")
+		}
+	}
+	return detailLabelStyle.Render("Date: ") + detailValueStyle.Render(historyRanges[m.historyRange].label) + "  " +
+		detailLabelStyle.Render("Kind: ") + detailValueStyle.Render(kind) + "  " +
+		detailLabelStyle.Render("Outcome: ") + detailValueStyle.Render(outcome) + "  " +
+		detailLabelStyle.Render("Name: ") + name
+}
+
+func (m Model) renderExport() string {
+	width := m.dialogWidth()
+	title := titleStyle.Foreground(colorHighlight).Render("━━━ Export ━━━")
+
+	dateRange := historyRanges[m.exportRange].label
+	if exportTargets[m.exportWhat] != "history" {
+		dateRange = "n/a"
+	}
+	fields := []struct{ label, value string }{
+		{"Data:", exportTargets[m.exportWhat]},
+		{"Format:", string(export.Formats[m.exportFormat])},
+		{"Date range:", dateRange},
+	}
+	var rows []string
+	for i, f := range fields {
+		value := "◂ " + f.value + " ▸"
+		if i == m.exportField {
+			value = cursorStyle.Render(value)
+		} else {
+			value = detailValueStyle.Render(value)
+		}
+		rows = append(rows, detailLabelStyle.Render(fmt.Sprintf("%-14s", f.label))+value)
+	}
+	path := m.exportPathInput.View()
+	if m.exportField != exportFieldPath {
+		path = detailValueStyle.Render(m.exportPathInput.Value())
+	}
+	rows = append(rows, detailLabelStyle.Render(fmt.Sprintf("%-14s", "Path:"))+path)
+
+	ek := m.keys.Export
+	footer := dimStyle.Render(" " + keyHints(
+		keyHint("field", ek.PrevField, ek.NextField),
+		keyHint("change", ek.Prev, ek.Next),
+		bindingHint(ek.Submit),
+		bindingHint(ek.Cancel),
+	) + " ")
+	return confirmStyle.Width(width).Render(
+		title + "\n\n" +
+			strings.Join(rows, "\n") + "\n\n" +
+			footer,
+	)
+}
+
+// renderRemoteHistoryStatus describes the Azure audit trail merged into the history view
+func (m Model) renderRemoteHistoryStatus() string {
+	label := detailLabelStyle.Render("Azure: ")
+	switch {
+	case m.remoteHistoryLoading:
+		return label + spinner(colorPending) + dimStyle.Render(" loading request history...")
+	case m.remoteHistoryErr != nil:
+		return label + errorBoldStyle.Render("⚠ ") + dimStyle.Render(truncate(m.remoteHistoryErr.Error(), max(m.dialogWidth()-20, 20)))
+	case m.remoteHistoryFetched.IsZero():
+		return label + dimStyle.Render(fmt.Sprintf("not loaded (%s)", keyHint("to load", m.keys.History.Reload)))
+	}
+	return label + detailValueStyle.Render(fmt.Sprintf("%d requests", len(m.remoteHistory))) +
+		dimStyle.Render(fmt.Sprintf(" in the last %d days (☁ = made elsewhere)", int(remoteHistoryWindow.Hours()/24)))
+}
+
+func (m Model) renderHistory() string {
+	width := m.dialogWidth()
+	entries := m.filteredHistory()
+	total := len(m.mergedHistory())
+	title := titleStyle.Foreground(colorHighlight).Render(
+		fmt.Sprintf("━━━ Activation History (%d/%d) ━━━", len(entries), total))
+	filters := m.renderHistoryFilters() + "\n" + m.renderRemoteHistoryStatus()
+	hk := m.keys.History
+	footer := dimStyle.Render(" " + keyHints(
+		keyHint("navigate", hk.Up, hk.Down),
+		bindingHint(hk.Range),
+		bindingHint(hk.Kind),
+		bindingHint(hk.Outcome),
+		bindingHint(hk.Filter),
+		bindingHint(hk.Clear),
+		bindingHint(hk.Reload),
+		bindingHint(hk.Export),
+		bindingHint(hk.Close),
+	) + " ")
+
+	if len(entries) == 0 {
+		empty := "No activations or deactivations recorded yet."
+		if total > 0 {
+			empty = "No history entries match the current filters."
+		}
+		return confirmStyle.Width(width).Render(
+			title + "\n\n" +
+				filters + "\n\n" +
+				dimStyle.Render(empty) + "\n\n" +
+				footer,
+		)
+	}
+
+	// Newest first; historyCursor indexes this order
+	count := len(entries)
+	entryAt := func(i int) ActivationHistoryEntry { return entries[min(i, count-1)] }
+
+	listHeight := max(m.height-30, 5)
+	start := 0
+	if m.historyCursor >= listHeight {
+		start = m.historyCursor - listHeight + 1
+	}
+	end := min(start+listHeight, count)
+
+	nameWidth := max(width-45, 15)
+	var rows []string
+	for i := start; i < end; i++ {
+		entry := entryAt(i)
+		outcome := activeStyle.Render("✓")
+		if !entry.Success {
+			outcome = errorBoldStyle.Render("✗")
+		}
+		until := "      "
+		if entry.Success && entry.ExpiresAt != nil {
+			until = "→" + entry.ExpiresAt.Local().Format("15:04")
+		}
+		origin := " "
+		if entry.Remote {
+			origin = "☁"
+		}
+		line := fmt.Sprintf("%s %s  %s  %s  %-*s  %s",
+			origin, entry.Time.Local().Format("Jan 02 15:04"), historyKindLabel(entry.Kind), outcome,
+			nameWidth, truncate(entry.Name, nameWidth), dimStyle.Render(until))
+		if i == m.historyCursor {
+			line = cursorStyle.Render(line)
+		}
+		rows = append(rows, line)
+	}
+
+	// Details of the selected entry
+	selected := entryAt(m.historyCursor)
+	var details []string
+	addDetail := func(label, value string) {
+		if value != "" {
+			details = append(details, detailLabelStyle.Render(fmt.Sprintf("%-14s", label))+detailValueStyle.Render(truncate(value, max(width-20, 20))))
+		}
+	}
+	addDetail("Type:", selected.Type)
+	if selected.Remote {
+		addDetail("Source:", "Azure audit trail")
+	}
+	addDetail("Scope:", selected.Scope)
+	if selected.Duration > 0 {
+		addDetail("Duration:", formatDuration(selected.Duration))
+	}
+	if selected.ExpiresAt != nil {
+		addDetail("Expires:", selected.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	}
+	addDetail("Request ID:", selected.RequestID)
+	addDetail("Status:", selected.Status)
+	addDetail("Justification:", selected.Justification)
+	if selected.Error != "" {
+		details = append(details, errorBoldStyle.Render("Error:        ")+
+			detailValueStyle.Render(truncate(selected.Error, max(width-20, 20))))
+	}
+
+	return confirmStyle.Width(width).Render(
+		title + "\n\n" +
+			filters + "\n\n" +
+			strings.Join(rows, "\n") + "\n\n" +
+			strings.Join(details, "\n") + "\n\n" +
+			footer,
+	)
+}
+
+// timelineStyles draws each span kind in the timeline view
+var timelineStyles = map[spanKind]struct {
+	cell, label string
+	color       lipgloss.Color
+}{
+	spanEnded:     {"█", "ended", colorHighlight},
+	spanActive:    {"█", "active", colorActive},
+	spanScheduled: {"░", "auto-extend", colorPending},
+	spanStanding:  {"━", "standing", colorWarning},
+}
+
+func (m Model) renderTimeline() string {
+	width := m.dialogWidth()
+	now := time.Now()
+	from, to := now.Add(-timelinePast), now.Add(timelineFuture)
+	rows := m.timelineRows(now)
+
+	tk := m.keys.Timeline
+	title := titleStyle.Foreground(colorHighlight).Render(
+		fmt.Sprintf("━━━ Timeline: last %s and next %s ━━━", formatCompactDuration(timelinePast), formatCompactDuration(timelineFuture)))
+	footer := dimStyle.Render(" " + keyHints(
+		keyHint("navigate", tk.Up, tk.Down),
+		bindingHint(tk.Reload),
+		bindingHint(tk.Close),
+	) + " ")
+
+	if len(rows) == 0 {
+		return confirmStyle.Width(width).Render(
+			title + "\n\n" +
+				m.renderRemoteHistoryStatus() + "\n\n" +
+				dimStyle.Render("No elevations in this window.") + "\n\n" +
+				footer,
+		)
+	}
+
+	// Name column, then one cell per slice of the window
+	inner := width - 4
+	nameWidth := min(max(inner/4, 12), 32)
+	chartWidth := max(inner-nameWidth-1, 10)
+	step := to.Sub(from) / time.Duration(chartWidth)
+	nowCol := int(now.Sub(from) / step)
+	pad := strings.Repeat(" ", nameWidth+1)
+
+	cursor := min(m.timelineCursor, len(rows)-1)
+	listHeight := max(m.height-34, 5)
+	start := 0
+	if cursor >= listHeight {
+		start = cursor - listHeight + 1
+	}
+	end := min(start+listHeight, len(rows))
+
+	lines := []string{pad + renderTimelineAxis(from, step, chartWidth, nowCol)}
+	for i := start; i < end; i++ {
+		name := fmt.Sprintf("%-*s", nameWidth, truncate(rows[i].name, nameWidth))
+		if i == cursor {
+			name = cursorStyle.Render(name)
+		}
+		lines = append(lines, name+" "+renderTimelineRow(rows[i], from, step, chartWidth, nowCol))
+	}
+	if len(rows) > listHeight {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("%-*s", nameWidth, fmt.Sprintf("↕ %d/%d", cursor+1, len(rows)))))
+	}
+
+	// Overlapping elevations stand out in the concurrency row
+	var concurrent strings.Builder
+	for col, n := range timelineConcurrency(rows, from, to, chartWidth) {
+		switch {
+		case n == 0 && col == nowCol:
+			concurrent.WriteString(dimStyle.Render("│"))
+		case n == 0:
+			concurrent.WriteString(" ")
+		case n == 1:
+			concurrent.WriteString(dimStyle.Render("1"))
+		case n < 10:
+			concurrent.WriteString(lipgloss.NewStyle().Foreground(colorWarning).Bold(true).Render(fmt.Sprintf("%d", n)))
+		default:
+			concurrent.WriteString(lipgloss.NewStyle().Foreground(colorWarning).Bold(true).Render("+"))
+		}
+	}
+	lines = append(lines, detailLabelStyle.Render(fmt.Sprintf("%-*s", nameWidth, "Concurrent"))+" "+concurrent.String())
+
+	var legend []string
+	for _, kind := range []spanKind{spanEnded, spanActive, spanScheduled, spanStanding} {
+		s := timelineStyles[kind]
+		legend = append(legend, lipgloss.NewStyle().Foreground(s.color).Render(s.cell)+" "+s.label)
+	}
+	legend = append(legend, dimStyle.Render("│")+" now")
+
+	return confirmStyle.Width(width).Render(
+		title + "\n\n" +
+			m.renderRemoteHistoryStatus() + "\n\n" +
+			lipgloss.NewStyle().Align(lipgloss.Left).Render(strings.Join(lines, "\n")) + "\n\n" +
+			dimStyle.Render(strings.Join(legend, "   ")) + "\n" +
+			renderTimelineSpans(rows[cursor], now) + "\n\n" +
+			footer,
+	)
+}
+
+// renderTimelineAxis labels the chart with clock times every few hours and
+// marks the current time
+func renderTimelineAxis(from time.Time, step time.Duration, width, nowCol int) string {
+	axis := []rune(strings.Repeat(" ", width))
+	every := 4 * time.Hour
+	if width < 40 {
+		every = 8 * time.Hour
+	}
+	local := from.Local()
+	tick := local.Truncate(time.Hour).Add(time.Hour)
+	for tick.Hour()%int(every.Hours()) != 0 {
+		tick = tick.Add(time.Hour)
+	}
+	for ; ; tick = tick.Add(every) {
+		col := int(tick.Sub(from) / step)
+		label := []rune("┊" + tick.Format("15:04"))
+		if col+len(label) > width {
+			break
+		}
+		// Keep clear of the now marker
+		if col <= nowCol && nowCol < col+len(label)+1 {
+			continue
+		}
+		copy(axis[col:], label)
+	}
+	if nowCol < width {
+		axis[nowCol] = '▼'
+	}
+	return dimStyle.Render(string(axis))
+}
+
+// renderTimelineRow draws the spans of one row, one cell per step from from
+func renderTimelineRow(row timelineRow, from time.Time, step time.Duration, width, nowCol int) string {
+	var b strings.Builder
+	for col := 0; col < width; {
+		kind, ok := timelineCell(row, from.Add(time.Duration(col)*step), step)
+		// Render runs of equal cells with one style
+		run := col + 1
+		for run < width && run != nowCol && col != nowCol {
+			k, o := timelineCell(row, from.Add(time.Duration(run)*step), step)
+			if k != kind || o != ok {
+				break
 			}
+			run++
+		}
+		switch {
+		case ok:
+			s := timelineStyles[kind]
+			b.WriteString(lipgloss.NewStyle().Foreground(s.color).Render(strings.Repeat(s.cell, run-col)))
+		case col == nowCol:
+			b.WriteString(dimStyle.Render("│"))
+		default:
+			b.WriteString(strings.Repeat(" ", run-col))
+		}
+		col = run
+	}
+	return b.String()
+}
+
+// timelineCell returns the span kind shown in the cell starting at start,
+// preferring current over planned, ended and standing spans
+func timelineCell(row timelineRow, start time.Time, step time.Duration) (spanKind, bool) {
+	rank := map[spanKind]int{spanActive: 3, spanScheduled: 2, spanEnded: 1, spanStanding: 0}
+	found, ok := spanKind(0), false
+	for _, s := range row.spans {
+		if s.start.Before(start.Add(step)) && s.end.After(start) && (!ok || rank[s.kind] > rank[found]) {
+			found, ok = s.kind, true
+		}
+	}
+	return found, ok
+}
+
+// renderTimelineSpans lists the spans of the selected row with their times
+func renderTimelineSpans(row timelineRow, now time.Time) string {
+	at := func(t time.Time) string {
+		if y, mo, d := t.Local().Date(); y == now.Year() && mo == now.Month() && d == now.Day() {
+			return t.Local().Format("15:04")
+		}
+		return t.Local().Format("Jan 02 15:04")
+	}
+	var parts []string
+	for _, s := range row.spans {
+		switch s.kind {
+		case spanStanding:
+			parts = append(parts, "standing")
+		case spanScheduled:
+			parts = append(parts, "auto-extend to "+at(s.end))
+		case spanActive:
+			parts = append(parts, fmt.Sprintf("%s–%s (expires in %s)", at(s.start), at(s.end), formatCompactDuration(s.end.Sub(now))))
+		default:
+			parts = append(parts, fmt.Sprintf("%s–%s (%s)", at(s.start), at(s.end), formatCompactDuration(s.end.Sub(s.start))))
+		}
+	}
+	return detailLabelStyle.Render(row.name+": ") + detailValueStyle.Render(strings.Join(parts, ", "))
+}
+
+// usageTypeLabels names history item types in the statistics table
+var usageTypeLabels = map[string]string{"role": "role", "group": "group", "azure-role": "azure role"}
+
+func (m Model) renderStats() string {
+	width := m.dialogWidth()
+	now := time.Now()
+	stats := m.usageStats(now)
+
+	var eligible, used, unused int
+	for _, s := range stats {
+		if s.eligible {
+			eligible++
+		}
+		if s.total > 0 {
+			used++
+		}
+		if s.unused() {
+			unused++
+		}
+	}
+
+	sk := m.keys.Stats
+	title := titleStyle.Foreground(colorHighlight).Render(
+		fmt.Sprintf("━━━ Usage Statistics: last %d days ━━━", int(statsWindow.Hours()/24)))
+	summary := detailLabelStyle.Render("Eligible: ") + detailValueStyle.Render(fmt.Sprintf("%d", eligible)) + "  " +
+		detailLabelStyle.Render("Used: ") + detailValueStyle.Render(fmt.Sprintf("%d", used)) + "  " +
+		detailLabelStyle.Render("Never used: ") + lipgloss.NewStyle().Foreground(colorWarning).Render(fmt.Sprintf("%d", unused))
+	// Retention or a fresh install can leave less history than the window covers
+	coverage := dimStyle.Render("No activations recorded yet")
+	if since := m.historySince(); !since.IsZero() {
+		coverage = dimStyle.Render("History since " + since.Local().Format("2006-01-02"))
+		if since.After(now.Add(-statsWindow)) {
+			coverage += lipgloss.NewStyle().Foreground(colorWarning).Render(
+				fmt.Sprintf(" (only %d days; older activations are not counted)", int(now.Sub(since).Hours()/24)))
+		}
+	}
+	header := summary + "\n" + coverage + "\n" + m.renderRemoteHistoryStatus()
+	footer := dimStyle.Render(" " + keyHints(
+		keyHint("navigate", sk.Up, sk.Down),
+		bindingHint(sk.Reload),
+		bindingHint(sk.Close),
+	) + " ")
+
+	if len(stats) == 0 {
+		return confirmStyle.Width(width).Render(
+			title + "\n\n" +
+				header + "\n\n" +
+				dimStyle.Render("No eligible roles, groups or Azure roles loaded.") + "\n\n" +
+				footer,
+		)
+	}
+
+	cursor := min(m.statsCursor, len(stats)-1)
+	listHeight := max(m.height-32, 5)
+	start := 0
+	if cursor >= listHeight {
+		start = cursor - listHeight + 1
+	}
+	end := min(start+listHeight, len(stats))
+
+	nameWidth := max(width-72, 15)
+	columns := fmt.Sprintf("%-*s  %-10s  %4s  %4s  %8s  %7s  %7s  %-12s",
+		nameWidth, "Name", "Type", "30d", "90d", "Elevated", "Avg", "Req", "Last used")
+	rows := []string{detailLabelStyle.Render(columns)}
+	for i := start; i < end; i++ {
+		s := stats[i]
+		elevated, avg, requested, last := "-", "-", "-", "never"
+		if s.elevated > 0 {
+			elevated = formatCompactDuration(s.elevated)
+		}
+		if s.total > 0 {
+			avg = formatCompactDuration(s.averageElevated())
+			requested = formatCompactDuration(s.averageRequested())
+		}
+		if !s.lastUsed.IsZero() {
+			last = s.lastUsed.Local().Format("Jan 02 15:04")
 		}
+		if s.standing {
+			last = "standing"
+		}
+		line := fmt.Sprintf("%-*s  %-10s  %4d  %4d  %8s  %7s  %7s  %-12s",
+			nameWidth, truncate(s.name, nameWidth), usageTypeLabels[s.itemType], s.recent, s.total, elevated, avg, requested, last)
+		switch {
+		case i == cursor:
+			line = cursorStyle.Render(line)
+		case s.unused():
+			line = lipgloss.NewStyle().Foreground(colorWarning).Render(line)
+		case !s.eligible:
+			line = dimStyle.Render(line)
+		}
+		rows = append(rows, line)
+	}
+	if len(stats) > listHeight {
+		rows = append(rows, dimStyle.Render(fmt.Sprintf("↕ %d/%d", cursor+1, len(stats))))
+	}
+
+	return confirmStyle.Width(width).Render(
+		title + "\n\n" +
+			header + "\n\n" +
+			lipgloss.NewStyle().Align(lipgloss.Left).Render(strings.Join(rows, "\n")) + "\n\n" +
+			renderUsageDetail(stats[cursor]) + "\n\n" +
+			footer,
+	)
+}
+
+// renderUsageDetail explains the selected statistics row in words
+func renderUsageDetail(s usageStat) string {
+	var detail string
+	switch {
+	case s.standing:
+		detail = "standing assignment, always active"
+	case s.unused():
+		detail = fmt.Sprintf("eligible but not activated in %d days; a candidate for removal", int(statsWindow.Hours()/24))
+	default:
+		detail = fmt.Sprintf("%d activations, %s elevated", s.total, formatCompactDuration(s.elevated))
+		if s.total > 0 && s.requested > 0 {
+			detail += fmt.Sprintf(", %d%% of the requested time used", int(100*s.elevated/s.requested))
+		}
+		if !s.eligible {
+			detail += "; no longer eligible"
+		}
+	}
+	return detailLabelStyle.Render(s.name+": ") + detailValueStyle.Render(detail)
+}
+
+func (m Model) renderSearch() string {
+	// Count matches for current search input
+	query := parseSearch(m.searchInput.Value())
+	var matchInfo string
+	if len(query) > 0 {
+		roleMatches, groupMatches, subMatches := m.searchCounts(query)
 		total := roleMatches + groupMatches + subMatches
 		if total > 0 {
-			matchInfo = activeStyle.Render(fmt.Sprintf("Found: %d roles, %d groups, %d subs", roleMatches, groupMatches, subMatches))
+			matchInfo = activeStyle.Render(fmt.Sprintf("Found: %d roles, %d groups, %d subs", roleMatches, groupMatches, subMatches)) + "\n\n" +
+				m.renderEntryRows(m.searchResults, m.searchCursor, 8, m.searchInput.Value())
 		} else {
 			matchInfo = errorBoldStyle.Render("No matches found")
 		}
 	} else {
-		matchInfo = dimStyle.Render("Type to search...")
+		matchInfo = dimStyle.Render("Type to search... fields: name: role: group: sub: tenant: scope: status: type:")
 	}
 
+	sk := m.keys.Search
 	return confirmStyle.Width(m.dialogWidth()).Render(
 		titleStyle.Foreground(colorHighlight).Render("━━━ Search / Filter ━━━") + "\n\n" +
 			m.searchInput.View() + "\n\n" +
 			matchInfo + "\n\n" +
-			activeStyle.Render(" [Enter] Apply ") + "  " + dimStyle.Render(" [Esc] Cancel "),
+			dimStyle.Render(" "+keyHints(
+				keyHint("choose", sk.Up, sk.Down),
+				bindingHint(sk.Submit),
+				bindingHint(sk.Cancel),
+			)+" "),
 	)
 }
 
+func (m Model) renderPalette() string {
+	rows := errorBoldStyle.Render("No matches found")
+	if len(m.paletteResults) > 0 {
+		rows = m.renderEntryRows(m.paletteResults, m.paletteCursor, 10, m.paletteInput.Value())
+	}
+
+	pk := m.keys.Palette
+	footer := dimStyle.Render(" " + keyHints(
+		keyHint("navigate", pk.Up, pk.Down),
+		bindingHint(pk.Submit),
+		bindingHint(pk.Cancel),
+	) + " ")
+
+	return confirmStyle.Width(m.dialogWidth()).Render(
+		titleStyle.Foreground(colorHighlight).Render("━━━ Command Palette ━━━") + "\n\n" +
+			m.paletteInput.View() + "\n\n" +
+			rows + "\n\n" +
+			footer,
+	)
+}
+
+// renderEntryRows renders up to maxShow ranked palette or search results around
+// the cursor, highlighting what query matched in each title
+func (m Model) renderEntryRows(entries []paletteEntry, cursor, maxShow int, query string) string {
+	width := m.dialogWidth() - 8
+
+	var rows []string
+	// Keep the cursor inside the visible window
+	start := max(0, min(cursor-maxShow/2, len(entries)-maxShow))
+	for i := start; i < len(entries) && i < start+maxShow; i++ {
+		e := entries[i]
+		kind := fmt.Sprintf("%-11s", e.kind)
+		// Actions show their key, items where they live
+		detail := e.hint
+		if detail == "" {
+			detail = e.detail
+		}
+		title := truncate(e.title, max(width-lipgloss.Width(kind)-lipgloss.Width(e.hint)-4, 10))
+		detail = truncate(detail, max(width-lipgloss.Width(kind)-lipgloss.Width(title)-4, 3))
+
+		prefix, nameStyle := "  ", detailValueStyle
+		if i == cursor {
+			prefix, nameStyle = highlightBoldStyle.Render("▸ "), highlightBoldStyle
+		}
+		rows = append(rows, prefix+dimStyle.Render(kind)+nameStyle.Render(highlightSearchMatch(title, query))+"  "+dimStyle.Render(detail))
+	}
+	if len(entries) > maxShow {
+		rows = append(rows, dimStyle.Render(fmt.Sprintf("  %d/%d", cursor+1, len(entries))))
+	}
+	return lipgloss.NewStyle().Align(lipgloss.Left).Render(strings.Join(rows, "\n"))
+}
+
 // Helper functions
 
 func (m Model) renderItemList(height int, itemType string, count int, getItem func(int) (name string, status azure.ActivationStatus, selected, isCursor bool)) string {
@@ -1320,7 +2329,7 @@ func (m Model) renderItemList(height int, itemType string, count int, getItem fu
 			break
 		}
 		name, status, selected, isCursor := getItem(i)
-		if m.searchActive && !strings.Contains(strings.ToLower(name), strings.ToLower(m.searchQuery)) {
+		if !m.search().matches(searchDoc{name: name}) {
 			continue
 		}
 		lines = append(lines, m.renderListItem(i, name, status, selected, isCursor))
@@ -1338,9 +2347,22 @@ func (m Model) renderItemList(height int, itemType string, count int, getItem fu
 	return strings.Join(lines, "\n")
 }
 
-// renderItemListWithExpiry renders a list with optional expiry time display
-// scrollOffset is the stored scroll position (index of first visible item)
-func (m Model) renderItemListWithExpiry(height int, itemType string, count int, scrollOffset int, getItem func(int) (name string, status azure.ActivationStatus, selected, isCursor bool, expiresAt *time.Time)) string {
+// listEntry is one row of the roles or groups list
+type listEntry struct {
+	name          string
+	status        azure.ActivationStatus
+	selected      bool
+	isCursor      bool
+	expiresAt     *time.Time // End of the current activation
+	standing      bool       // Permanent assignment
+	eligibleUntil *time.Time // End of the eligibility, nil if permanent
+	autoExtend    *time.Time // Auto-extend target, nil if not auto-extended
+	favorite      bool
+}
+
+// renderItemListWithExpiry renders the items at visibleIndices with optional expiry
+// time display; scrollOffset is the stored scroll position (index of first visible item)
+func (m Model) renderItemListWithExpiry(height int, itemType string, count int, visibleIndices []int, scrollOffset int, getItem func(int) listEntry) string {
 	if count == 0 {
 		emptyIcon := "📭"
 		if itemType == "roles" {
@@ -1356,16 +2378,6 @@ func (m Model) renderItemListWithExpiry(height int, itemType string, count int,
 		)
 	}
 
-	// First pass: count visible items after filtering
-	visibleIndices := make([]int, 0, count)
-	for i := 0; i < count; i++ {
-		name, _, _, _, _ := getItem(i)
-		if m.searchActive && !strings.Contains(strings.ToLower(name), strings.ToLower(m.searchQuery)) {
-			continue
-		}
-		visibleIndices = append(visibleIndices, i)
-	}
-
 	if len(visibleIndices) == 0 && m.searchActive {
 		return lipgloss.JoinVertical(lipgloss.Center,
 			"",
@@ -1374,12 +2386,14 @@ func (m Model) renderItemListWithExpiry(height int, itemType string, count int,
 			dimStyle.Render("Try a different search term"),
 		)
 	}
+	if len(visibleIndices) == 0 {
+		return m.renderFilteredEmpty(itemType)
+	}
 
 	// Find cursor position in visible list (for position indicator)
 	cursorVisibleIdx := 0
 	for idx, i := range visibleIndices {
-		_, _, _, isCursor, _ := getItem(i)
-		if isCursor {
+		if getItem(i).isCursor {
 			cursorVisibleIdx = idx
 			break
 		}
@@ -1405,8 +2419,7 @@ func (m Model) renderItemListWithExpiry(height int, itemType string, count int,
 	var lines []string
 	endIdx := min(startIdx+displayHeight, len(visibleIndices))
 	for _, i := range visibleIndices[startIdx:endIdx] {
-		name, status, selected, isCursor, expiresAt := getItem(i)
-		lines = append(lines, m.renderListItemWithExpiry(i, name, status, selected, isCursor, expiresAt))
+		lines = append(lines, m.renderListItemWithExpiry(getItem(i)))
 	}
 
 	// Add scroll indicator if there are more items
@@ -1425,23 +2438,40 @@ func (m Model) renderItemListWithExpiry(height int, itemType string, count int,
 	return strings.Join(lines, "\n")
 }
 
-// renderListItemWithExpiry renders a list item with optional compact expiry time
-func (m Model) renderListItemWithExpiry(idx int, name string, status azure.ActivationStatus, selected, isCursor bool, expiresAt *time.Time) string {
-	// Calculate available width for name (accounting for expiry suffix)
-	baseWidth := m.listPanelWidth() - 6 // checkbox + status icon
-	expiryWidth := 0
-	var expirySuffix string
+// renderListItemWithExpiry renders a list item with optional compact expiry time.
+// Standing assignments never expire and are tagged instead; eligibilities ending
+// within the warning window get an hourglass with the days left.
+func (m Model) renderListItemWithExpiry(item listEntry) string {
+	// Suffixes after the name, e.g. "2h" or "⌛ 5d"
+	var suffixes []string
+	suffixWidth := 0
+	addSuffix := func(text string, style lipgloss.Style) {
+		suffixes = append(suffixes, style.Render(text))
+		suffixWidth += lipgloss.Width(text) + 1 // +1 for space
+	}
 
 	// Add compact expiry time for active/expiring items
-	if expiresAt != nil && status.IsActive() {
-		remaining := time.Until(*expiresAt)
-		if remaining > 0 {
-			expirySuffix = formatCompactDuration(remaining)
-			expiryWidth = len(expirySuffix) + 1 // +1 for space
+	if item.standing {
+		addSuffix(iconStanding+" standing", lipgloss.NewStyle().Foreground(colorWarning))
+	} else if item.expiresAt != nil && item.status.IsActive() {
+		if remaining := time.Until(*item.expiresAt); remaining > 0 {
+			addSuffix(formatCompactDuration(remaining), expiryStyle(remaining))
 		}
 	}
+	if item.autoExtend != nil {
+		addSuffix(iconAutoExtend+item.autoExtend.Format("15:04"), lipgloss.NewStyle().Foreground(colorPending))
+	}
+	if azure.EligibilityExpiringWithin(item.eligibleUntil, m.eligibilityWarnWindow()) {
+		addSuffix(iconEligibilityEnding+" "+formatEligibilityLeft(*item.eligibleUntil), lipgloss.NewStyle().Foreground(colorCritical))
+	}
 
-	nameWidth := max(baseWidth-expiryWidth, 10)
+	// Calculate available width for name (accounting for suffixes)
+	baseWidth := m.listPanelWidth() - 6 // checkbox + status icon
+	if item.favorite {
+		baseWidth -= 2
+	}
+	name := item.name
+	nameWidth := max(baseWidth-suffixWidth, 10)
 	if len(name) > nameWidth {
 		name = name[:nameWidth-3] + "..."
 	}
@@ -1452,33 +2482,68 @@ func (m Model) renderListItemWithExpiry(idx int, name string, status azure.Activ
 		displayName = highlightSearchMatch(name, m.searchQuery)
 	}
 
-	// Build line with optional expiry
-	var line string
-	if expirySuffix != "" {
-		// Color the expiry based on time remaining
-		var expiryStyle lipgloss.Style
-		remaining := time.Until(*expiresAt)
-		switch {
-		case remaining < 15*time.Minute:
-			expiryStyle = lipgloss.NewStyle().Foreground(colorCritical)
-		case remaining < 30*time.Minute:
-			expiryStyle = lipgloss.NewStyle().Foreground(colorWarning)
-		case remaining < time.Hour:
-			expiryStyle = lipgloss.NewStyle().Foreground(colorExpiring)
-		default:
-			expiryStyle = dimStyle
-		}
-		line = fmt.Sprintf("%s %s %s %s", renderCheckbox(selected), statusIcon(status), displayName, expiryStyle.Render(expirySuffix))
-	} else {
-		line = fmt.Sprintf("%s %s %s", renderCheckbox(selected), statusIcon(status), displayName)
+	line := fmt.Sprintf("%s %s %s%s", renderCheckbox(item.selected), statusIcon(item.status), favoriteMark(item.favorite), displayName)
+	if len(suffixes) > 0 {
+		line += " " + strings.Join(suffixes, " ")
 	}
 
-	if isCursor {
+	if item.isCursor {
 		return cursorStyle.Render(line)
 	}
 	return line
 }
 
+// favoriteMark prefixes the names of favorite items
+func favoriteMark(favorite bool) string {
+	if !favorite {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(colorExpiring).Render(iconFavorite) + " "
+}
+
+// expiryStyle colors a remaining activation time by how soon it runs out
+func expiryStyle(remaining time.Duration) lipgloss.Style {
+	switch {
+	case remaining < 15*time.Minute:
+		return lipgloss.NewStyle().Foreground(colorCritical)
+	case remaining < 30*time.Minute:
+		return lipgloss.NewStyle().Foreground(colorWarning)
+	case remaining < time.Hour:
+		return lipgloss.NewStyle().Foreground(colorExpiring)
+	}
+	return dimStyle
+}
+
+// formatEligibilityLeft formats the time until an eligibility ends, like "5d", "3h" or "ended"
+func formatEligibilityLeft(endsAt time.Time) string {
+	remaining := time.Until(endsAt)
+	switch {
+	case remaining <= 0:
+		return "ended"
+	case remaining < 24*time.Hour:
+		return formatCompactDuration(remaining)
+	}
+	return fmt.Sprintf("%dd", int(remaining.Hours()/24))
+}
+
+// renderEligibilityEnd describes when an eligibility ends for the detail panels,
+// highlighting it inside the warning window
+func (m Model) renderEligibilityEnd(endsAt *time.Time) string {
+	label := detailLabelStyle.Render("Eligible until: ")
+	if endsAt == nil {
+		return label + detailValueStyle.Render("permanent")
+	}
+	value := fmt.Sprintf("%s (%s)", endsAt.Local().Format("2006-01-02"), formatEligibilityLeft(*endsAt))
+	if azure.EligibilityExpiringWithin(endsAt, m.eligibilityWarnWindow()) {
+		s := label + lipgloss.NewStyle().Foreground(colorCritical).Bold(true).Render(iconEligibilityEnding+" "+value)
+		if hint := keyHint("to request renewal", m.keys.Normal.Renew); hint != "" {
+			s += "\n" + detailDimStyle.Render("  "+hint)
+		}
+		return s
+	}
+	return label + detailValueStyle.Render(value)
+}
+
 // formatCompactDuration formats duration in a compact form like "2h" or "45m"
 func formatCompactDuration(d time.Duration) string {
 	if d < time.Minute {
@@ -1508,47 +2573,44 @@ func spinnerDots(color lipgloss.Color) string {
 	return lipgloss.NewStyle().Foreground(color).Render(chars[idx])
 }
 
-// highlightSearchMatch highlights the search query within the text
-func highlightSearchMatch(text, query string) string {
-	if query == "" {
-		return text
-	}
-
-	lowerText := strings.ToLower(text)
-	lowerQuery := strings.ToLower(query)
-
-	idx := strings.Index(lowerText, lowerQuery)
-	if idx == -1 {
-		return text
-	}
-
-	// Build the highlighted string
-	before := text[:idx]
-	match := text[idx : idx+len(query)]
-	after := text[idx+len(query):]
-
-	highlightStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#000000")).
-		Background(colorExpiring).
-		Bold(true)
-
-	return before + highlightStyle.Render(match) + after
-}
-
+// countActiveItems counts PIM activations; standing assignments are counted separately
 func (m Model) countActiveItems() (roles, groups int) {
 	for _, r := range m.roles {
-		if r.Status.IsActive() {
+		if r.Status.IsActive() && !r.Standing {
 			roles++
 		}
 	}
 	for _, g := range m.groups {
-		if g.Status.IsActive() {
+		if g.Status.IsActive() && !g.Standing {
 			groups++
 		}
 	}
 	return
 }
 
+// countStandingItems counts standing assignments across all tabs
+func (m Model) countStandingItems() int {
+	count := 0
+	for _, r := range m.roles {
+		if r.Standing {
+			count++
+		}
+	}
+	for _, g := range m.groups {
+		if g.Standing {
+			count++
+		}
+	}
+	for _, sub := range m.lighthouse {
+		for _, role := range sub.EligibleRoles {
+			if role.Standing {
+				count++
+			}
+		}
+	}
+	return count
+}
+
 func (m Model) countExpiringItems() int {
 	count := 0
 	for _, r := range m.roles {